@@ -11,7 +11,20 @@ import (
 
 	"github.com/lmittmann/tint"
 	"github.com/scrot/musclemem-api/internal"
+	"github.com/scrot/musclemem-api/internal/body"
+	"github.com/scrot/musclemem-api/internal/catalog"
+	"github.com/scrot/musclemem-api/internal/challenge"
+	"github.com/scrot/musclemem-api/internal/coach"
+	"github.com/scrot/musclemem-api/internal/comment"
+	"github.com/scrot/musclemem-api/internal/equipment"
 	"github.com/scrot/musclemem-api/internal/exercise"
+	"github.com/scrot/musclemem-api/internal/program"
+	"github.com/scrot/musclemem-api/internal/record"
+	"github.com/scrot/musclemem-api/internal/schedule"
+	"github.com/scrot/musclemem-api/internal/session"
+	"github.com/scrot/musclemem-api/internal/share"
+	"github.com/scrot/musclemem-api/internal/social"
+	"github.com/scrot/musclemem-api/internal/stats"
 	"github.com/scrot/musclemem-api/internal/storage"
 	"github.com/scrot/musclemem-api/internal/user"
 	"github.com/scrot/musclemem-api/internal/workout"
@@ -71,8 +84,33 @@ func run(ctx context.Context, getenv func(string) string) error {
 
 	// register service stores
 	us := user.NewSQLUserStore(db)
+	es := equipment.NewSQLEquipmentStore(db)
+	bs := body.NewSQLBodyStore(db)
+	chs := coach.NewSQLCoachStore(db)
 	ws := workout.NewSQLWorkoutStore(db)
-	xs := exercise.NewSQLExerciseStore(db)
+	xs := exercise.NewSQLExerciseStore(db, es)
+	cs := catalog.NewSQLCatalogStore(db)
+	rs := record.NewSQLRecordStore(db)
+	ss := session.NewSQLSessionStore(db, es, bs, rs)
+	sts := stats.NewSQLStatsStore(db)
+	scs := schedule.NewSQLScheduleStore(db)
+	ps := program.NewSQLProgramStore(db, es)
+	shs := share.NewSQLShareStore(db, ws, xs, chs)
+	cms := comment.NewSQLCommentStore(db)
+	sos := social.NewSQLSocialStore(db, rs)
+	cgs := challenge.NewSQLChallengeStore(db)
+
+	// seed the built-in exercise catalog
+	library, err := catalog.Library()
+	if err != nil {
+		l.Error(err.Error())
+		os.Exit(1)
+	}
+
+	if err := cs.Seed(library); err != nil {
+		l.Error(err.Error())
+		os.Exit(1)
+	}
 
 	// configure and start server
 	var cfg internal.ServerConfig
@@ -91,7 +129,7 @@ func run(ctx context.Context, getenv func(string) string) error {
 		}
 	}
 
-	server := internal.NewServer(cfg, l, us, ws, xs)
+	server := internal.NewServer(cfg, l, us, ws, xs, cs, ss, rs, sts, scs, ps, es, bs, shs, chs, cms, sos, cgs)
 	if err != nil {
 		l.Error(err.Error())
 		os.Exit(1)
@@ -106,6 +144,9 @@ func run(ctx context.Context, getenv func(string) string) error {
 		cancel()
 	}()
 
+	// recompute challenge leaderboards in the background
+	go challenge.Refresh(ctx, l, cgs, challenge.RefreshInterval)
+
 	server.Start(ctx)
 
 	return nil
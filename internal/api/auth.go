@@ -1 +1,34 @@
 package api
+
+import (
+	"context"
+	"net/http"
+)
+
+// callerKey is the context key of the authenticated caller
+type callerKey struct{}
+
+// WithCaller returns a copy of ctx containing the username of the authenticated caller
+func WithCaller(ctx context.Context, username string) context.Context {
+	return context.WithValue(ctx, callerKey{}, username)
+}
+
+// Caller returns the username of the authenticated caller of the request,
+// empty if the request is not authenticated
+func Caller(r *http.Request) string {
+	username, _ := r.Context().Value(callerKey{}).(string)
+	return username
+}
+
+// WriteUnauthorizedError writes the error response of a request
+// without valid credentials, challenging the client to authenticate
+func WriteUnauthorizedError(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Basic realm="musclemem", charset="UTF-8"`)
+	http.Error(w, "invalid credentials", http.StatusUnauthorized)
+}
+
+// WriteForbiddenError writes the error response of a request
+// of a caller without access to the resource
+func WriteForbiddenError(w http.ResponseWriter) {
+	http.Error(w, "access denied", http.StatusForbidden)
+}
@@ -0,0 +1,48 @@
+package body
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidFields = errors.New("contains invalid fields")
+)
+
+// BodyStore represents the body measurement repository
+type BodyStore interface {
+	Retreiver
+	Storer
+	Deleter
+}
+
+// Retreiver implementations allow for body entries to be queried
+type Retreiver interface {
+	// ByOwner returns the entries of owner within the query ordered by date
+	ByOwner(owner string, query Query) ([]Entry, error)
+
+	// ByDate returns the entry of owner measured on date
+	ByDate(owner string, date string) (Entry, error)
+
+	// Trend returns the moving averages over window days of the
+	// entries of owner within the query
+	Trend(owner string, query Query, window int) (Trend, error)
+
+	// WeightOn returns the latest body weight of owner measured
+	// on or before date, or 0 if the body weight is unknown
+	WeightOn(owner string, date string) (float64, error)
+}
+
+// Storer implementations allow for body entries to be stored
+type Storer interface {
+	// New stores the entry, measured today if the date is empty,
+	// an entry can only be stored once per date
+	New(owner string, entry Entry) (Entry, error)
+
+	// Replace stores the entry on date replacing an existing entry
+	Replace(owner string, date string, entry Entry) (Entry, error)
+}
+
+// Deleter implementations allow for body entries to be deleted
+type Deleter interface {
+	// Delete deletes the entry of owner measured on date
+	Delete(owner string, date string) (Entry, error)
+}
@@ -0,0 +1,225 @@
+package body
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/exercise"
+)
+
+// DateLayout is the layout of the date an entry is measured on
+const DateLayout = time.DateOnly
+
+const (
+	// DefaultWindow is the default number of days moving averages span
+	DefaultWindow = 7
+
+	// MaxWindow is the maximum number of days moving averages span
+	MaxWindow = 90
+)
+
+// Site is a location on the body the circumference is measured at
+type Site string
+
+const (
+	SiteNeck         Site = "neck"
+	SiteShoulders    Site = "shoulders"
+	SiteChest        Site = "chest"
+	SiteWaist        Site = "waist"
+	SiteHips         Site = "hips"
+	SiteLeftArm      Site = "left_arm"
+	SiteRightArm     Site = "right_arm"
+	SiteLeftForearm  Site = "left_forearm"
+	SiteRightForearm Site = "right_forearm"
+	SiteLeftThigh    Site = "left_thigh"
+	SiteRightThigh   Site = "right_thigh"
+	SiteLeftCalf     Site = "left_calf"
+	SiteRightCalf    Site = "right_calf"
+)
+
+// Sites contains all sites circumferences can be measured at
+var Sites = []Site{
+	SiteNeck, SiteShoulders, SiteChest, SiteWaist, SiteHips,
+	SiteLeftArm, SiteRightArm, SiteLeftForearm, SiteRightForearm,
+	SiteLeftThigh, SiteRightThigh, SiteLeftCalf, SiteRightCalf,
+}
+
+// Entry contains the body measurements of a user on a date, zero values
+// are not measured. Weight is in kilograms, BodyFat a percentage and
+// Measurements the circumference in centimeters of each measured site
+type Entry struct {
+	Owner        string           `json:"owner"`
+	Date         string           `json:"date"`
+	Weight       float64          `json:"weight,omitempty"`
+	BodyFat      float64          `json:"body_fat,omitempty"`
+	Measurements map[Site]float64 `json:"measurements,omitempty"`
+}
+
+func (e Entry) String() string {
+	return fmt.Sprintf("body entry %s/%s: %.1f kg, %.1f%% with %d measurements",
+		e.Owner, e.Date, e.Weight, e.BodyFat, len(e.Measurements))
+}
+
+// Validate checks if the entry contains at least one valid measurement
+func (e Entry) Validate() error {
+	if _, err := time.Parse(DateLayout, e.Date); err != nil {
+		return fmt.Errorf("%w: date %q, expected %s", ErrInvalidFields, e.Date, DateLayout)
+	}
+
+	if e.Weight < 0 || e.Weight > 500 {
+		return fmt.Errorf("%w: weight %.1f, expected 0 to 500", ErrInvalidFields, e.Weight)
+	}
+
+	if e.BodyFat < 0 || e.BodyFat > 75 {
+		return fmt.Errorf("%w: body fat %.1f, expected 0 to 75", ErrInvalidFields, e.BodyFat)
+	}
+
+	for site, c := range e.Measurements {
+		if !site.valid() {
+			return fmt.Errorf("%w: unknown site %q", ErrInvalidFields, site)
+		}
+
+		if c <= 0 || c > 300 {
+			return fmt.Errorf("%w: %s circumference %.1f, expected up to 300", ErrInvalidFields, site, c)
+		}
+	}
+
+	if e.Weight == 0 && e.BodyFat == 0 && len(e.Measurements) == 0 {
+		return fmt.Errorf("%w: entry requires a weight, body fat or measurement", ErrInvalidFields)
+	}
+
+	return nil
+}
+
+func (s Site) valid() bool {
+	for _, site := range Sites {
+		if s == site {
+			return true
+		}
+	}
+	return false
+}
+
+// Query selects the entries of a user between the optional inclusive dates
+type Query struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// Validate checks if the dates of the query are valid
+func (q Query) Validate() error {
+	for _, d := range []string{q.From, q.To} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse(DateLayout, d); err != nil {
+			return fmt.Errorf("%w: date %q, expected %s", ErrInvalidFields, d, DateLayout)
+		}
+	}
+
+	return nil
+}
+
+// Trend contains the moving averages of the body weight and body fat
+// over the preceding Window days of each entry
+type Trend struct {
+	Window int          `json:"window"`
+	Points []TrendPoint `json:"points"`
+}
+
+// TrendPoint contains the measured values of an entry and their
+// moving averages, averages only include measured values
+type TrendPoint struct {
+	Date           string  `json:"date"`
+	Weight         float64 `json:"weight,omitempty"`
+	WeightAverage  float64 `json:"weight_average,omitempty"`
+	BodyFat        float64 `json:"body_fat,omitempty"`
+	BodyFatAverage float64 `json:"body_fat_average,omitempty"`
+}
+
+// MovingAverages returns the trend of the entries, the average of each
+// entry spans the window of days up to and including its date
+func MovingAverages(entries []Entry, window int) (Trend, error) {
+	if window == 0 {
+		window = DefaultWindow
+	}
+
+	if window < 1 || window > MaxWindow {
+		return Trend{}, fmt.Errorf("%w: window %d, expected 1 to %d", ErrInvalidFields, window, MaxWindow)
+	}
+
+	type dated struct {
+		Entry
+		date time.Time
+	}
+
+	ds := make([]dated, 0, len(entries))
+	for _, e := range entries {
+		d, err := time.Parse(DateLayout, e.Date)
+		if err != nil {
+			return Trend{}, fmt.Errorf("%w: date %q", ErrInvalidFields, e.Date)
+		}
+		ds = append(ds, dated{e, d})
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i].date.Before(ds[j].date) })
+
+	trend := Trend{Window: window, Points: []TrendPoint{}}
+	for i, e := range ds {
+		if e.Weight == 0 && e.BodyFat == 0 {
+			continue
+		}
+
+		var (
+			start            = e.date.AddDate(0, 0, -window)
+			weight, fat      float64
+			weights, samples int
+		)
+
+		for j := i; j >= 0 && ds[j].date.After(start); j-- {
+			if ds[j].Weight > 0 {
+				weight += ds[j].Weight
+				weights++
+			}
+			if ds[j].BodyFat > 0 {
+				fat += ds[j].BodyFat
+				samples++
+			}
+		}
+
+		p := TrendPoint{Date: e.Date, Weight: e.Weight, BodyFat: e.BodyFat}
+		if weights > 0 {
+			p.WeightAverage = round(weight / float64(weights))
+		}
+		if samples > 0 {
+			p.BodyFatAverage = round(fat / float64(samples))
+		}
+
+		trend.Points = append(trend.Points, p)
+	}
+
+	return trend, nil
+}
+
+// Load returns the load moved per repetition of a set of an exercise
+// of kind, the weight is added to the body weight for bodyweight
+// exercises and subtracted from it for assisted exercises. Without
+// a known body weight only the weight of the set is moved
+func Load(kind exercise.Kind, weight float64, bodyWeight float64) float64 {
+	switch kind {
+	case exercise.KindBodyweight:
+		return bodyWeight + weight
+	case exercise.KindAssisted:
+		if bodyWeight == 0 {
+			return weight
+		}
+		return max(bodyWeight-weight, 0)
+	default:
+		return weight
+	}
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,89 @@
+package body
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scrot/musclemem-api/internal/exercise"
+)
+
+func TestMovingAverages(t *testing.T) {
+	entries := []Entry{
+		{Date: "2026-10-05", Weight: 80, BodyFat: 20},
+		{Date: "2026-10-01", Weight: 82},
+		{Date: "2026-10-03", Measurements: map[Site]float64{SiteWaist: 85}},
+		{Date: "2026-10-09", Weight: 79, BodyFat: 18},
+	}
+
+	trend, err := MovingAverages(entries, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []TrendPoint{
+		{Date: "2026-10-01", Weight: 82, WeightAverage: 82},
+		{Date: "2026-10-05", Weight: 80, WeightAverage: 81, BodyFat: 20, BodyFatAverage: 20},
+		{Date: "2026-10-09", Weight: 79, WeightAverage: 79.5, BodyFat: 18, BodyFatAverage: 19},
+	}
+
+	if len(trend.Points) != len(want) {
+		t.Fatalf("want %d points but got %v", len(want), trend.Points)
+	}
+
+	for i, p := range trend.Points {
+		if p != want[i] {
+			t.Errorf("want %+v but got %+v", want[i], p)
+		}
+	}
+
+	if _, err := MovingAverages(entries, MaxWindow+1); !errors.Is(err, ErrInvalidFields) {
+		t.Errorf("want invalid window error but got %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	cs := []struct {
+		name       string
+		kind       exercise.Kind
+		weight     float64
+		bodyWeight float64
+		want       float64
+	}{
+		{"weighted", exercise.KindWeighted, 100, 80, 100},
+		{"bodyweight", exercise.KindBodyweight, 10, 80, 90},
+		{"assisted", exercise.KindAssisted, 30, 80, 50},
+		{"overassisted", exercise.KindAssisted, 90, 80, 0},
+		{"unknownBodyWeight", exercise.KindAssisted, 30, 0, 30},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			if got := Load(c.kind, c.weight, c.bodyWeight); got != c.want {
+				t.Errorf("want %.1f but got %.1f", c.want, got)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cs := []struct {
+		name  string
+		entry Entry
+		valid bool
+	}{
+		{"weight", Entry{Date: "2026-10-19", Weight: 80}, true},
+		{"measurement", Entry{Date: "2026-10-19", Measurements: map[Site]float64{SiteChest: 100}}, true},
+		{"empty", Entry{Date: "2026-10-19"}, false},
+		{"date", Entry{Date: "19-10-2026", Weight: 80}, false},
+		{"bodyFat", Entry{Date: "2026-10-19", BodyFat: 90}, false},
+		{"site", Entry{Date: "2026-10-19", Measurements: map[Site]float64{"nose": 5}}, false},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.entry.Validate(); (err == nil) != c.valid {
+				t.Errorf("want valid %t but got %v", c.valid, err)
+			}
+		})
+	}
+}
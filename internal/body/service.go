@@ -0,0 +1,203 @@
+package body
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/scrot/musclemem-api/internal/api"
+)
+
+// NewFetchAllHandler returns the body entries of a user ordered by date
+// requires {username} path variable, optional from and to query parameters
+func NewFetchAllHandler(l *slog.Logger, bodies Retreiver) http.Handler {
+	l = l.With("handler", "FetchAllHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			params   = r.URL.Query()
+			query    = Query{From: params.Get("from"), To: params.Get("to")}
+		)
+
+		l := l.With("user", username)
+
+		es, err := bodies.ByOwner(username, query)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched body entries", "count", len(es))
+
+		if err := api.WriteJSON(w, http.StatusOK, es); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewFetchHandler returns the body entry of a user measured on a date
+// requires {username} and {date} path variables
+func NewFetchHandler(l *slog.Logger, bodies Retreiver) http.Handler {
+	l = l.With("handler", "FetchHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			date     = r.PathValue("date")
+		)
+
+		l := l.With("user", username, "date", date)
+
+		e, err := bodies.ByDate(username, date)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("fetched %s", e))
+
+		if err := api.WriteJSON(w, http.StatusOK, e); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewCreateHandler stores a body entry of a user, measured today by default
+// requires {username} path variable
+// requires json payload {"date": DATE, "weight": KG, "body_fat": PERCENT, "measurements": {SITE: CM}}
+func NewCreateHandler(l *slog.Logger, bodies Storer) http.Handler {
+	l = l.With("handler", "CreateHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		e, err := api.ReadJSON[Entry](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		created, err := bodies.New(username, e)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s created", created))
+
+		if err := api.WriteJSON(w, http.StatusOK, created); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewReplaceHandler stores the body entry of a user on a date
+// replacing the existing entry
+// requires {username} and {date} path variables
+// requires json payload {"weight": KG, "body_fat": PERCENT, "measurements": {SITE: CM}}
+func NewReplaceHandler(l *slog.Logger, bodies Storer) http.Handler {
+	l = l.With("handler", "ReplaceHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			date     = r.PathValue("date")
+		)
+
+		l := l.With("user", username, "date", date)
+
+		e, err := api.ReadJSON[Entry](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		replaced, err := bodies.Replace(username, date, e)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s replaced", replaced))
+
+		if err := api.WriteJSON(w, http.StatusOK, replaced); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewDeleteHandler deletes the body entry of a user measured on a date
+// requires {username} and {date} path variables
+func NewDeleteHandler(l *slog.Logger, bodies Deleter) http.Handler {
+	l = l.With("handler", "DeleteHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			date     = r.PathValue("date")
+		)
+
+		l := l.With("user", username, "date", date)
+
+		deleted, err := bodies.Delete(username, date)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s deleted", deleted))
+
+		if err := api.WriteJSON(w, http.StatusOK, deleted); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewTrendHandler returns the moving averages of the body weight and
+// body fat of a user
+// requires {username} path variable, optional from, to and window
+// query parameters, window is the number of days averaged, 7 by default
+func NewTrendHandler(l *slog.Logger, bodies Retreiver) http.Handler {
+	l = l.With("handler", "TrendHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			params   = r.URL.Query()
+			query    = Query{From: params.Get("from"), To: params.Get("to")}
+			window   int
+		)
+
+		l := l.With("user", username, "window", params.Get("window"))
+
+		if v := params.Get("window"); v != "" {
+			var err error
+			if window, err = strconv.Atoi(v); err != nil {
+				api.WriteInternalError(l, w, err, "invalid window")
+				return
+			}
+		}
+
+		t, err := bodies.Trend(username, query, window)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("calculated body trend", "count", len(t.Points))
+
+		if err := api.WriteJSON(w, http.StatusOK, t); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
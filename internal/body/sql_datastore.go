@@ -0,0 +1,378 @@
+package body
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/exercise"
+	"github.com/scrot/musclemem-api/internal/storage"
+)
+
+type SQLBodyStore struct {
+	*storage.SqlDatastore
+
+	// now returns the current time, replaceable for testing
+	now func() time.Time
+}
+
+func NewSQLBodyStore(db *storage.SqlDatastore) *SQLBodyStore {
+	return &SQLBodyStore{db, time.Now}
+}
+
+// WeightExpr returns the SQL expression of the latest body weight measured
+// on or before date by owner, 0 if unknown. The owner and date are SQL
+// expressions, e.g. the columns of the session a set is logged in
+func WeightExpr(owner string, date string) string {
+	return fmt.Sprintf(`COALESCE((
+    SELECT b.weight FROM body_entries b
+    WHERE b.owner = %s AND b.measured_on <= %s AND b.weight > 0
+    ORDER BY b.measured_on DESC LIMIT 1), 0)`, owner, date)
+}
+
+// LoadExpr returns the SQL expression equivalent of Load for the SQL
+// expressions of the exercise kind, set weight and body weight
+func LoadExpr(kind string, weight string, bodyWeight string) string {
+	return fmt.Sprintf(`CASE
+    WHEN %[1]s = '%[4]s' THEN %[3]s + %[2]s
+    WHEN %[1]s = '%[5]s' AND %[3]s > 0 THEN CASE WHEN %[3]s > %[2]s THEN %[3]s - %[2]s ELSE 0 END
+    ELSE %[2]s END`, kind, weight, bodyWeight, exercise.KindBodyweight, exercise.KindAssisted)
+}
+
+func (bs *SQLBodyStore) ByOwner(owner string, query Query) ([]Entry, error) {
+	const (
+		entriesStmt = `
+    SELECT owner, measured_on, weight, body_fat
+    FROM body_entries
+    WHERE owner = {{ .Owner }}
+      {{ if .From }}AND measured_on >= {{ .From }}{{ end }}
+      {{ if .To }}AND measured_on <= {{ .To }}{{ end }}
+    ORDER BY measured_on
+    `
+
+		measurementsStmt = `
+    SELECT measured_on, site, circumference
+    FROM body_measurements
+    WHERE owner = {{ .Owner }}
+      {{ if .From }}AND measured_on >= {{ .From }}{{ end }}
+      {{ if .To }}AND measured_on <= {{ .To }}{{ end }}
+    `
+	)
+
+	if owner == "" {
+		return []Entry{}, fmt.Errorf("ByOwner: %w", ErrInvalidFields)
+	}
+
+	if err := query.Validate(); err != nil {
+		return []Entry{}, fmt.Errorf("ByOwner: %w", err)
+	}
+
+	data := struct {
+		Owner string
+		Query
+	}{owner, query}
+
+	entries, err := bs.query(entriesStmt, measurementsStmt, data)
+	if err != nil {
+		return []Entry{}, fmt.Errorf("ByOwner: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (bs *SQLBodyStore) ByDate(owner string, date string) (Entry, error) {
+	if owner == "" || date == "" {
+		return Entry{}, fmt.Errorf("ByDate: %w", ErrInvalidFields)
+	}
+
+	entries, err := bs.ByOwner(owner, Query{From: date, To: date})
+	if err != nil {
+		return Entry{}, fmt.Errorf("ByDate: %w", err)
+	}
+
+	if len(entries) == 0 {
+		return Entry{}, fmt.Errorf("ByDate: entry %s/%s: %w", owner, date, ErrNotFound)
+	}
+
+	return entries[0], nil
+}
+
+func (bs *SQLBodyStore) Trend(owner string, query Query, window int) (Trend, error) {
+	if err := query.Validate(); err != nil {
+		return Trend{}, fmt.Errorf("Trend: %w", err)
+	}
+
+	if window == 0 {
+		window = DefaultWindow
+	}
+
+	// include the entries the averages of the first day span
+	from := query
+	if query.From != "" {
+		d, _ := time.Parse(DateLayout, query.From)
+		from.From = d.AddDate(0, 0, -window).Format(DateLayout)
+	}
+
+	entries, err := bs.ByOwner(owner, from)
+	if err != nil {
+		return Trend{}, fmt.Errorf("Trend: %w", err)
+	}
+
+	trend, err := MovingAverages(entries, window)
+	if err != nil {
+		return Trend{}, fmt.Errorf("Trend: %w", err)
+	}
+
+	points := trend.Points[:0]
+	for _, p := range trend.Points {
+		if p.Date >= query.From {
+			points = append(points, p)
+		}
+	}
+	trend.Points = points
+
+	return trend, nil
+}
+
+func (bs *SQLBodyStore) WeightOn(owner string, date string) (float64, error) {
+	if owner == "" {
+		return 0, fmt.Errorf("WeightOn: %w", ErrInvalidFields)
+	}
+
+	if _, err := time.Parse(DateLayout, date); err != nil {
+		return 0, fmt.Errorf("WeightOn: %w: date %q, expected %s", ErrInvalidFields, date, DateLayout)
+	}
+
+	data := struct {
+		Owner string
+		Date  string
+	}{owner, date}
+
+	q, args, err := bs.CompileStatement("SELECT "+WeightExpr("{{ .Owner }}", "{{ .Date }}"), data)
+	if err != nil {
+		return 0, fmt.Errorf("WeightOn: compile: %w", err)
+	}
+
+	var weight float64
+	if err := bs.QueryRow(q, args...).Scan(&weight); err != nil {
+		return 0, fmt.Errorf("WeightOn: query: %w", err)
+	}
+
+	return weight, nil
+}
+
+func (bs *SQLBodyStore) New(owner string, entry Entry) (Entry, error) {
+	if owner == "" {
+		return Entry{}, fmt.Errorf("New: %w", ErrInvalidFields)
+	}
+
+	if entry.Date == "" {
+		entry.Date = bs.now().UTC().Format(DateLayout)
+	}
+
+	if _, err := bs.ByDate(owner, entry.Date); err == nil {
+		return Entry{}, fmt.Errorf("New: entry %s/%s already exists: %w", owner, entry.Date, ErrInvalidFields)
+	} else if !errors.Is(err, ErrNotFound) {
+		return Entry{}, fmt.Errorf("New: %w", err)
+	}
+
+	e, err := bs.store(owner, entry)
+	if err != nil {
+		return Entry{}, fmt.Errorf("New: %w", err)
+	}
+
+	return e, nil
+}
+
+func (bs *SQLBodyStore) Replace(owner string, date string, entry Entry) (Entry, error) {
+	if owner == "" || date == "" {
+		return Entry{}, fmt.Errorf("Replace: %w", ErrInvalidFields)
+	}
+
+	entry.Date = date
+	e, err := bs.store(owner, entry)
+	if err != nil {
+		return Entry{}, fmt.Errorf("Replace: %w", err)
+	}
+
+	return e, nil
+}
+
+func (bs *SQLBodyStore) Delete(owner string, date string) (Entry, error) {
+	e, err := bs.ByDate(owner, date)
+	if err != nil {
+		return Entry{}, fmt.Errorf("Delete: %w", err)
+	}
+
+	tx, err := bs.Begin()
+	if err != nil {
+		return Entry{}, fmt.Errorf("Delete: begin transaction: %w", err)
+	}
+
+	for _, stmt := range clearStmts {
+		q, args, err := bs.CompileStatement(stmt, e)
+		if err != nil {
+			tx.Rollback()
+			return Entry{}, fmt.Errorf("Delete: compile: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			tx.Rollback()
+			return Entry{}, fmt.Errorf("Delete: execute: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Entry{}, fmt.Errorf("Delete: commit transaction: %w", err)
+	}
+
+	return e, nil
+}
+
+// clearStmts delete an entry, its measurements first
+var clearStmts = []string{
+	`DELETE FROM body_measurements WHERE owner = {{ .Owner }} AND measured_on = {{ .Date }}`,
+	`DELETE FROM body_entries WHERE owner = {{ .Owner }} AND measured_on = {{ .Date }}`,
+}
+
+// store validates and stores the entry replacing an existing entry on its date
+func (bs *SQLBodyStore) store(owner string, entry Entry) (Entry, error) {
+	const (
+		entryStmt = `
+    INSERT INTO body_entries (owner, measured_on, weight, body_fat)
+    VALUES ({{ .Owner }}, {{ .Date }}, {{ .Weight }}, {{ .BodyFat }})
+    `
+
+		measurementStmt = `
+    INSERT INTO body_measurements (owner, measured_on, site, circumference)
+    VALUES ({{ .Owner }}, {{ .Date }}, {{ .Site }}, {{ .Circumference }})
+    `
+	)
+
+	entry.Owner = owner
+	if err := entry.Validate(); err != nil {
+		return Entry{}, err
+	}
+
+	tx, err := bs.Begin()
+	if err != nil {
+		return Entry{}, fmt.Errorf("begin transaction: %w", err)
+	}
+
+	exec := func(stmt string, data any) error {
+		q, args, err := bs.CompileStatement(stmt, data)
+		if err != nil {
+			return fmt.Errorf("compile: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			return fmt.Errorf("execute: %w", err)
+		}
+
+		return nil
+	}
+
+	for _, stmt := range clearStmts {
+		if err := exec(stmt, entry); err != nil {
+			tx.Rollback()
+			return Entry{}, fmt.Errorf("clear: %w", err)
+		}
+	}
+
+	if err := exec(entryStmt, entry); err != nil {
+		tx.Rollback()
+		return Entry{}, fmt.Errorf("entry: %w", err)
+	}
+
+	for site, c := range entry.Measurements {
+		data := struct {
+			Owner         string
+			Date          string
+			Site          Site
+			Circumference float64
+		}{owner, entry.Date, site, c}
+
+		if err := exec(measurementStmt, data); err != nil {
+			tx.Rollback()
+			return Entry{}, fmt.Errorf("measurement %s: %w", site, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Entry{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return entry, nil
+}
+
+// query returns the entries of entriesStmt including the
+// measurements of measurementsStmt, both compiled with data
+func (bs *SQLBodyStore) query(entriesStmt string, measurementsStmt string, data any) ([]Entry, error) {
+	q, args, err := bs.CompileStatement(entriesStmt, data)
+	if err != nil {
+		return nil, fmt.Errorf("compile: %w", err)
+	}
+
+	rows, err := bs.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		entries = []Entry{}
+		dates   = make(map[string]int)
+	)
+
+	for rows.Next() {
+		var e Entry
+		if err := rows.Scan(&e.Owner, &e.Date, &e.Weight, &e.BodyFat); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		dates[e.Date] = len(entries)
+		entries = append(entries, e)
+	}
+
+	if err := rows.Close(); err != nil {
+		return nil, fmt.Errorf("close: %w", err)
+	}
+
+	q, args, err = bs.CompileStatement(measurementsStmt, data)
+	if err != nil {
+		return nil, fmt.Errorf("compile measurements: %w", err)
+	}
+
+	rows, err = bs.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query measurements: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			date string
+			site Site
+			c    float64
+		)
+
+		if err := rows.Scan(&date, &site, &c); err != nil {
+			return nil, fmt.Errorf("scan measurement: %w", err)
+		}
+
+		i, ok := dates[date]
+		if !ok {
+			continue
+		}
+
+		if entries[i].Measurements == nil {
+			entries[i].Measurements = make(map[Site]float64)
+		}
+		entries[i].Measurements[site] = c
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,171 @@
+package body
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/exercise"
+	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/user"
+)
+
+func TestNewEntry(t *testing.T) {
+	bodies, flush := mockBodyStore(t)
+	defer flush()
+
+	e := Entry{Weight: 80.5, BodyFat: 18, Measurements: map[Site]float64{SiteWaist: 84, SiteLeftArm: 38}}
+	created, err := bodies.New("user", e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created.Date != "2026-10-19" {
+		t.Errorf("want entry measured today but got %s", created.Date)
+	}
+
+	if _, err := bodies.New("user", e); !errors.Is(err, ErrInvalidFields) {
+		t.Errorf("want error storing a second entry on the same date but got %v", err)
+	}
+
+	got, err := bodies.ByDate("user", "2026-10-19")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Weight != 80.5 || got.BodyFat != 18 || len(got.Measurements) != 2 || got.Measurements[SiteWaist] != 84 {
+		t.Errorf("want %s but got %s", created, got)
+	}
+
+	replaced, err := bodies.Replace("user", "2026-10-19", Entry{Weight: 80})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := bodies.ByDate("user", "2026-10-19"); got.Weight != 80 || len(got.Measurements) != 0 {
+		t.Errorf("want %s but got %s", replaced, got)
+	}
+
+	if _, err := bodies.Delete("user", "2026-10-19"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := bodies.ByDate("user", "2026-10-19"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want not found error after delete but got %v", err)
+	}
+}
+
+func TestWeightOn(t *testing.T) {
+	bodies, flush := mockBodyStore(t)
+	defer flush()
+
+	for _, e := range []Entry{
+		{Date: "2026-10-01", Weight: 82},
+		{Date: "2026-10-05", Measurements: map[Site]float64{SiteWaist: 85}},
+		{Date: "2026-10-10", Weight: 80},
+	} {
+		if _, err := bodies.New("user", e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cs := []struct {
+		date string
+		want float64
+	}{
+		{"2026-09-30", 0},
+		{"2026-10-01", 82},
+		{"2026-10-07", 82},
+		{"2026-10-19", 80},
+	}
+
+	for _, c := range cs {
+		got, err := bodies.WeightOn("user", c.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != c.want {
+			t.Errorf("%s: want %.1f but got %.1f", c.date, c.want, got)
+		}
+	}
+
+	trend, err := bodies.Trend("user", Query{From: "2026-10-05"}, 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(trend.Points) != 1 || trend.Points[0].WeightAverage != 81 {
+		t.Errorf("want a single point averaging 81 but got %v", trend.Points)
+	}
+}
+
+func TestLoadExpr(t *testing.T) {
+	bodies, flush := mockBodyStore(t)
+	defer flush()
+
+	if _, err := bodies.New("user", Entry{Date: "2026-10-01", Weight: 80}); err != nil {
+		t.Fatal(err)
+	}
+
+	cs := []struct {
+		kind exercise.Kind
+		want float64
+	}{
+		{exercise.KindWeighted, 30},
+		{exercise.KindBodyweight, 110},
+		{exercise.KindAssisted, 50},
+	}
+
+	for _, c := range cs {
+		data := struct {
+			Owner string
+			Kind  exercise.Kind
+		}{"user", c.kind}
+
+		stmt := "SELECT " + LoadExpr("{{ .Kind }}", "30", WeightExpr("{{ .Owner }}", "'2026-10-19'"))
+		q, args, err := bodies.CompileStatement(stmt, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got float64
+		if err := bodies.QueryRow(q, args...).Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+
+		if got != c.want {
+			t.Errorf("%s: want %.1f but got %.1f", c.kind, c.want, got)
+		}
+	}
+}
+
+func mockBodyStore(t *testing.T) (*SQLBodyStore, func()) {
+	t.Helper()
+
+	config := storage.DatastoreConfig{
+		DatabaseURL:   "file://test.db?cache=shared&mode=memory",
+		MigrationPath: "migrations",
+		Overwrite:     false,
+	}
+
+	store, err := storage.NewSqlDatastore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := user.NewSQLUserStore(store).New("user", "test@gmail.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	flush := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal()
+		}
+	}
+
+	bodies := NewSQLBodyStore(store)
+	bodies.now = func() time.Time { return time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC) }
+
+	return bodies, flush
+}
@@ -0,0 +1,54 @@
+package catalog
+
+import (
+	"errors"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidFields = errors.New("contains invalid fields")
+	ErrInUse         = errors.New("referenced by exercises")
+)
+
+// CatalogStore represents the exercise catalog repository
+type CatalogStore interface {
+	Retreiver
+	Storer
+	Deleter
+	Seeder
+}
+
+// Retreiver implementations allow for catalog entries to be queried
+type Retreiver interface {
+	// ByRef returns the entry the reference points to
+	ByRef(ref EntryRef) (Entry, error)
+
+	// Available returns the built-in entries together
+	// with the custom entries of owner sorted by name
+	Available(owner string) ([]Entry, error)
+
+	// Search returns the built-in and custom entries of owner that match
+	// the filters in query, ranked by how well they match the query text.
+	// An empty owner searches the built-in entries only
+	Search(owner string, query Query) (Results, error)
+}
+
+// Storer implementations allow for custom entries to be created
+type Storer interface {
+	// New creates a custom entry for owner, if the slug
+	// is empty it is derived from the name
+	New(owner string, e Entry) (Entry, error)
+}
+
+// Deleter implementations allow for custom entries to be deleted
+type Deleter interface {
+	// Delete deletes a custom entry of owner, returns an ErrInUse
+	// error if the entry is still referenced by exercises
+	Delete(owner string, slug string) (Entry, error)
+}
+
+// Seeder implementations allow for the built-in library to be loaded
+type Seeder interface {
+	// Seed inserts or updates the given built-in entries
+	Seed(entries []Entry) error
+}
@@ -0,0 +1,20 @@
+package catalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+//go:embed library.json
+var library []byte
+
+// Library returns the built-in catalog entries
+// used to seed the catalog on startup
+func Library() ([]Entry, error) {
+	var es []Entry
+	if err := json.Unmarshal(library, &es); err != nil {
+		return []Entry{}, fmt.Errorf("Library: decode: %w", err)
+	}
+	return es, nil
+}
@@ -0,0 +1,248 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Entry is the canonical definition of an exercise, built-in entries
+// have no owner while custom entries belong to the user that created them
+type Entry struct {
+	Owner     string    `json:"owner,omitempty"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	Primary   []Muscle  `json:"primary_muscles"`
+	Secondary []Muscle  `json:"secondary_muscles"`
+	Equipment Equipment `json:"equipment"`
+	Pattern   Pattern   `json:"pattern"`
+	Aliases   []string  `json:"aliases"`
+}
+
+func (e Entry) String() string {
+	return fmt.Sprintf("catalog entry %s: %s", e.Ref(), e.Name)
+}
+
+// Ref returns the reference used by exercises to refer to this entry
+func (e Entry) Ref() EntryRef {
+	return EntryRef{e.Owner, e.Slug}
+}
+
+// Builtin reports whether the entry is part of the built-in library
+func (e Entry) Builtin() bool {
+	return e.Owner == ""
+}
+
+// Muscles returns the primary followed by the secondary muscles
+func (e Entry) Muscles() []Muscle {
+	ms := make([]Muscle, 0, len(e.Primary)+len(e.Secondary))
+	ms = append(ms, e.Primary...)
+	return append(ms, e.Secondary...)
+}
+
+// Validate checks if the entry contains the required fields
+// and only known muscles, equipment and movement patterns
+func (e Entry) Validate() error {
+	if e.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidFields)
+	}
+
+	if !validSlug(e.Slug) {
+		return fmt.Errorf("%w: slug %q, expected lowercase letters, digits and dashes", ErrInvalidFields, e.Slug)
+	}
+
+	if len(e.Primary) == 0 {
+		return fmt.Errorf("%w: at least one primary muscle is required", ErrInvalidFields)
+	}
+
+	seen := make(map[Muscle]bool)
+	for _, m := range e.Muscles() {
+		if !m.valid() {
+			return fmt.Errorf("%w: unknown muscle %q", ErrInvalidFields, m)
+		}
+		if seen[m] {
+			return fmt.Errorf("%w: muscle %q listed twice", ErrInvalidFields, m)
+		}
+		seen[m] = true
+	}
+
+	if !e.Equipment.valid() {
+		return fmt.Errorf("%w: unknown equipment %q", ErrInvalidFields, e.Equipment)
+	}
+
+	if !e.Pattern.valid() {
+		return fmt.Errorf("%w: unknown movement pattern %q", ErrInvalidFields, e.Pattern)
+	}
+
+	for _, a := range e.Aliases {
+		if strings.TrimSpace(a) == "" {
+			return fmt.Errorf("%w: empty alias", ErrInvalidFields)
+		}
+	}
+
+	return nil
+}
+
+// EntryRef represents the unique key that references an Entry,
+// built-in entries are referenced by their slug only
+type EntryRef struct {
+	Owner string
+	Slug  string
+}
+
+func (er EntryRef) String() string {
+	if er.Owner == "" {
+		return er.Slug
+	}
+	return fmt.Sprintf("%s/%s", er.Owner, er.Slug)
+}
+
+// ParseRef parses {slug} for built-in and {username}/{slug} for custom entries
+func ParseRef(s string) (EntryRef, error) {
+	ss := strings.Split(s, "/")
+
+	switch {
+	case len(ss) == 1 && ss[0] != "":
+		return EntryRef{Slug: ss[0]}, nil
+	case len(ss) == 2 && ss[0] != "" && ss[1] != "":
+		return EntryRef{Owner: ss[0], Slug: ss[1]}, nil
+	default:
+		return EntryRef{}, errors.New("invalid ref expected {slug} or {username}/{slug}")
+	}
+}
+
+// Slugify turns a name into a slug, e.g. "Barbell Bench Press" into "barbell-bench-press"
+func Slugify(name string) string {
+	var (
+		b    strings.Builder
+		dash bool
+	)
+
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if dash && b.Len() > 0 {
+				b.WriteRune('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+
+	return b.String()
+}
+
+func validSlug(slug string) bool {
+	return slug != "" && Slugify(slug) == slug
+}
+
+// Muscle is a muscle group trained by an exercise
+type Muscle string
+
+const (
+	MuscleChest      Muscle = "chest"
+	MuscleLats       Muscle = "lats"
+	MuscleUpperBack  Muscle = "upper_back"
+	MuscleTraps      Muscle = "traps"
+	MuscleLowerBack  Muscle = "lower_back"
+	MuscleShoulders  Muscle = "shoulders"
+	MuscleRearDelts  Muscle = "rear_delts"
+	MuscleBiceps     Muscle = "biceps"
+	MuscleTriceps    Muscle = "triceps"
+	MuscleForearms   Muscle = "forearms"
+	MuscleAbs        Muscle = "abs"
+	MuscleObliques   Muscle = "obliques"
+	MuscleGlutes     Muscle = "glutes"
+	MuscleQuads      Muscle = "quads"
+	MuscleHamstrings Muscle = "hamstrings"
+	MuscleAdductors  Muscle = "adductors"
+	MuscleCalves     Muscle = "calves"
+	MuscleCardio     Muscle = "cardio"
+)
+
+// Muscles contains all known muscle groups
+var Muscles = []Muscle{
+	MuscleChest, MuscleLats, MuscleUpperBack, MuscleTraps, MuscleLowerBack,
+	MuscleShoulders, MuscleRearDelts, MuscleBiceps, MuscleTriceps, MuscleForearms,
+	MuscleAbs, MuscleObliques, MuscleGlutes, MuscleQuads, MuscleHamstrings,
+	MuscleAdductors, MuscleCalves, MuscleCardio,
+}
+
+func (m Muscle) valid() bool {
+	for _, known := range Muscles {
+		if m == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Equipment is the primary equipment required to perform an exercise
+type Equipment string
+
+const (
+	EquipmentBarbell    Equipment = "barbell"
+	EquipmentDumbbell   Equipment = "dumbbell"
+	EquipmentKettlebell Equipment = "kettlebell"
+	EquipmentMachine    Equipment = "machine"
+	EquipmentCable      Equipment = "cable"
+	EquipmentBodyweight Equipment = "bodyweight"
+	EquipmentBand       Equipment = "band"
+	EquipmentEZBar      Equipment = "ez_bar"
+	EquipmentTrapBar    Equipment = "trap_bar"
+	EquipmentSmith      Equipment = "smith_machine"
+	EquipmentCardio     Equipment = "cardio_machine"
+	EquipmentOther      Equipment = "other"
+)
+
+// Equipments contains all known equipment
+var Equipments = []Equipment{
+	EquipmentBarbell, EquipmentDumbbell, EquipmentKettlebell, EquipmentMachine,
+	EquipmentCable, EquipmentBodyweight, EquipmentBand, EquipmentEZBar,
+	EquipmentTrapBar, EquipmentSmith, EquipmentCardio, EquipmentOther,
+}
+
+func (e Equipment) valid() bool {
+	for _, known := range Equipments {
+		if e == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Pattern is the movement pattern of an exercise
+type Pattern string
+
+const (
+	PatternSquat          Pattern = "squat"
+	PatternHinge          Pattern = "hinge"
+	PatternLunge          Pattern = "lunge"
+	PatternHorizontalPush Pattern = "horizontal_push"
+	PatternVerticalPush   Pattern = "vertical_push"
+	PatternHorizontalPull Pattern = "horizontal_pull"
+	PatternVerticalPull   Pattern = "vertical_pull"
+	PatternCarry          Pattern = "carry"
+	PatternCore           Pattern = "core"
+	PatternIsolation      Pattern = "isolation"
+	PatternCardio         Pattern = "cardio"
+)
+
+// Patterns contains all known movement patterns
+var Patterns = []Pattern{
+	PatternSquat, PatternHinge, PatternLunge, PatternHorizontalPush,
+	PatternVerticalPush, PatternHorizontalPull, PatternVerticalPull,
+	PatternCarry, PatternCore, PatternIsolation, PatternCardio,
+}
+
+func (p Pattern) valid() bool {
+	for _, known := range Patterns {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,251 @@
+package catalog
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+
+	// candidatePrefix is the number of leading characters of a query token
+	// a name or alias token needs to share to be ranked, typos after the
+	// prefix are still found by rank
+	candidatePrefix = 2
+)
+
+// Query contains the search text and filters used to search the catalog
+type Query struct {
+	Text      string
+	Muscle    Muscle
+	Equipment Equipment
+	Pattern   Pattern
+	Limit     int
+	Offset    int
+}
+
+// Results is a page of ranked search results
+type Results struct {
+	Results []Result `json:"results"`
+	Total   int      `json:"total"`
+	Limit   int      `json:"limit"`
+	Offset  int      `json:"offset"`
+}
+
+// Result is an entry matching the search text, Match contains the
+// name or alias that matched best and Score how well it matched
+type Result struct {
+	Entry
+	Match string  `json:"match,omitempty"`
+	Score float64 `json:"score"`
+}
+
+// rank scores the entries against the search text dropping the entries
+// that don't match, the best matches come first. If the text is empty
+// all entries are returned sorted by name
+func rank(es []Entry, text string) []Result {
+	query := tokenize(text)
+
+	rs := make([]Result, 0, len(es))
+	for _, e := range es {
+		if len(query) == 0 {
+			rs = append(rs, Result{Entry: e})
+			continue
+		}
+
+		best := Result{Entry: e}
+		for i, candidate := range append([]string{e.Name}, e.Aliases...) {
+			score := similarity(query, tokenize(candidate))
+
+			// prefer the canonical name over aliases
+			if i > 0 {
+				score *= 0.95
+			}
+
+			if score > best.Score {
+				best.Score = score
+				best.Match = candidate
+			}
+		}
+
+		if best.Score > 0 {
+			rs = append(rs, best)
+		}
+	}
+
+	sort.SliceStable(rs, func(i, j int) bool {
+		if rs[i].Score != rs[j].Score {
+			return rs[i].Score > rs[j].Score
+		}
+		return rs[i].Name < rs[j].Name
+	})
+
+	return rs
+}
+
+// similarity returns a score between 0 and 1 of how well the candidate
+// matches the query, every query token needs to match a candidate token
+func similarity(query, candidate []string) float64 {
+	if len(candidate) == 0 {
+		return 0
+	}
+
+	q, c := strings.Join(query, " "), strings.Join(candidate, " ")
+	switch {
+	case q == c:
+		return 1
+	case strings.HasPrefix(c, q):
+		return 0.9
+	case strings.Contains(c, q):
+		return 0.8
+	}
+
+	var total float64
+	for i, qt := range query {
+		// the last token might still being typed
+		partial := i == len(query)-1
+
+		var best float64
+		for _, ct := range candidate {
+			if s := tokenSimilarity(qt, ct, partial); s > best {
+				best = s
+			}
+		}
+
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+
+	// fewer unmatched candidate tokens rank higher
+	coverage := float64(len(query)) / float64(max(len(query), len(candidate)))
+
+	return 0.7 * (total / float64(len(query))) * (0.8 + 0.2*coverage)
+}
+
+// tokenSimilarity compares two tokens allowing for typos,
+// short tokens allow for less typos than long ones
+func tokenSimilarity(q, c string, partial bool) float64 {
+	if q == c {
+		return 1
+	}
+
+	if partial && strings.HasPrefix(c, q) {
+		return 0.95
+	}
+
+	allowed := 1
+	switch {
+	case len(q) <= 2:
+		allowed = 0
+	case len(q) >= 6:
+		allowed = 2
+	}
+
+	// compare the typed prefix when the token is still being typed
+	if partial && len(c) > len(q) {
+		if d := distance(q, c[:len(q)]); d <= allowed {
+			return 0.85 - 0.1*float64(d)
+		}
+	}
+
+	d := distance(q, c)
+	if d > allowed {
+		return 0
+	}
+
+	return 1 - float64(d)/float64(max(len(q), len(c)))
+}
+
+// distance returns the optimal string alignment distance between a and b,
+// the number of insertions, deletions, substitutions and transpositions
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(ra)][len(rb)]
+}
+
+// tokenize lowercases s and splits it into words, ignoring punctuation
+// so that "Pull-up" and "pullup" become comparable
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// document returns the searchable text of an entry, the tokens of the name
+// and aliases separated and surrounded by spaces so that every token start
+// can be matched with LIKE '% token%'
+func document(e Entry) string {
+	tokens := tokenize(e.Name)
+	for _, a := range e.Aliases {
+		tokens = append(tokens, tokenize(a)...)
+	}
+
+	return " " + strings.Join(tokens, " ") + " "
+}
+
+// candidates returns the LIKE patterns selecting the entries that share
+// the prefix of at least one query token, the patterns are safe to use as
+// tokens only contain letters and digits
+func candidates(text string) []string {
+	var (
+		ps   []string
+		seen = make(map[string]bool)
+	)
+
+	for _, t := range tokenize(text) {
+		if r := []rune(t); len(r) > candidatePrefix {
+			t = string(r[:candidatePrefix])
+		}
+
+		if !seen[t] {
+			seen[t] = true
+			ps = append(ps, "% "+t+"%")
+		}
+	}
+
+	return ps
+}
+
+// paginate returns the page of results starting at offset
+func paginate(rs []Result, limit int, offset int) []Result {
+	if offset >= len(rs) {
+		return []Result{}
+	}
+
+	end := offset + limit
+	if end > len(rs) {
+		end = len(rs)
+	}
+
+	return rs[offset:end]
+}
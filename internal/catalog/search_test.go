@@ -0,0 +1,75 @@
+package catalog
+
+import (
+	"testing"
+)
+
+func TestRank(t *testing.T) {
+	library, err := Library()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"exactName", "Barbell Bench Press", "barbell-bench-press"},
+		{"caseInsensitive", "barbell bench press", "barbell-bench-press"},
+		{"alias", "BB Bench", "barbell-bench-press"},
+		{"abbreviation", "rdl", "romanian-deadlift"},
+		{"typo", "benhc press", "barbell-bench-press"},
+		{"transposition", "dealdift", "barbell-deadlift"},
+		{"partialLastToken", "lat pull", "lat-pulldown"},
+		{"punctuation", "pullup", "pull-up"},
+		{"aliasTypo", "farmers cary", "farmers-walk"},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			rs := rank(library, c.text)
+			if len(rs) == 0 {
+				t.Fatalf("want %s but got no results", c.want)
+			}
+
+			if rs[0].Slug != c.want {
+				t.Errorf("want %s first but got %s (%s, %.2f)", c.want, rs[0].Slug, rs[0].Match, rs[0].Score)
+			}
+		})
+	}
+}
+
+func TestRankNoMatch(t *testing.T) {
+	library, err := Library()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rs := rank(library, "xylophone"); len(rs) != 0 {
+		t.Errorf("want no results but got %d, first %s", len(rs), rs[0].Slug)
+	}
+
+	if rs := rank(library, ""); len(rs) != len(library) {
+		t.Errorf("want all %d entries for empty query but got %d", len(library), len(rs))
+	}
+}
+
+func TestDistance(t *testing.T) {
+	cs := []struct {
+		a, b string
+		want int
+	}{
+		{"squat", "squat", 0},
+		{"squat", "sqaut", 1},
+		{"bench", "bnch", 1},
+		{"press", "presses", 2},
+		{"", "row", 3},
+	}
+
+	for _, c := range cs {
+		if got := distance(c.a, c.b); got != c.want {
+			t.Errorf("distance(%q, %q): want %d but got %d", c.a, c.b, c.want, got)
+		}
+	}
+}
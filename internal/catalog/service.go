@@ -0,0 +1,174 @@
+package catalog
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/scrot/musclemem-api/internal/api"
+)
+
+// NewFetchHandler returns a single catalog entry, built-in entries
+// require the {slug} path variable, custom entries also require {username}
+func NewFetchHandler(l *slog.Logger, catalog Retreiver) http.Handler {
+	l = l.With("handler", "FetchHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ref := EntryRef{
+			Owner: r.PathValue("username"),
+			Slug:  r.PathValue("slug"),
+		}
+
+		l := l.With("entry", ref)
+
+		e, err := catalog.ByRef(ref)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("fetched %s", e))
+
+		if err := api.WriteJSON(w, http.StatusOK, e); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewFetchAllHandler returns the built-in and custom entries of a user
+// requires {username} path variable
+func NewFetchAllHandler(l *slog.Logger, catalog Retreiver) http.Handler {
+	l = l.With("handler", "FetchAllHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		es, err := catalog.Available(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched catalog entries", "count", len(es))
+
+		if err := api.WriteJSON(w, http.StatusOK, es); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewCreateHandler creates a custom catalog entry for a user
+// requires {username} path variable
+func NewCreateHandler(l *slog.Logger, catalog Storer) http.Handler {
+	l = l.With("handler", "CreateHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		add, err := api.ReadJSON[Entry](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		created, err := catalog.New(username, add)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s created", created))
+
+		if err := api.WriteJSON(w, http.StatusOK, created); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewDeleteHandler deletes a custom catalog entry of a user
+// requires {username} and {slug} path variables
+func NewDeleteHandler(l *slog.Logger, catalog Deleter) http.Handler {
+	l = l.With("handler", "DeleteHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			slug     = r.PathValue("slug")
+		)
+
+		l := l.With("user", username, "slug", slug)
+
+		deleted, err := catalog.Delete(username, slug)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("catalog entry deleted", "key", deleted.Ref())
+
+		if err := api.WriteJSON(w, http.StatusOK, deleted); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewSearchHandler searches the catalog for entries matching the query parameters,
+// the built-in library is always searched and the custom entries of the user
+// when the optional {username} path variable is set
+// supports q, muscle, equipment, pattern, limit and offset query parameters
+func NewSearchHandler(l *slog.Logger, catalog Retreiver) http.Handler {
+	l = l.With("handler", "SearchHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			params   = r.URL.Query()
+		)
+
+		query := Query{
+			Text:      params.Get("q"),
+			Muscle:    Muscle(params.Get("muscle")),
+			Equipment: Equipment(params.Get("equipment")),
+			Pattern:   Pattern(params.Get("pattern")),
+		}
+
+		l := l.With("user", username, "query", query.Text, "muscle", query.Muscle, "equipment", query.Equipment)
+
+		var err error
+		if v := params.Get("limit"); v != "" {
+			if query.Limit, err = strconv.Atoi(v); err != nil {
+				api.WriteInternalError(l, w, err, "invalid limit")
+				return
+			}
+		}
+
+		if v := params.Get("offset"); v != "" {
+			if query.Offset, err = strconv.Atoi(v); err != nil {
+				api.WriteInternalError(l, w, err, "invalid offset")
+				return
+			}
+		}
+
+		rs, err := catalog.Search(username, query)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("searched catalog", "total", rs.Total)
+
+		if err := api.WriteJSON(w, http.StatusOK, rs); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
@@ -0,0 +1,536 @@
+package catalog
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/scrot/musclemem-api/internal/storage"
+)
+
+const (
+	rolePrimary   = "primary"
+	roleSecondary = "secondary"
+)
+
+type SQLCatalogStore struct {
+	*storage.SqlDatastore
+}
+
+func NewSQLCatalogStore(db *storage.SqlDatastore) *SQLCatalogStore {
+	return &SQLCatalogStore{db}
+}
+
+func (cs *SQLCatalogStore) ByRef(ref EntryRef) (Entry, error) {
+	const stmt = `
+  SELECT owner, slug, name, equipment, pattern
+  FROM catalog_exercises
+  WHERE owner = {{ .Owner }} AND slug = {{ .Slug }}
+  `
+
+	if ref.Slug == "" {
+		return Entry{}, fmt.Errorf("ByRef: %w", ErrInvalidFields)
+	}
+
+	q, args, err := cs.CompileStatement(stmt, ref)
+	if err != nil {
+		return Entry{}, fmt.Errorf("ByRef: compile: %w", err)
+	}
+
+	var e Entry
+	if err := cs.QueryRow(q, args...).Scan(&e.Owner, &e.Slug, &e.Name, &e.Equipment, &e.Pattern); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Entry{}, fmt.Errorf("ByRef: %s: %w", ref, ErrNotFound)
+		}
+		return Entry{}, fmt.Errorf("ByRef: query: %w", err)
+	}
+
+	es := []Entry{e}
+	if err := cs.details(es); err != nil {
+		return Entry{}, fmt.Errorf("ByRef: %w", err)
+	}
+
+	return es[0], nil
+}
+
+func (cs *SQLCatalogStore) Available(owner string) ([]Entry, error) {
+	const stmt = `
+  SELECT owner, slug, name, equipment, pattern
+  FROM catalog_exercises
+  WHERE owner = '' OR owner = {{ . }}
+  `
+
+	q, args, err := cs.CompileStatement(stmt, owner)
+	if err != nil {
+		return []Entry{}, fmt.Errorf("Available: compile: %w", err)
+	}
+
+	rows, err := cs.Query(q, args...)
+	if err != nil {
+		return []Entry{}, fmt.Errorf("Available: query: %w", err)
+	}
+	defer rows.Close()
+
+	var es []Entry
+	for rows.Next() {
+		var e Entry
+		if err := rows.Scan(&e.Owner, &e.Slug, &e.Name, &e.Equipment, &e.Pattern); err != nil {
+			return []Entry{}, fmt.Errorf("Available: scan: %w", err)
+		}
+		es = append(es, e)
+	}
+
+	if err := cs.details(es); err != nil {
+		return []Entry{}, fmt.Errorf("Available: %w", err)
+	}
+
+	sort.Slice(es, func(i, j int) bool { return es[i].Name < es[j].Name })
+
+	return es, nil
+}
+
+func (cs *SQLCatalogStore) Search(owner string, query Query) (Results, error) {
+	const (
+		filter = `
+    FROM catalog_exercises c
+    WHERE (c.owner = '' OR c.owner = {{ .Owner }})
+    {{ if .Equipment }} AND c.equipment = {{ .Equipment }} {{ end }}
+    {{ if .Pattern }} AND c.pattern = {{ .Pattern }} {{ end }}
+    {{ if .Muscle }}
+      AND EXISTS (
+        SELECT 1
+        FROM catalog_muscles m
+        WHERE m.owner = c.owner AND m.slug = c.slug AND m.muscle = {{ .Muscle }}
+      )
+    {{ end }}
+    {{ if .Candidates }}
+      AND ({{ range $i, $p := .Candidates }}{{ if $i }} OR {{ end }}c.search LIKE {{ $p }}{{ end }})
+    {{ end }}
+    `
+
+		countStmt = `SELECT COUNT(*)` + filter
+
+		entriesStmt = `
+    SELECT c.owner, c.slug, c.name, c.equipment, c.pattern` + filter + `
+    ORDER BY c.name, c.owner, c.slug
+    {{ if not .Candidates }} LIMIT {{ .Limit }} OFFSET {{ .Offset }} {{ end }}
+    `
+	)
+
+	if query.Limit <= 0 {
+		query.Limit = DefaultSearchLimit
+	}
+
+	if query.Limit > MaxSearchLimit || query.Offset < 0 {
+		return Results{}, fmt.Errorf("Search: %w", ErrInvalidFields)
+	}
+
+	data := struct {
+		Owner      string
+		Equipment  Equipment
+		Pattern    Pattern
+		Muscle     Muscle
+		Candidates []string
+		Limit      int
+		Offset     int
+	}{owner, query.Equipment, query.Pattern, query.Muscle, candidates(query.Text), query.Limit, query.Offset}
+
+	q, args, err := cs.CompileStatement(entriesStmt, data)
+	if err != nil {
+		return Results{}, fmt.Errorf("Search: compile: %w", err)
+	}
+
+	rows, err := cs.Query(q, args...)
+	if err != nil {
+		return Results{}, fmt.Errorf("Search: query: %w", err)
+	}
+	defer rows.Close()
+
+	es := []Entry{}
+	for rows.Next() {
+		var e Entry
+		if err := rows.Scan(&e.Owner, &e.Slug, &e.Name, &e.Equipment, &e.Pattern); err != nil {
+			return Results{}, fmt.Errorf("Search: scan: %w", err)
+		}
+		es = append(es, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return Results{}, fmt.Errorf("Search: %w", err)
+	}
+
+	if err := cs.details(es); err != nil {
+		return Results{}, fmt.Errorf("Search: %w", err)
+	}
+
+	// without text the page is selected by the database, otherwise
+	// only the candidates sharing a token prefix are ranked and paged
+	if len(data.Candidates) == 0 {
+		q, args, err := cs.CompileStatement(countStmt, data)
+		if err != nil {
+			return Results{}, fmt.Errorf("Search: compile count: %w", err)
+		}
+
+		var total int
+		if err := cs.QueryRow(q, args...).Scan(&total); err != nil {
+			return Results{}, fmt.Errorf("Search: count: %w", err)
+		}
+
+		return Results{
+			Results: rank(es, ""),
+			Total:   total,
+			Limit:   query.Limit,
+			Offset:  query.Offset,
+		}, nil
+	}
+
+	ranked := rank(es, query.Text)
+
+	return Results{
+		Results: paginate(ranked, query.Limit, query.Offset),
+		Total:   len(ranked),
+		Limit:   query.Limit,
+		Offset:  query.Offset,
+	}, nil
+}
+
+func (cs *SQLCatalogStore) New(owner string, e Entry) (Entry, error) {
+	if owner == "" {
+		return Entry{}, fmt.Errorf("New: %w", ErrInvalidFields)
+	}
+
+	e.Owner = owner
+	if e.Slug == "" {
+		e.Slug = Slugify(e.Name)
+	}
+
+	if err := e.Validate(); err != nil {
+		return Entry{}, fmt.Errorf("New: %w", err)
+	}
+
+	if _, err := cs.ByRef(e.Ref()); err == nil {
+		return Entry{}, fmt.Errorf("New: %s already exists: %w", e.Ref(), ErrInvalidFields)
+	}
+
+	tx, err := cs.Begin()
+	if err != nil {
+		return Entry{}, fmt.Errorf("New: new transaction: %w", err)
+	}
+
+	if err := cs.upsert(tx, e); err != nil {
+		tx.Rollback()
+		return Entry{}, fmt.Errorf("New: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Entry{}, fmt.Errorf("New: commit transaction: %w", err)
+	}
+
+	created, err := cs.ByRef(e.Ref())
+	if err != nil {
+		return Entry{}, fmt.Errorf("New: fetch %s: %w", e.Ref(), err)
+	}
+
+	return created, nil
+}
+
+func (cs *SQLCatalogStore) Delete(owner string, slug string) (Entry, error) {
+	const (
+		usedStmt = `
+    SELECT COUNT(*)
+    FROM exercises
+    WHERE catalog = {{ . }}
+    `
+
+		deleteStmt = `
+    DELETE FROM catalog_exercises
+    WHERE owner = {{ .Owner }} AND slug = {{ .Slug }}
+    `
+	)
+
+	if owner == "" || slug == "" {
+		return Entry{}, fmt.Errorf("Delete: %w", ErrInvalidFields)
+	}
+
+	e, err := cs.ByRef(EntryRef{owner, slug})
+	if err != nil {
+		return Entry{}, fmt.Errorf("Delete: %w", err)
+	}
+
+	q, args, err := cs.CompileStatement(usedStmt, e.Ref().String())
+	if err != nil {
+		return Entry{}, fmt.Errorf("Delete: compile: %w", err)
+	}
+
+	var count int
+	if err := cs.QueryRow(q, args...).Scan(&count); err != nil {
+		return Entry{}, fmt.Errorf("Delete: count references: %w", err)
+	}
+
+	if count > 0 {
+		return Entry{}, fmt.Errorf("Delete: %s: %w", e.Ref(), ErrInUse)
+	}
+
+	tx, err := cs.Begin()
+	if err != nil {
+		return Entry{}, fmt.Errorf("Delete: new transaction: %w", err)
+	}
+
+	if err := cs.clearDetails(tx, e.Ref()); err != nil {
+		tx.Rollback()
+		return Entry{}, fmt.Errorf("Delete: %w", err)
+	}
+
+	q, args, err = cs.CompileStatement(deleteStmt, e.Ref())
+	if err != nil {
+		tx.Rollback()
+		return Entry{}, fmt.Errorf("Delete: compile: %w", err)
+	}
+
+	if _, err := tx.Exec(q, args...); err != nil {
+		tx.Rollback()
+		return Entry{}, fmt.Errorf("Delete: execute: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Entry{}, fmt.Errorf("Delete: commit transaction: %w", err)
+	}
+
+	return e, nil
+}
+
+func (cs *SQLCatalogStore) Seed(entries []Entry) error {
+	tx, err := cs.Begin()
+	if err != nil {
+		return fmt.Errorf("Seed: new transaction: %w", err)
+	}
+
+	for _, e := range entries {
+		e.Owner = ""
+		if err := e.Validate(); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Seed: %s: %w", e.Slug, err)
+		}
+
+		if err := cs.upsert(tx, e); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Seed: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Seed: commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// upsert inserts or updates the entry and replaces its muscles and aliases
+func (cs *SQLCatalogStore) upsert(tx *sql.Tx, e Entry) error {
+	const (
+		entryStmt = `
+    INSERT INTO catalog_exercises (owner, slug, name, equipment, pattern, search)
+    VALUES ({{ .Owner }}, {{ .Slug }}, {{ .Name }}, {{ .Equipment }}, {{ .Pattern }}, {{ .Search }})
+    ON CONFLICT (owner, slug) DO UPDATE
+    SET name = excluded.name, equipment = excluded.equipment, pattern = excluded.pattern, search = excluded.search
+    `
+
+		muscleStmt = `
+    INSERT INTO catalog_muscles (owner, slug, muscle, role)
+    VALUES ({{ .Owner }}, {{ .Slug }}, {{ .Muscle }}, {{ .Role }})
+    `
+
+		aliasStmt = `
+    INSERT INTO catalog_aliases (owner, slug, alias)
+    VALUES ({{ .Owner }}, {{ .Slug }}, {{ .Alias }})
+    `
+	)
+
+	data := struct {
+		Entry
+		Search string
+	}{e, document(e)}
+
+	q, args, err := cs.CompileStatement(entryStmt, data)
+	if err != nil {
+		return fmt.Errorf("upsert: compile: %w", err)
+	}
+
+	if _, err := tx.Exec(q, args...); err != nil {
+		return fmt.Errorf("upsert %s: %w", e.Ref(), err)
+	}
+
+	if err := cs.clearDetails(tx, e.Ref()); err != nil {
+		return fmt.Errorf("upsert %s: %w", e.Ref(), err)
+	}
+
+	type muscle struct {
+		Owner, Slug, Muscle, Role string
+	}
+
+	var muscles []muscle
+	for _, m := range e.Primary {
+		muscles = append(muscles, muscle{e.Owner, e.Slug, string(m), rolePrimary})
+	}
+	for _, m := range e.Secondary {
+		muscles = append(muscles, muscle{e.Owner, e.Slug, string(m), roleSecondary})
+	}
+
+	for _, m := range muscles {
+		q, args, err := cs.CompileStatement(muscleStmt, m)
+		if err != nil {
+			return fmt.Errorf("upsert: compile muscle: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			return fmt.Errorf("upsert %s: muscle %s: %w", e.Ref(), m.Muscle, err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, a := range e.Aliases {
+		if seen[a] {
+			continue
+		}
+		seen[a] = true
+
+		data := struct {
+			Owner, Slug, Alias string
+		}{e.Owner, e.Slug, a}
+
+		q, args, err := cs.CompileStatement(aliasStmt, data)
+		if err != nil {
+			return fmt.Errorf("upsert: compile alias: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			return fmt.Errorf("upsert %s: alias %s: %w", e.Ref(), a, err)
+		}
+	}
+
+	return nil
+}
+
+// clearDetails removes the muscles and aliases of an entry
+func (cs *SQLCatalogStore) clearDetails(tx *sql.Tx, ref EntryRef) error {
+	const (
+		musclesStmt = `
+    DELETE FROM catalog_muscles
+    WHERE owner = {{ .Owner }} AND slug = {{ .Slug }}
+    `
+
+		aliasesStmt = `
+    DELETE FROM catalog_aliases
+    WHERE owner = {{ .Owner }} AND slug = {{ .Slug }}
+    `
+	)
+
+	for _, stmt := range []string{musclesStmt, aliasesStmt} {
+		q, args, err := cs.CompileStatement(stmt, ref)
+		if err != nil {
+			return fmt.Errorf("clearDetails: compile: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			return fmt.Errorf("clearDetails: execute: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// details fills in the muscles and aliases of the given entries
+func (cs *SQLCatalogStore) details(es []Entry) error {
+	const (
+		musclesStmt = `
+    SELECT owner, slug, muscle, role
+    FROM catalog_muscles
+    WHERE owner = '' OR owner = {{ . }}
+    ORDER BY muscle
+    `
+
+		aliasesStmt = `
+    SELECT owner, slug, alias
+    FROM catalog_aliases
+    WHERE owner = '' OR owner = {{ . }}
+    ORDER BY alias
+    `
+	)
+
+	if len(es) == 0 {
+		return nil
+	}
+
+	index := make(map[EntryRef]*Entry, len(es))
+	var owner string
+	for i := range es {
+		es[i].Primary = []Muscle{}
+		es[i].Secondary = []Muscle{}
+		es[i].Aliases = []string{}
+		index[es[i].Ref()] = &es[i]
+		if es[i].Owner != "" {
+			owner = es[i].Owner
+		}
+	}
+
+	q, args, err := cs.CompileStatement(musclesStmt, owner)
+	if err != nil {
+		return fmt.Errorf("details: compile muscles: %w", err)
+	}
+
+	rows, err := cs.Query(q, args...)
+	if err != nil {
+		return fmt.Errorf("details: query muscles: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ref        EntryRef
+			muscle     Muscle
+			muscleRole string
+		)
+		if err := rows.Scan(&ref.Owner, &ref.Slug, &muscle, &muscleRole); err != nil {
+			return fmt.Errorf("details: scan muscle: %w", err)
+		}
+
+		e, ok := index[ref]
+		if !ok {
+			continue
+		}
+
+		if muscleRole == rolePrimary {
+			e.Primary = append(e.Primary, muscle)
+		} else {
+			e.Secondary = append(e.Secondary, muscle)
+		}
+	}
+
+	q, args, err = cs.CompileStatement(aliasesStmt, owner)
+	if err != nil {
+		return fmt.Errorf("details: compile aliases: %w", err)
+	}
+
+	rows, err = cs.Query(q, args...)
+	if err != nil {
+		return fmt.Errorf("details: query aliases: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ref   EntryRef
+			alias string
+		)
+		if err := rows.Scan(&ref.Owner, &ref.Slug, &alias); err != nil {
+			return fmt.Errorf("details: scan alias: %w", err)
+		}
+
+		if e, ok := index[ref]; ok {
+			e.Aliases = append(e.Aliases, alias)
+		}
+	}
+
+	return nil
+}
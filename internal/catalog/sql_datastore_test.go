@@ -0,0 +1,233 @@
+package catalog
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scrot/musclemem-api/internal/storage"
+)
+
+func TestSeedLibrary(t *testing.T) {
+	entries, flush := mockCatalogStore(t)
+	defer flush()
+
+	library, err := Library()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// seeding twice updates instead of duplicating entries
+	for i := 0; i < 2; i++ {
+		if err := entries.Seed(library); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := entries.Available("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != len(library) {
+		t.Fatalf("want %d entries but got %d", len(library), len(got))
+	}
+
+	bench, err := entries.ByRef(EntryRef{Slug: "barbell-bench-press"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bench.Primary) != 1 || bench.Primary[0] != MuscleChest {
+		t.Errorf("want primary muscle %s but got %v", MuscleChest, bench.Primary)
+	}
+
+	if len(bench.Aliases) == 0 {
+		t.Errorf("want aliases for %s but got none", bench)
+	}
+}
+
+func TestNewCustomEntry(t *testing.T) {
+	entries, flush := mockCatalogStore(t)
+	defer flush()
+
+	cs := []struct {
+		name    string
+		input   Entry
+		want    EntryRef
+		wantErr error
+	}{
+		{
+			"validEntry",
+			Entry{Name: "Zercher Squat", Primary: []Muscle{MuscleQuads}, Equipment: EquipmentBarbell, Pattern: PatternSquat},
+			EntryRef{"user", "zercher-squat"},
+			nil,
+		},
+		{
+			"duplicateEntry",
+			Entry{Name: "Zercher Squat", Primary: []Muscle{MuscleQuads}, Equipment: EquipmentBarbell, Pattern: PatternSquat},
+			EntryRef{},
+			ErrInvalidFields,
+		},
+		{
+			"unknownMuscle",
+			Entry{Name: "Neck Curl", Primary: []Muscle{"neck"}, Equipment: EquipmentOther, Pattern: PatternIsolation},
+			EntryRef{},
+			ErrInvalidFields,
+		},
+		{
+			"missingPrimaryMuscle",
+			Entry{Name: "Mystery", Equipment: EquipmentOther, Pattern: PatternIsolation},
+			EntryRef{},
+			ErrInvalidFields,
+		},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := entries.New("user", c.input)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("want error %v but got %v", c.wantErr, err)
+			}
+
+			if got.Ref() != c.want && c.wantErr == nil {
+				t.Errorf("want ref %s but got %s", c.want, got.Ref())
+			}
+		})
+	}
+
+	available, err := entries.Available("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(available) != 1 {
+		t.Errorf("want 1 custom entry but got %d", len(available))
+	}
+
+	if _, err := entries.Delete("user", "zercher-squat"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := entries.ByRef(EntryRef{"user", "zercher-squat"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v but got %v", ErrNotFound, err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	entries, flush := mockCatalogStore(t)
+	defer flush()
+
+	library, err := Library()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := entries.Seed(library); err != nil {
+		t.Fatal(err)
+	}
+
+	custom := Entry{Name: "Bench Press Lockout", Primary: []Muscle{MuscleTriceps}, Equipment: EquipmentBarbell, Pattern: PatternHorizontalPush}
+	if _, err := entries.New("user", custom); err != nil {
+		t.Fatal(err)
+	}
+
+	cs := []struct {
+		name      string
+		owner     string
+		query     Query
+		wantTotal int
+		wantFirst string
+	}{
+		{"fuzzyText", "", Query{Text: "bench pres"}, 4, "barbell-bench-press"},
+		{"typo", "", Query{Text: "benhc press"}, 4, "barbell-bench-press"},
+		{"customEntries", "user", Query{Text: "bench press lockout"}, 1, "bench-press-lockout"},
+		{"otherUsersEntries", "other", Query{Text: "bench press lockout"}, 0, ""},
+		{"muscleFilter", "", Query{Text: "raise", Muscle: MuscleShoulders}, 1, "lateral-raise"},
+		{"equipmentFilter", "", Query{Text: "bench", Equipment: EquipmentDumbbell}, 1, "dumbbell-bench-press"},
+		{"onlyFilters", "", Query{Equipment: EquipmentTrapBar}, 1, "trap-bar-deadlift"},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := entries.Search(c.owner, c.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.Total != c.wantTotal {
+				t.Errorf("want %d results but got %d", c.wantTotal, got.Total)
+			}
+
+			if c.wantTotal > 0 && got.Results[0].Slug != c.wantFirst {
+				t.Errorf("want %s first but got %v", c.wantFirst, got.Results)
+			}
+		})
+	}
+
+	page, err := entries.Search("", Query{Text: "bench", Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(page.Results) != 2 || page.Total != 4 {
+		t.Errorf("want page of 2 out of 4 results but got %d out of %d", len(page.Results), page.Total)
+	}
+
+	// without text the page is selected by name in the database
+	all, err := entries.Search("user", Query{Limit: 3, Offset: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(all.Results) != 3 || all.Total != len(library)+1 || all.Results[0].Name > all.Results[1].Name {
+		t.Errorf("want second page of 3 out of %d entries by name but got %d out of %d", len(library)+1, len(all.Results), all.Total)
+	}
+}
+
+func TestParseRef(t *testing.T) {
+	cs := []struct {
+		input   string
+		want    EntryRef
+		wantErr bool
+	}{
+		{"barbell-bench-press", EntryRef{Slug: "barbell-bench-press"}, false},
+		{"user/zercher-squat", EntryRef{"user", "zercher-squat"}, false},
+		{"user/", EntryRef{}, true},
+		{"", EntryRef{}, true},
+		{"a/b/c", EntryRef{}, true},
+	}
+
+	for _, c := range cs {
+		got, err := ParseRef(c.input)
+		if (err != nil) != c.wantErr {
+			t.Errorf("%q: want error %t but got %v", c.input, c.wantErr, err)
+		}
+
+		if got != c.want {
+			t.Errorf("%q: want %v but got %v", c.input, c.want, got)
+		}
+	}
+}
+
+func mockCatalogStore(t *testing.T) (CatalogStore, func()) {
+	t.Helper()
+
+	config := storage.DatastoreConfig{
+		DatabaseURL:   "file://test.db?cache=shared&mode=memory",
+		MigrationPath: "migrations",
+		Overwrite:     false,
+	}
+
+	store, err := storage.NewSqlDatastore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flush := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal()
+		}
+	}
+
+	return NewSQLCatalogStore(store), flush
+}
@@ -0,0 +1,64 @@
+package challenge
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidFields = errors.New("contains invalid fields")
+	ErrForbidden     = errors.New("not allowed to access challenge")
+)
+
+// ChallengeStore represents the challenge repository
+type ChallengeStore interface {
+	Retreiver
+	Storer
+	Participator
+	Ranker
+}
+
+// Retreiver implementations allow for challenges to be queried
+type Retreiver interface {
+	// ByID returns the challenge including its participants
+	ByID(challenge int) (Challenge, error)
+
+	// ByParticipant returns the challenges username participates
+	// in, the most recently started first
+	ByParticipant(username string) ([]Challenge, error)
+}
+
+// Storer implementations allow for challenges to be created and deleted
+type Storer interface {
+	// New creates the challenge of owner, the owner participates by default
+	New(owner string, c Challenge) (Challenge, error)
+
+	// Delete deletes the challenge, it returns an
+	// ErrForbidden error if the caller is not the owner
+	Delete(challenge int, caller string) (Challenge, error)
+}
+
+// Participator implementations allow for participants to join and leave
+type Participator interface {
+	// Join adds username to the participants, users can only join
+	// themselves, it returns an ErrForbidden error if the account is private
+	Join(challenge int, username string) (Challenge, error)
+
+	// Leave removes username from the participants, it returns an ErrForbidden
+	// error if the caller is neither username nor the owner
+	Leave(challenge int, username string, caller string) (Challenge, error)
+}
+
+// Ranker implementations allow for leaderboards to be computed
+type Ranker interface {
+	// Leaderboard returns the last computed leaderboard of the challenge,
+	// computing it if it was never computed, it returns an ErrForbidden
+	// error if the caller doesn't participate
+	Leaderboard(challenge int, caller string) (Leaderboard, error)
+
+	// Recompute computes the leaderboard of the challenge from the logged
+	// sessions, participants with a private account are left out
+	Recompute(challenge int) (Leaderboard, error)
+
+	// RecomputeActive recomputes the leaderboards of the challenges that
+	// are running or ended within GraceDays, returning the number recomputed
+	RecomputeActive() (int, error)
+}
@@ -0,0 +1,138 @@
+package challenge
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// DateLayout is the layout of the dates a challenge runs between
+	DateLayout = time.DateOnly
+
+	// RefreshInterval is the interval leaderboards are recomputed at
+	RefreshInterval = 15 * time.Minute
+
+	// GraceDays is the number of days after a challenge ended its
+	// leaderboard is still recomputed, for sessions logged late
+	GraceDays = 3
+)
+
+// Metric is what participants of a challenge compete on
+type Metric string
+
+const (
+	// MetricVolume is the total load times repetitions
+	MetricVolume Metric = "volume"
+
+	// MetricRepetitions is the total number of repetitions
+	MetricRepetitions Metric = "repetitions"
+
+	// MetricHeaviestWeight is the heaviest load lifted, including
+	// the body weight for bodyweight exercises like volume
+	MetricHeaviestWeight Metric = "heaviest_weight"
+
+	// MetricSessions is the number of sessions
+	MetricSessions Metric = "sessions"
+)
+
+// Metrics contains all metrics
+var Metrics = []Metric{MetricVolume, MetricRepetitions, MetricHeaviestWeight, MetricSessions}
+
+// Challenge is a competition between Participants on Metric from StartsOn
+// up to and including EndsOn, Exercise is a catalog reference or the name
+// of the exercise the metric is limited to, all exercises if empty
+type Challenge struct {
+	ID           int       `json:"id"`
+	Owner        string    `json:"owner"`
+	Name         string    `json:"name"`
+	Metric       Metric    `json:"metric"`
+	Exercise     string    `json:"exercise,omitempty"`
+	StartsOn     string    `json:"starts_on"`
+	EndsOn       string    `json:"ends_on"`
+	CreatedAt    time.Time `json:"created_at"`
+	Participants []string  `json:"participants"`
+}
+
+func (c Challenge) String() string {
+	return fmt.Sprintf("challenge %d %q of %s", c.ID, c.Name, c.Owner)
+}
+
+// Validate checks if the challenge has a name, known metric and valid date range
+func (c Challenge) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("%w: challenge requires a name", ErrInvalidFields)
+	}
+
+	if !slices.Contains(Metrics, c.Metric) {
+		return fmt.Errorf("%w: metric %q, expected one of %v", ErrInvalidFields, c.Metric, Metrics)
+	}
+
+	if c.Metric == MetricHeaviestWeight && c.Exercise == "" {
+		return fmt.Errorf("%w: metric %s requires an exercise", ErrInvalidFields, c.Metric)
+	}
+
+	for _, d := range []string{c.StartsOn, c.EndsOn} {
+		if _, err := time.Parse(DateLayout, d); err != nil {
+			return fmt.Errorf("%w: date %q, expected %s", ErrInvalidFields, d, DateLayout)
+		}
+	}
+
+	if c.StartsOn > c.EndsOn {
+		return fmt.Errorf("%w: starts on %s after ends on %s", ErrInvalidFields, c.StartsOn, c.EndsOn)
+	}
+
+	return nil
+}
+
+// Standing is the position of a participant on the leaderboard, participants
+// with the same value share the same Rank and are marked Tied
+type Standing struct {
+	Rank     int     `json:"rank"`
+	Username string  `json:"username"`
+	Value    float64 `json:"value"`
+	Tied     bool    `json:"tied,omitempty"`
+}
+
+// Leaderboard contains the standings of the participants of a challenge
+// as of ComputedAt, the best first
+type Leaderboard struct {
+	Challenge  int        `json:"challenge"`
+	Metric     Metric     `json:"metric"`
+	ComputedAt time.Time  `json:"computed_at"`
+	Standings  []Standing `json:"standings"`
+}
+
+// Rank orders the standings by value, the highest first, and assigns
+// their rank. Equal values share a rank and the rank after the tied
+// standings skips their number, participants are ordered by username
+// within a tie
+func Rank(standings []Standing) []Standing {
+	ranked := make([]Standing, len(standings))
+	for i, s := range standings {
+		s.Value = math.Round(s.Value*100) / 100
+		ranked[i] = s
+	}
+
+	slices.SortFunc(ranked, func(a, b Standing) int {
+		if a.Value != b.Value {
+			if a.Value > b.Value {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Username, b.Username)
+	})
+
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+		if i > 0 && ranked[i].Value == ranked[i-1].Value {
+			ranked[i].Rank = ranked[i-1].Rank
+			ranked[i].Tied, ranked[i-1].Tied = true, true
+		}
+	}
+
+	return ranked
+}
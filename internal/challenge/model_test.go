@@ -0,0 +1,32 @@
+package challenge
+
+import "testing"
+
+func TestRank(t *testing.T) {
+	standings := []Standing{
+		{Username: "dave", Value: 500},
+		{Username: "alice", Value: 1000},
+		{Username: "carol", Value: 500.001},
+		{Username: "bob", Value: 1000},
+		{Username: "erin", Value: 0},
+	}
+
+	want := []Standing{
+		{Rank: 1, Username: "alice", Value: 1000, Tied: true},
+		{Rank: 1, Username: "bob", Value: 1000, Tied: true},
+		{Rank: 3, Username: "carol", Value: 500, Tied: true},
+		{Rank: 3, Username: "dave", Value: 500, Tied: true},
+		{Rank: 5, Username: "erin", Value: 0},
+	}
+
+	got := Rank(standings)
+	if len(got) != len(want) {
+		t.Fatalf("want %d standings but got %v", len(want), got)
+	}
+
+	for i, s := range got {
+		if s != want[i] {
+			t.Errorf("want %+v but got %+v", want[i], s)
+		}
+	}
+}
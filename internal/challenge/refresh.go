@@ -0,0 +1,31 @@
+package challenge
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Refresh recomputes the leaderboards of the active challenges every
+// interval until ctx is canceled, so leaderboards pick up sessions
+// logged after they were last computed
+func Refresh(ctx context.Context, l *slog.Logger, rankers Ranker, interval time.Duration) {
+	l = l.With("worker", "Refresh")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.Info("stopped refreshing leaderboards")
+			return
+		case <-ticker.C:
+			n, err := rankers.RecomputeActive()
+			if err != nil {
+				l.Error(err.Error())
+			}
+			l.Debug("refreshed leaderboards", "count", n)
+		}
+	}
+}
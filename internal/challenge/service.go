@@ -0,0 +1,256 @@
+package challenge
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/scrot/musclemem-api/internal/api"
+)
+
+// NewFetchAllHandler returns the challenges the caller participates in
+func NewFetchAllHandler(l *slog.Logger, challenges Retreiver) http.Handler {
+	l = l.With("handler", "FetchAllHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := api.Caller(r)
+
+		l := l.With("user", username)
+
+		cs, err := challenges.ByParticipant(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched challenges", "count", len(cs))
+
+		if err := api.WriteJSON(w, http.StatusOK, cs); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewFetchHandler returns a challenge including its participants
+// requires {challenge} path variable
+func NewFetchHandler(l *slog.Logger, challenges Retreiver) http.Handler {
+	l = l.With("handler", "FetchHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		challenge := r.PathValue("challenge")
+
+		l := l.With("challenge", challenge)
+
+		ci, err := strconv.Atoi(challenge)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		c, err := challenges.ByID(ci)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		if err := api.WriteJSON(w, http.StatusOK, c); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewCreateHandler creates a challenge owned by the caller
+// requires json payload {"name": STRING, "metric": "volume"|"repetitions"|"heaviest_weight"|"sessions",
+// "exercise": STRING, "starts_on": DATE, "ends_on": DATE}
+func NewCreateHandler(l *slog.Logger, challenges Storer) http.Handler {
+	l = l.With("handler", "CreateHandler")
+
+	type Request struct {
+		Name     string `json:"name"`
+		Metric   Metric `json:"metric"`
+		Exercise string `json:"exercise"`
+		StartsOn string `json:"starts_on"`
+		EndsOn   string `json:"ends_on"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := api.Caller(r)
+
+		l := l.With("user", username)
+
+		req, err := api.ReadJSON[Request](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		c := Challenge{
+			Name:     req.Name,
+			Metric:   req.Metric,
+			Exercise: req.Exercise,
+			StartsOn: req.StartsOn,
+			EndsOn:   req.EndsOn,
+		}
+
+		c, err = challenges.New(username, c)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s created", c))
+
+		if err := api.WriteJSON(w, http.StatusOK, c); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewDeleteHandler deletes a challenge, only the owner can delete a challenge
+// requires {challenge} path variable
+func NewDeleteHandler(l *slog.Logger, challenges Storer) http.Handler {
+	l = l.With("handler", "DeleteHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		challenge := r.PathValue("challenge")
+
+		l := l.With("user", api.Caller(r), "challenge", challenge)
+
+		ci, err := strconv.Atoi(challenge)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		c, err := challenges.Delete(ci, api.Caller(r))
+		if err != nil {
+			writeError(l, w, err)
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s deleted", c))
+
+		if err := api.WriteJSON(w, http.StatusOK, c); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewJoinHandler adds the caller to the participants of a challenge
+// requires {challenge} path variable
+func NewJoinHandler(l *slog.Logger, challenges Participator) http.Handler {
+	l = l.With("handler", "JoinHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username  = api.Caller(r)
+			challenge = r.PathValue("challenge")
+		)
+
+		l := l.With("user", username, "challenge", challenge)
+
+		ci, err := strconv.Atoi(challenge)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		c, err := challenges.Join(ci, username)
+		if err != nil {
+			writeError(l, w, err)
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s joined %s", username, c))
+
+		if err := api.WriteJSON(w, http.StatusOK, c); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewLeaveHandler removes a participant from a challenge, callers can
+// leave themselves and the owner can remove any participant
+// requires {challenge} and {participant} path variables
+func NewLeaveHandler(l *slog.Logger, challenges Participator) http.Handler {
+	l = l.With("handler", "LeaveHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			challenge   = r.PathValue("challenge")
+			participant = r.PathValue("participant")
+		)
+
+		l := l.With("user", api.Caller(r), "challenge", challenge, "participant", participant)
+
+		ci, err := strconv.Atoi(challenge)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		c, err := challenges.Leave(ci, participant, api.Caller(r))
+		if err != nil {
+			writeError(l, w, err)
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s left %s", participant, c))
+
+		if err := api.WriteJSON(w, http.StatusOK, c); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewLeaderboardHandler returns the standings of the participants of a
+// challenge, the leaderboard is recomputed periodically and only visible
+// to participants
+// requires {challenge} path variable
+func NewLeaderboardHandler(l *slog.Logger, challenges Ranker) http.Handler {
+	l = l.With("handler", "LeaderboardHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		challenge := r.PathValue("challenge")
+
+		l := l.With("user", api.Caller(r), "challenge", challenge)
+
+		ci, err := strconv.Atoi(challenge)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		lb, err := challenges.Leaderboard(ci, api.Caller(r))
+		if err != nil {
+			writeError(l, w, err)
+			return
+		}
+
+		l.Debug("fetched leaderboard", "count", len(lb.Standings))
+
+		if err := api.WriteJSON(w, http.StatusOK, lb); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// writeError writes the forbidden response if the caller isn't allowed
+// to change or view the challenge or otherwise the internal error response
+func writeError(l *slog.Logger, w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrForbidden) {
+		l.Debug(err.Error())
+		api.WriteForbiddenError(w)
+		return
+	}
+	api.WriteInternalError(l, w, err, "")
+}
@@ -0,0 +1,683 @@
+package challenge
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/body"
+	"github.com/scrot/musclemem-api/internal/social"
+	"github.com/scrot/musclemem-api/internal/storage"
+)
+
+const challengeColumns = `challenge_id, owner, name, metric, exercise, starts_on, ends_on, created_at`
+
+type SQLChallengeStore struct {
+	*storage.SqlDatastore
+
+	// now returns the current time, replaceable for testing
+	now func() time.Time
+}
+
+func NewSQLChallengeStore(db *storage.SqlDatastore) *SQLChallengeStore {
+	return &SQLChallengeStore{db, time.Now}
+}
+
+func (cs *SQLChallengeStore) timestamp() time.Time {
+	return cs.now().UTC().Truncate(time.Second)
+}
+
+// load is the load moved per repetition of set t of session exercise x,
+// including the body weight of the owner of session s for bodyweight exercises
+var load = body.LoadExpr("x.kind", "t.weight", body.WeightExpr("s.owner", "s.performed_on"))
+
+// aggregates contains the expression each metric aggregates the sets of a participant by
+var aggregates = map[Metric]string{
+	MetricVolume:         fmt.Sprintf("SUM((%s) * t.repetitions)", load),
+	MetricRepetitions:    "SUM(t.repetitions)",
+	MetricHeaviestWeight: fmt.Sprintf("MAX(%s)", load),
+	MetricSessions:       "COUNT(DISTINCT s.session_index)",
+}
+
+func (cs *SQLChallengeStore) ByID(challenge int) (Challenge, error) {
+	const stmt = `
+  SELECT ` + challengeColumns + `
+  FROM challenges
+  WHERE challenge_id = {{ . }}
+  `
+
+	challenges, err := cs.query(stmt, challenge)
+	if err != nil {
+		return Challenge{}, fmt.Errorf("ByID: %w", err)
+	}
+
+	if len(challenges) == 0 {
+		return Challenge{}, fmt.Errorf("ByID: challenge %d: %w", challenge, ErrNotFound)
+	}
+
+	return challenges[0], nil
+}
+
+func (cs *SQLChallengeStore) ByParticipant(username string) ([]Challenge, error) {
+	const stmt = `
+  SELECT c.challenge_id, c.owner, c.name, c.metric, c.exercise, c.starts_on, c.ends_on, c.created_at
+  FROM challenges c
+  JOIN challenge_participants p ON p.challenge = c.challenge_id
+  WHERE p.username = {{ . }}
+  ORDER BY c.starts_on DESC, c.challenge_id DESC
+  `
+
+	if username == "" {
+		return []Challenge{}, fmt.Errorf("ByParticipant: %w", ErrInvalidFields)
+	}
+
+	challenges, err := cs.query(stmt, username)
+	if err != nil {
+		return []Challenge{}, fmt.Errorf("ByParticipant: %w", err)
+	}
+
+	return challenges, nil
+}
+
+func (cs *SQLChallengeStore) New(owner string, c Challenge) (Challenge, error) {
+	const (
+		lastStmt = `
+    SELECT COALESCE(MAX(challenge_id), 0)
+    FROM challenges
+    `
+
+		challengeStmt = `
+    INSERT INTO challenges (` + challengeColumns + `)
+    VALUES ({{ .ID }}, {{ .Owner }}, {{ .Name }}, {{ .Metric }}, {{ .Exercise }}, {{ .StartsOn }}, {{ .EndsOn }}, {{ .CreatedAt }})
+    `
+	)
+
+	if owner == "" {
+		return Challenge{}, fmt.Errorf("New: %w: challenge requires an owner", ErrInvalidFields)
+	}
+
+	c.Owner, c.CreatedAt = owner, cs.timestamp()
+	if err := c.Validate(); err != nil {
+		return Challenge{}, fmt.Errorf("New: %w", err)
+	}
+
+	tx, err := cs.Begin()
+	if err != nil {
+		return Challenge{}, fmt.Errorf("New: begin transaction: %w", err)
+	}
+
+	if err := tx.QueryRow(lastStmt).Scan(&c.ID); err != nil {
+		tx.Rollback()
+		return Challenge{}, fmt.Errorf("New: last challenge: %w", err)
+	}
+	c.ID++
+
+	q, args, err := cs.CompileStatement(challengeStmt, c)
+	if err != nil {
+		tx.Rollback()
+		return Challenge{}, fmt.Errorf("New: compile: %w", err)
+	}
+
+	if _, err := tx.Exec(q, args...); err != nil {
+		tx.Rollback()
+		return Challenge{}, fmt.Errorf("New: execute: %w", err)
+	}
+
+	if err := cs.join(tx, c.ID, owner); err != nil {
+		tx.Rollback()
+		return Challenge{}, fmt.Errorf("New: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Challenge{}, fmt.Errorf("New: commit transaction: %w", err)
+	}
+
+	c.Participants = []string{owner}
+	return c, nil
+}
+
+func (cs *SQLChallengeStore) Delete(challenge int, caller string) (Challenge, error) {
+	stmts := []string{
+		`DELETE FROM challenge_standings WHERE challenge = {{ . }}`,
+		`DELETE FROM challenge_participants WHERE challenge = {{ . }}`,
+		`DELETE FROM challenges WHERE challenge_id = {{ . }}`,
+	}
+
+	c, err := cs.ByID(challenge)
+	if err != nil {
+		return Challenge{}, fmt.Errorf("Delete: %w", err)
+	}
+
+	if c.Owner != caller {
+		return Challenge{}, fmt.Errorf("Delete: %s by %s: %w", c, caller, ErrForbidden)
+	}
+
+	tx, err := cs.Begin()
+	if err != nil {
+		return Challenge{}, fmt.Errorf("Delete: begin transaction: %w", err)
+	}
+
+	for _, stmt := range stmts {
+		q, args, err := cs.CompileStatement(stmt, challenge)
+		if err != nil {
+			tx.Rollback()
+			return Challenge{}, fmt.Errorf("Delete: compile: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			tx.Rollback()
+			return Challenge{}, fmt.Errorf("Delete: execute: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Challenge{}, fmt.Errorf("Delete: commit transaction: %w", err)
+	}
+
+	return c, nil
+}
+
+func (cs *SQLChallengeStore) Join(challenge int, username string) (Challenge, error) {
+	const userStmt = `
+  SELECT privacy
+  FROM users
+  WHERE username = {{ . }}
+  `
+
+	c, err := cs.ByID(challenge)
+	if err != nil {
+		return Challenge{}, fmt.Errorf("Join: %w", err)
+	}
+
+	if c.EndsOn < cs.now().UTC().Format(DateLayout) {
+		return Challenge{}, fmt.Errorf("Join: %w: %s ended on %s", ErrInvalidFields, c, c.EndsOn)
+	}
+
+	if c.participates(username) {
+		return c, nil
+	}
+
+	q, args, err := cs.CompileStatement(userStmt, username)
+	if err != nil {
+		return Challenge{}, fmt.Errorf("Join: compile user: %w", err)
+	}
+
+	var privacy string
+	if err := cs.QueryRow(q, args...).Scan(&privacy); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Challenge{}, fmt.Errorf("Join: user %s: %w", username, ErrNotFound)
+		}
+		return Challenge{}, fmt.Errorf("Join: query user: %w", err)
+	}
+
+	if social.Privacy(privacy) == social.PrivacyPrivate {
+		return Challenge{}, fmt.Errorf("Join: private account %s: %w", username, ErrForbidden)
+	}
+
+	tx, err := cs.Begin()
+	if err != nil {
+		return Challenge{}, fmt.Errorf("Join: begin transaction: %w", err)
+	}
+
+	if err := cs.join(tx, challenge, username); err != nil {
+		tx.Rollback()
+		return Challenge{}, fmt.Errorf("Join: %w", err)
+	}
+
+	if err := cs.invalidate(tx, challenge); err != nil {
+		tx.Rollback()
+		return Challenge{}, fmt.Errorf("Join: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Challenge{}, fmt.Errorf("Join: commit transaction: %w", err)
+	}
+
+	c.Participants = append(c.Participants, username)
+	return c, nil
+}
+
+func (cs *SQLChallengeStore) Leave(challenge int, username string, caller string) (Challenge, error) {
+	stmts := []string{
+		`DELETE FROM challenge_standings WHERE challenge = {{ .Challenge }} AND username = {{ .Username }}`,
+		`DELETE FROM challenge_participants WHERE challenge = {{ .Challenge }} AND username = {{ .Username }}`,
+	}
+
+	c, err := cs.ByID(challenge)
+	if err != nil {
+		return Challenge{}, fmt.Errorf("Leave: %w", err)
+	}
+
+	if caller != username && caller != c.Owner {
+		return Challenge{}, fmt.Errorf("Leave: %s by %s: %w", c, caller, ErrForbidden)
+	}
+
+	if !c.participates(username) {
+		return Challenge{}, fmt.Errorf("Leave: participant %s of %s: %w", username, c, ErrNotFound)
+	}
+
+	data := struct {
+		Challenge int
+		Username  string
+	}{challenge, username}
+
+	tx, err := cs.Begin()
+	if err != nil {
+		return Challenge{}, fmt.Errorf("Leave: begin transaction: %w", err)
+	}
+
+	for _, stmt := range stmts {
+		q, args, err := cs.CompileStatement(stmt, data)
+		if err != nil {
+			tx.Rollback()
+			return Challenge{}, fmt.Errorf("Leave: compile: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			tx.Rollback()
+			return Challenge{}, fmt.Errorf("Leave: execute: %w", err)
+		}
+	}
+
+	if err := cs.invalidate(tx, challenge); err != nil {
+		tx.Rollback()
+		return Challenge{}, fmt.Errorf("Leave: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Challenge{}, fmt.Errorf("Leave: commit transaction: %w", err)
+	}
+
+	participants := make([]string, 0, len(c.Participants))
+	for _, p := range c.Participants {
+		if p != username {
+			participants = append(participants, p)
+		}
+	}
+	c.Participants = participants
+
+	return c, nil
+}
+
+func (cs *SQLChallengeStore) Leaderboard(challenge int, caller string) (Leaderboard, error) {
+	const (
+		computedStmt = `
+    SELECT computed_at
+    FROM challenges
+    WHERE challenge_id = {{ . }}
+    `
+
+		standingsStmt = `
+    SELECT username, value
+    FROM challenge_standings
+    WHERE challenge = {{ . }}
+    ORDER BY standing, username
+    `
+	)
+
+	c, err := cs.ByID(challenge)
+	if err != nil {
+		return Leaderboard{}, fmt.Errorf("Leaderboard: %w", err)
+	}
+
+	if !c.participates(caller) {
+		return Leaderboard{}, fmt.Errorf("Leaderboard: %s by %s: %w", c, caller, ErrForbidden)
+	}
+
+	q, args, err := cs.CompileStatement(computedStmt, challenge)
+	if err != nil {
+		return Leaderboard{}, fmt.Errorf("Leaderboard: compile: %w", err)
+	}
+
+	var computed sql.NullTime
+	if err := cs.QueryRow(q, args...).Scan(&computed); err != nil {
+		return Leaderboard{}, fmt.Errorf("Leaderboard: query computed: %w", err)
+	}
+
+	if !computed.Valid {
+		lb, err := cs.Recompute(challenge)
+		if err != nil {
+			return Leaderboard{}, fmt.Errorf("Leaderboard: %w", err)
+		}
+		return lb, nil
+	}
+
+	standings, err := cs.standings(standingsStmt, challenge)
+	if err != nil {
+		return Leaderboard{}, fmt.Errorf("Leaderboard: %w", err)
+	}
+
+	lb := Leaderboard{
+		Challenge:  challenge,
+		Metric:     c.Metric,
+		ComputedAt: computed.Time.UTC(),
+		Standings:  Rank(standings),
+	}
+
+	return lb, nil
+}
+
+func (cs *SQLChallengeStore) Recompute(challenge int) (Leaderboard, error) {
+	const (
+		computeStmt = `
+    SELECT p.username, COALESCE(v.value, 0)
+    FROM challenge_participants p
+    LEFT JOIN (
+      SELECT s.owner AS owner, %s AS value
+      FROM sessions s
+      JOIN challenge_participants cp ON cp.username = s.owner AND cp.challenge = {{ .ID }}
+      JOIN session_exercises x ON x.owner = s.owner AND x.session = s.session_index
+      JOIN session_sets t
+        ON t.owner = x.owner AND t.session = x.session AND t.exercise = x.exercise_index
+        AND t.completed AND NOT t.warmup
+      WHERE s.performed_on >= {{ .StartsOn }} AND s.performed_on <= {{ .EndsOn }}
+        {{ if .Exercise }}AND (x.catalog = {{ .Exercise }} OR LOWER(x.name) = {{ .ExerciseName }}){{ end }}
+      GROUP BY s.owner
+    ) v ON v.owner = p.username
+    JOIN users u ON u.username = p.username
+    WHERE p.challenge = {{ .ID }} AND u.privacy <> {{ .Private }}
+    `
+
+		clearStmt = `
+    DELETE FROM challenge_standings
+    WHERE challenge = {{ . }}
+    `
+
+		standingStmt = `
+    INSERT INTO challenge_standings (challenge, username, standing, value)
+    VALUES ({{ .Challenge }}, {{ .Username }}, {{ .Rank }}, {{ .Value }})
+    `
+
+		computedStmt = `
+    UPDATE challenges
+    SET computed_at = {{ .ComputedAt }}
+    WHERE challenge_id = {{ .Challenge }}
+    `
+	)
+
+	c, err := cs.ByID(challenge)
+	if err != nil {
+		return Leaderboard{}, fmt.Errorf("Recompute: %w", err)
+	}
+
+	aggregate, ok := aggregates[c.Metric]
+	if !ok {
+		return Leaderboard{}, fmt.Errorf("Recompute: %w: metric %q", ErrInvalidFields, c.Metric)
+	}
+
+	data := struct {
+		Challenge
+		ExerciseName string
+		Private      social.Privacy
+	}{c, strings.ToLower(c.Exercise), social.PrivacyPrivate}
+
+	standings, err := cs.standings(fmt.Sprintf(computeStmt, aggregate), data)
+	if err != nil {
+		return Leaderboard{}, fmt.Errorf("Recompute: %w", err)
+	}
+
+	lb := Leaderboard{
+		Challenge:  challenge,
+		Metric:     c.Metric,
+		ComputedAt: cs.timestamp(),
+		Standings:  Rank(standings),
+	}
+
+	tx, err := cs.Begin()
+	if err != nil {
+		return Leaderboard{}, fmt.Errorf("Recompute: begin transaction: %w", err)
+	}
+
+	q, args, err := cs.CompileStatement(clearStmt, challenge)
+	if err != nil {
+		tx.Rollback()
+		return Leaderboard{}, fmt.Errorf("Recompute: compile clear: %w", err)
+	}
+
+	if _, err := tx.Exec(q, args...); err != nil {
+		tx.Rollback()
+		return Leaderboard{}, fmt.Errorf("Recompute: clear: %w", err)
+	}
+
+	for _, s := range lb.Standings {
+		data := struct {
+			Standing
+			Challenge int
+		}{s, challenge}
+
+		q, args, err := cs.CompileStatement(standingStmt, data)
+		if err != nil {
+			tx.Rollback()
+			return Leaderboard{}, fmt.Errorf("Recompute: compile standing: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			tx.Rollback()
+			return Leaderboard{}, fmt.Errorf("Recompute: insert standing: %w", err)
+		}
+	}
+
+	q, args, err = cs.CompileStatement(computedStmt, lb)
+	if err != nil {
+		tx.Rollback()
+		return Leaderboard{}, fmt.Errorf("Recompute: compile computed: %w", err)
+	}
+
+	if _, err := tx.Exec(q, args...); err != nil {
+		tx.Rollback()
+		return Leaderboard{}, fmt.Errorf("Recompute: update computed: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Leaderboard{}, fmt.Errorf("Recompute: commit transaction: %w", err)
+	}
+
+	return lb, nil
+}
+
+func (cs *SQLChallengeStore) RecomputeActive() (int, error) {
+	const stmt = `
+  SELECT challenge_id
+  FROM challenges
+  WHERE starts_on <= {{ .Today }} AND ends_on >= {{ .Since }}
+  ORDER BY challenge_id
+  `
+
+	today := cs.now().UTC()
+	data := struct {
+		Today string
+		Since string
+	}{today.Format(DateLayout), today.AddDate(0, 0, -GraceDays).Format(DateLayout)}
+
+	q, args, err := cs.CompileStatement(stmt, data)
+	if err != nil {
+		return 0, fmt.Errorf("RecomputeActive: compile: %w", err)
+	}
+
+	rows, err := cs.Query(q, args...)
+	if err != nil {
+		return 0, fmt.Errorf("RecomputeActive: query: %w", err)
+	}
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("RecomputeActive: scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("RecomputeActive: rows: %w", err)
+	}
+
+	// a failing challenge doesn't hold back the others
+	var errs []error
+	for _, id := range ids {
+		if _, err := cs.Recompute(id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return len(ids) - len(errs), fmt.Errorf("RecomputeActive: %w", err)
+	}
+
+	return len(ids), nil
+}
+
+// participates reports whether username participates in the challenge
+func (c Challenge) participates(username string) bool {
+	for _, p := range c.Participants {
+		if p == username {
+			return true
+		}
+	}
+	return false
+}
+
+// join adds username to the participants of the challenge within tx
+func (cs *SQLChallengeStore) join(tx *sql.Tx, challenge int, username string) error {
+	const stmt = `
+  INSERT INTO challenge_participants (challenge, username, joined_at)
+  VALUES ({{ .Challenge }}, {{ .Username }}, {{ .JoinedAt }})
+  `
+
+	data := struct {
+		Challenge int
+		Username  string
+		JoinedAt  time.Time
+	}{challenge, username, cs.timestamp()}
+
+	q, args, err := cs.CompileStatement(stmt, data)
+	if err != nil {
+		return fmt.Errorf("join: compile: %w", err)
+	}
+
+	if _, err := tx.Exec(q, args...); err != nil {
+		return fmt.Errorf("join: execute: %w", err)
+	}
+
+	return nil
+}
+
+// invalidate marks the leaderboard of the challenge to be computed on the next read
+func (cs *SQLChallengeStore) invalidate(tx *sql.Tx, challenge int) error {
+	const stmt = `
+  UPDATE challenges
+  SET computed_at = NULL
+  WHERE challenge_id = {{ . }}
+  `
+
+	q, args, err := cs.CompileStatement(stmt, challenge)
+	if err != nil {
+		return fmt.Errorf("invalidate: compile: %w", err)
+	}
+
+	if _, err := tx.Exec(q, args...); err != nil {
+		return fmt.Errorf("invalidate: execute: %w", err)
+	}
+
+	return nil
+}
+
+// standings compiles and executes stmt returning the scanned unranked standings
+func (cs *SQLChallengeStore) standings(stmt string, data any) ([]Standing, error) {
+	q, args, err := cs.CompileStatement(stmt, data)
+	if err != nil {
+		return nil, fmt.Errorf("compile standings: %w", err)
+	}
+
+	rows, err := cs.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query standings: %w", err)
+	}
+	defer rows.Close()
+
+	standings := []Standing{}
+	for rows.Next() {
+		var s Standing
+		if err := rows.Scan(&s.Username, &s.Value); err != nil {
+			return nil, fmt.Errorf("scan standing: %w", err)
+		}
+		standings = append(standings, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows standings: %w", err)
+	}
+
+	return standings, nil
+}
+
+// query compiles and executes stmt returning the scanned challenges
+// including their participants
+func (cs *SQLChallengeStore) query(stmt string, data any) ([]Challenge, error) {
+	const participantsStmt = `
+  SELECT username
+  FROM challenge_participants
+  WHERE challenge = {{ . }}
+  ORDER BY joined_at, username
+  `
+
+	q, args, err := cs.CompileStatement(stmt, data)
+	if err != nil {
+		return nil, fmt.Errorf("compile: %w", err)
+	}
+
+	rows, err := cs.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	challenges := []Challenge{}
+	for rows.Next() {
+		var c Challenge
+		if err := rows.Scan(&c.ID, &c.Owner, &c.Name, &c.Metric, &c.Exercise, &c.StartsOn, &c.EndsOn, &c.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		c.CreatedAt = c.CreatedAt.UTC()
+		challenges = append(challenges, c)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	for i, c := range challenges {
+		q, args, err := cs.CompileStatement(participantsStmt, c.ID)
+		if err != nil {
+			return nil, fmt.Errorf("compile participants: %w", err)
+		}
+
+		rows, err := cs.Query(q, args...)
+		if err != nil {
+			return nil, fmt.Errorf("query participants: %w", err)
+		}
+
+		challenges[i].Participants = []string{}
+		for rows.Next() {
+			var username string
+			if err := rows.Scan(&username); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scan participant: %w", err)
+			}
+			challenges[i].Participants = append(challenges[i].Participants, username)
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("rows participants: %w", err)
+		}
+	}
+
+	return challenges, nil
+}
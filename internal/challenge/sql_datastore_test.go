@@ -0,0 +1,176 @@
+package challenge
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/session/sessiontest"
+	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/user"
+)
+
+func TestLeaderboard(t *testing.T) {
+	challenges, flush := mockChallengeStore(t)
+	defer flush()
+
+	// sets that were not completed never count
+	for _, s := range []sessiontest.Session{
+		{Owner: "alice", Index: 1, Date: "2026-10-02", Exercises: lift("Squat", 100, 5)},
+		{Owner: "bob", Index: 1, Date: "2026-10-03", Exercises: lift("squat", 125, 4)},
+		{Owner: "carol", Index: 1, Date: "2026-10-04", Exercises: lift("Squat", 100, 3, sessiontest.Set{Weight: 100, Repetitions: 10})},
+		{Owner: "carol", Index: 2, Date: "2026-10-05", Exercises: lift("Bench Press", 100, 10)},
+		{Owner: "alice", Index: 2, Date: "2026-09-30", Exercises: lift("Squat", 200, 5)},
+	} {
+		s.Name = "legs"
+		sessiontest.Log(t, challenges.SqlDatastore, s)
+	}
+
+	c, err := challenges.New("alice", Challenge{
+		Name:     "most total volume on squat",
+		Metric:   MetricVolume,
+		Exercise: "squat",
+		StartsOn: "2026-10-01",
+		EndsOn:   "2026-10-31",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, username := range []string{"bob", "carol"} {
+		if _, err := challenges.Join(c.ID, username); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := challenges.Join(c.ID, "dave"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("want error %v joining with a private account but got %v", ErrForbidden, err)
+	}
+
+	if _, err := challenges.Leaderboard(c.ID, "dave"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("want error %v viewing as non-participant but got %v", ErrForbidden, err)
+	}
+
+	lb, err := challenges.Leaderboard(c.ID, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Standing{
+		{Rank: 1, Username: "alice", Value: 500, Tied: true},
+		{Rank: 1, Username: "bob", Value: 500, Tied: true},
+		{Rank: 3, Username: "carol", Value: 300},
+	}
+
+	if len(lb.Standings) != len(want) {
+		t.Fatalf("want %d standings but got %+v", len(want), lb.Standings)
+	}
+
+	for i, s := range lb.Standings {
+		if s != want[i] {
+			t.Errorf("want %+v but got %+v", want[i], s)
+		}
+	}
+
+	if _, err := challenges.Leave(c.ID, "bob", "bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := challenges.RecomputeActive()
+	if err != nil || n != 1 {
+		t.Fatalf("want 1 active challenge recomputed but got %d, %v", n, err)
+	}
+
+	if _, err := challenges.Leaderboard(c.ID, "bob"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("want error %v viewing after leaving but got %v", ErrForbidden, err)
+	}
+
+	if err := setPrivacy(challenges, "carol", "private"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := challenges.Recompute(c.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	lb, err = challenges.Leaderboard(c.ID, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lb.Standings) != 1 || lb.Standings[0].Username != "alice" || lb.Standings[0].Tied {
+		t.Errorf("want alice alone after bob left and carol went private but got %+v", lb.Standings)
+	}
+
+	if _, err := challenges.Delete(c.ID, "bob"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("want error %v deleting as participant but got %v", ErrForbidden, err)
+	}
+
+	if _, err := challenges.Delete(c.ID, "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := challenges.Leaderboard(c.ID, "alice"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v on deleted challenge but got %v", ErrNotFound, err)
+	}
+}
+
+// lift returns a single exercise with a completed set followed by the other sets
+func lift(name string, weight float64, repetitions int, other ...sessiontest.Set) []sessiontest.Exercise {
+	sets := append([]sessiontest.Set{{Weight: weight, Repetitions: repetitions, Completed: true}}, other...)
+	return []sessiontest.Exercise{{Name: name, Sets: sets}}
+}
+
+func mockChallengeStore(t *testing.T) (*SQLChallengeStore, func()) {
+	t.Helper()
+
+	config := storage.DatastoreConfig{
+		DatabaseURL:   "file://test.db?cache=shared&mode=memory",
+		MigrationPath: "migrations",
+		Overwrite:     false,
+	}
+
+	store, err := storage.NewSqlDatastore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users := user.NewSQLUserStore(store)
+	for _, username := range []string{"alice", "bob", "carol", "dave"} {
+		if _, err := users.New(username, username+"@gmail.com", "secret"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	flush := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal()
+		}
+	}
+
+	challenges := NewSQLChallengeStore(store)
+	challenges.now = func() time.Time { return time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC) }
+
+	if err := setPrivacy(challenges, "dave", "private"); err != nil {
+		t.Fatal(err)
+	}
+
+	return challenges, flush
+}
+
+func setPrivacy(challenges *SQLChallengeStore, username string, privacy string) error {
+	const stmt = `UPDATE users SET privacy = {{ .Privacy }} WHERE username = {{ .Username }}`
+
+	data := struct {
+		Username string
+		Privacy  string
+	}{username, privacy}
+
+	q, args, err := challenges.CompileStatement(stmt, data)
+	if err != nil {
+		return err
+	}
+
+	_, err = challenges.Exec(q, args...)
+	return err
+}
@@ -0,0 +1,49 @@
+package coach
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidFields = errors.New("contains invalid fields")
+)
+
+// CoachStore represents the coach-athlete relationship repository
+type CoachStore interface {
+	Retreiver
+	Inviter
+	Authorizer
+}
+
+// Retreiver implementations allow for relationships to be queried
+type Retreiver interface {
+	// Coaches returns the grants athlete gave to coaches including
+	// invitations that are not accepted yet
+	Coaches(athlete string) ([]Grant, error)
+
+	// Invitations returns the invitations to coach that are not accepted yet
+	Invitations(coach string) ([]Grant, error)
+
+	// Athletes returns the athletes of coach including their recent activity
+	Athletes(coach string) ([]Athlete, error)
+}
+
+// Inviter implementations allow for coaches to be invited
+type Inviter interface {
+	// Invite invites coach to access the workouts of athlete, inviting
+	// the coach again changes the access without requiring acceptance
+	Invite(athlete string, coach string, access Access) (Grant, error)
+
+	// Accept accepts the invitation of athlete to coach
+	Accept(coach string, athlete string) (Grant, error)
+
+	// Revoke ends the relationship between athlete and coach,
+	// either can revoke it including pending invitations
+	Revoke(athlete string, coach string) (Grant, error)
+}
+
+// Authorizer implementations allow for delegated access to be checked
+type Authorizer interface {
+	// Access returns the access athlete granted coach,
+	// AccessNone if there is no accepted grant
+	Access(athlete string, coach string) (Access, error)
+}
@@ -0,0 +1,82 @@
+package coach
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RecentDays is the number of days the recent activity of athletes spans
+const RecentDays = 30
+
+// Access is the level of access an athlete grants a coach to its workouts
+type Access string
+
+const (
+	// AccessNone grants no access
+	AccessNone Access = ""
+
+	// AccessRead allows the coach to view the workouts
+	AccessRead Access = "read"
+
+	// AccessWrite allows the coach to view and change the workouts
+	AccessWrite Access = "write"
+)
+
+// Allows reports whether the access permits a request using method,
+// safe methods require read access and others write access
+func (a Access) Allows(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return a == AccessRead || a == AccessWrite
+	default:
+		return a == AccessWrite
+	}
+}
+
+// Grant gives Coach Access to the workouts of Athlete, a grant starts
+// as an invitation and only takes effect after the coach accepts it
+type Grant struct {
+	Athlete    string     `json:"athlete"`
+	Coach      string     `json:"coach"`
+	Access     Access     `json:"access"`
+	CreatedAt  time.Time  `json:"created_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+}
+
+func (g Grant) String() string {
+	return fmt.Sprintf("grant of %s access by %s to coach %s", g.Access, g.Athlete, g.Coach)
+}
+
+// Accepted reports whether the coach accepted the invitation
+func (g Grant) Accepted() bool {
+	return g.AcceptedAt != nil
+}
+
+// Validate checks if the grant gives a valid access to another user
+func (g Grant) Validate() error {
+	if g.Athlete == "" || g.Coach == "" {
+		return fmt.Errorf("%w: grant requires an athlete and coach", ErrInvalidFields)
+	}
+
+	if g.Athlete == g.Coach {
+		return fmt.Errorf("%w: athlete %s can't coach itself", ErrInvalidFields, g.Athlete)
+	}
+
+	if g.Access != AccessRead && g.Access != AccessWrite {
+		return fmt.Errorf("%w: access %q, expected %s or %s", ErrInvalidFields, g.Access, AccessRead, AccessWrite)
+	}
+
+	return nil
+}
+
+// Athlete is an athlete of a coach including its recent activity,
+// Sessions is the number of sessions within the last RecentDays and
+// LastSession the date of the last session if any
+type Athlete struct {
+	Username    string    `json:"username"`
+	Access      Access    `json:"access"`
+	Since       time.Time `json:"since"`
+	LastSession string    `json:"last_session,omitempty"`
+	Sessions    int       `json:"sessions"`
+}
@@ -0,0 +1,207 @@
+package coach
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/scrot/musclemem-api/internal/api"
+)
+
+// NewCoachesHandler returns the coaches an athlete granted access
+// including invitations that are not accepted yet
+// requires {username} path variable
+func NewCoachesHandler(l *slog.Logger, coaches Retreiver) http.Handler {
+	l = l.With("handler", "CoachesHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		gs, err := coaches.Coaches(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched coaches", "count", len(gs))
+
+		if err := api.WriteJSON(w, http.StatusOK, gs); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewInviteHandler invites a coach to access the workouts of an athlete
+// requires {username} path variable
+// requires json payload {"coach": USERNAME, "access": "read"|"write"}
+func NewInviteHandler(l *slog.Logger, coaches Inviter) http.Handler {
+	l = l.With("handler", "InviteHandler")
+
+	type Request struct {
+		Coach  string `json:"coach"`
+		Access Access `json:"access"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		req, err := api.ReadJSON[Request](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		g, err := coaches.Invite(username, req.Coach, req.Access)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s invited", g))
+
+		if err := api.WriteJSON(w, http.StatusOK, g); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewRevokeHandler revokes the access of a coach to the workouts of an athlete
+// requires {username} and {coach} path variables
+func NewRevokeHandler(l *slog.Logger, coaches Inviter) http.Handler {
+	l = l.With("handler", "RevokeHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			coach    = r.PathValue("coach")
+		)
+
+		l := l.With("user", username, "coach", coach)
+
+		g, err := coaches.Revoke(username, coach)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s revoked", g))
+
+		if err := api.WriteJSON(w, http.StatusOK, g); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewInvitationsHandler returns the invitations of a coach that are not accepted yet
+// requires {username} path variable
+func NewInvitationsHandler(l *slog.Logger, coaches Retreiver) http.Handler {
+	l = l.With("handler", "InvitationsHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		gs, err := coaches.Invitations(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched invitations", "count", len(gs))
+
+		if err := api.WriteJSON(w, http.StatusOK, gs); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewAcceptHandler accepts the invitation of an athlete to coach
+// requires {username} and {athlete} path variables
+func NewAcceptHandler(l *slog.Logger, coaches Inviter) http.Handler {
+	l = l.With("handler", "AcceptHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			athlete  = r.PathValue("athlete")
+		)
+
+		l := l.With("user", username, "athlete", athlete)
+
+		g, err := coaches.Accept(username, athlete)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s accepted", g))
+
+		if err := api.WriteJSON(w, http.StatusOK, g); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewDeclineHandler declines the invitation of an athlete or
+// stops coaching the athlete when the invitation was accepted
+// requires {username} and {athlete} path variables
+func NewDeclineHandler(l *slog.Logger, coaches Inviter) http.Handler {
+	l = l.With("handler", "DeclineHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			athlete  = r.PathValue("athlete")
+		)
+
+		l := l.With("user", username, "athlete", athlete)
+
+		g, err := coaches.Revoke(athlete, username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s declined", g))
+
+		if err := api.WriteJSON(w, http.StatusOK, g); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewAthletesHandler returns the athletes of a coach with their recent activity
+// requires {username} path variable
+func NewAthletesHandler(l *slog.Logger, coaches Retreiver) http.Handler {
+	l = l.With("handler", "AthletesHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		as, err := coaches.Athletes(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched athletes", "count", len(as))
+
+		if err := api.WriteJSON(w, http.StatusOK, as); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
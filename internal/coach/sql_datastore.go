@@ -0,0 +1,312 @@
+package coach
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/storage"
+)
+
+const grantColumns = `athlete, coach, access, created_at, accepted_at`
+
+type SQLCoachStore struct {
+	*storage.SqlDatastore
+
+	// now returns the current time, replaceable for testing
+	now func() time.Time
+}
+
+func NewSQLCoachStore(db *storage.SqlDatastore) *SQLCoachStore {
+	return &SQLCoachStore{db, time.Now}
+}
+
+func (cs *SQLCoachStore) timestamp() time.Time {
+	return cs.now().UTC().Truncate(time.Second)
+}
+
+func (cs *SQLCoachStore) Coaches(athlete string) ([]Grant, error) {
+	const stmt = `
+  SELECT ` + grantColumns + `
+  FROM coach_grants
+  WHERE athlete = {{ . }}
+  ORDER BY coach
+  `
+
+	if athlete == "" {
+		return []Grant{}, fmt.Errorf("Coaches: %w", ErrInvalidFields)
+	}
+
+	grants, err := cs.query(stmt, athlete)
+	if err != nil {
+		return []Grant{}, fmt.Errorf("Coaches: %w", err)
+	}
+
+	return grants, nil
+}
+
+func (cs *SQLCoachStore) Invitations(coach string) ([]Grant, error) {
+	const stmt = `
+  SELECT ` + grantColumns + `
+  FROM coach_grants
+  WHERE coach = {{ . }} AND accepted_at IS NULL
+  ORDER BY created_at, athlete
+  `
+
+	if coach == "" {
+		return []Grant{}, fmt.Errorf("Invitations: %w", ErrInvalidFields)
+	}
+
+	grants, err := cs.query(stmt, coach)
+	if err != nil {
+		return []Grant{}, fmt.Errorf("Invitations: %w", err)
+	}
+
+	return grants, nil
+}
+
+func (cs *SQLCoachStore) Athletes(coach string) ([]Athlete, error) {
+	const stmt = `
+  SELECT g.athlete, g.access, g.accepted_at, MAX(s.performed_on),
+    COALESCE(SUM(CASE WHEN s.performed_on >= {{ .Since }} THEN 1 ELSE 0 END), 0)
+  FROM coach_grants g
+  LEFT JOIN sessions s ON s.owner = g.athlete
+  WHERE g.coach = {{ .Coach }} AND g.accepted_at IS NOT NULL
+  GROUP BY g.athlete, g.access, g.accepted_at
+  ORDER BY g.athlete
+  `
+
+	if coach == "" {
+		return []Athlete{}, fmt.Errorf("Athletes: %w", ErrInvalidFields)
+	}
+
+	data := struct {
+		Coach string
+		Since string
+	}{coach, cs.now().UTC().AddDate(0, 0, -RecentDays).Format(time.DateOnly)}
+
+	q, args, err := cs.CompileStatement(stmt, data)
+	if err != nil {
+		return []Athlete{}, fmt.Errorf("Athletes: compile: %w", err)
+	}
+
+	rows, err := cs.Query(q, args...)
+	if err != nil {
+		return []Athlete{}, fmt.Errorf("Athletes: query: %w", err)
+	}
+	defer rows.Close()
+
+	athletes := []Athlete{}
+	for rows.Next() {
+		var (
+			a    Athlete
+			last sql.NullString
+		)
+
+		if err := rows.Scan(&a.Username, &a.Access, &a.Since, &last, &a.Sessions); err != nil {
+			return []Athlete{}, fmt.Errorf("Athletes: scan: %w", err)
+		}
+
+		a.Since = a.Since.UTC()
+		a.LastSession = last.String
+		athletes = append(athletes, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []Athlete{}, fmt.Errorf("Athletes: rows: %w", err)
+	}
+
+	return athletes, nil
+}
+
+func (cs *SQLCoachStore) Invite(athlete string, coach string, access Access) (Grant, error) {
+	const (
+		userStmt = `
+    SELECT COUNT(*)
+    FROM users
+    WHERE username = {{ . }}
+    `
+
+		stmt = `
+    INSERT INTO coach_grants (` + grantColumns + `)
+    VALUES ({{ .Athlete }}, {{ .Coach }}, {{ .Access }}, {{ .CreatedAt }}, NULL)
+    ON CONFLICT (athlete, coach) DO UPDATE
+    SET access = excluded.access
+    `
+	)
+
+	g := Grant{Athlete: athlete, Coach: coach, Access: access, CreatedAt: cs.timestamp()}
+	if err := g.Validate(); err != nil {
+		return Grant{}, fmt.Errorf("Invite: %w", err)
+	}
+
+	q, args, err := cs.CompileStatement(userStmt, coach)
+	if err != nil {
+		return Grant{}, fmt.Errorf("Invite: compile coach: %w", err)
+	}
+
+	var count int
+	if err := cs.QueryRow(q, args...).Scan(&count); err != nil {
+		return Grant{}, fmt.Errorf("Invite: query coach: %w", err)
+	}
+
+	if count == 0 {
+		return Grant{}, fmt.Errorf("Invite: coach %s: %w", coach, ErrNotFound)
+	}
+
+	q, args, err = cs.CompileStatement(stmt, g)
+	if err != nil {
+		return Grant{}, fmt.Errorf("Invite: compile: %w", err)
+	}
+
+	if _, err := cs.Exec(q, args...); err != nil {
+		return Grant{}, fmt.Errorf("Invite: execute: %w", err)
+	}
+
+	g, err = cs.grant(athlete, coach)
+	if err != nil {
+		return Grant{}, fmt.Errorf("Invite: %w", err)
+	}
+
+	return g, nil
+}
+
+func (cs *SQLCoachStore) Accept(coach string, athlete string) (Grant, error) {
+	const stmt = `
+  UPDATE coach_grants
+  SET accepted_at = {{ .AcceptedAt }}
+  WHERE athlete = {{ .Athlete }} AND coach = {{ .Coach }}
+  `
+
+	g, err := cs.grant(athlete, coach)
+	if err != nil {
+		return Grant{}, fmt.Errorf("Accept: %w", err)
+	}
+
+	if g.Accepted() {
+		return g, nil
+	}
+
+	accepted := cs.timestamp()
+	g.AcceptedAt = &accepted
+
+	q, args, err := cs.CompileStatement(stmt, g)
+	if err != nil {
+		return Grant{}, fmt.Errorf("Accept: compile: %w", err)
+	}
+
+	if _, err := cs.Exec(q, args...); err != nil {
+		return Grant{}, fmt.Errorf("Accept: execute: %w", err)
+	}
+
+	return g, nil
+}
+
+func (cs *SQLCoachStore) Revoke(athlete string, coach string) (Grant, error) {
+	const stmt = `
+  DELETE FROM coach_grants
+  WHERE athlete = {{ .Athlete }} AND coach = {{ .Coach }}
+  `
+
+	g, err := cs.grant(athlete, coach)
+	if err != nil {
+		return Grant{}, fmt.Errorf("Revoke: %w", err)
+	}
+
+	q, args, err := cs.CompileStatement(stmt, g)
+	if err != nil {
+		return Grant{}, fmt.Errorf("Revoke: compile: %w", err)
+	}
+
+	if _, err := cs.Exec(q, args...); err != nil {
+		return Grant{}, fmt.Errorf("Revoke: execute: %w", err)
+	}
+
+	return g, nil
+}
+
+func (cs *SQLCoachStore) Access(athlete string, coach string) (Access, error) {
+	g, err := cs.grant(athlete, coach)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return AccessNone, nil
+		}
+		return AccessNone, fmt.Errorf("Access: %w", err)
+	}
+
+	if !g.Accepted() {
+		return AccessNone, nil
+	}
+
+	return g.Access, nil
+}
+
+// grant returns the grant of athlete to coach,
+// it returns an ErrNotFound error if there is none
+func (cs *SQLCoachStore) grant(athlete string, coach string) (Grant, error) {
+	const stmt = `
+  SELECT ` + grantColumns + `
+  FROM coach_grants
+  WHERE athlete = {{ .Athlete }} AND coach = {{ .Coach }}
+  `
+
+	if athlete == "" || coach == "" {
+		return Grant{}, ErrInvalidFields
+	}
+
+	data := struct {
+		Athlete string
+		Coach   string
+	}{athlete, coach}
+
+	grants, err := cs.query(stmt, data)
+	if err != nil {
+		return Grant{}, err
+	}
+
+	if len(grants) == 0 {
+		return Grant{}, fmt.Errorf("grant of %s to %s: %w", athlete, coach, ErrNotFound)
+	}
+
+	return grants[0], nil
+}
+
+// query compiles and executes stmt returning the scanned grants
+func (cs *SQLCoachStore) query(stmt string, data any) ([]Grant, error) {
+	q, args, err := cs.CompileStatement(stmt, data)
+	if err != nil {
+		return nil, fmt.Errorf("compile: %w", err)
+	}
+
+	rows, err := cs.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	grants := []Grant{}
+	for rows.Next() {
+		var (
+			g        Grant
+			accepted sql.NullTime
+		)
+
+		if err := rows.Scan(&g.Athlete, &g.Coach, &g.Access, &g.CreatedAt, &accepted); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		g.CreatedAt = g.CreatedAt.UTC()
+		if accepted.Valid {
+			t := accepted.Time.UTC()
+			g.AcceptedAt = &t
+		}
+		grants = append(grants, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return grants, nil
+}
@@ -0,0 +1,135 @@
+package coach
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/session/sessiontest"
+	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/user"
+)
+
+func TestInvite(t *testing.T) {
+	coaches, flush := mockCoachStore(t)
+	defer flush()
+
+	if _, err := coaches.Invite("athlete", "athlete", AccessRead); !errors.Is(err, ErrInvalidFields) {
+		t.Errorf("want error %v coaching yourself but got %v", ErrInvalidFields, err)
+	}
+
+	if _, err := coaches.Invite("athlete", "unknown", AccessRead); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v inviting unknown coach but got %v", ErrNotFound, err)
+	}
+
+	if _, err := coaches.Invite("athlete", "coach", AccessRead); err != nil {
+		t.Fatal(err)
+	}
+
+	// pending invitations don't grant access
+	if access, err := coaches.Access("athlete", "coach"); err != nil || access != AccessNone {
+		t.Errorf("want no access before accepting but got %q, %v", access, err)
+	}
+
+	invitations, err := coaches.Invitations("coach")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(invitations) != 1 || invitations[0].Athlete != "athlete" {
+		t.Fatalf("want invitation of athlete but got %v", invitations)
+	}
+
+	if _, err := coaches.Accept("coach", "athlete"); err != nil {
+		t.Fatal(err)
+	}
+
+	if access, _ := coaches.Access("athlete", "coach"); access != AccessRead {
+		t.Errorf("want %s access but got %q", AccessRead, access)
+	}
+
+	// inviting again changes the access without acceptance
+	if _, err := coaches.Invite("athlete", "coach", AccessWrite); err != nil {
+		t.Fatal(err)
+	}
+
+	if access, _ := coaches.Access("athlete", "coach"); access != AccessWrite {
+		t.Errorf("want %s access but got %q", AccessWrite, access)
+	}
+
+	if _, err := coaches.Revoke("athlete", "coach"); err != nil {
+		t.Fatal(err)
+	}
+
+	if access, _ := coaches.Access("athlete", "coach"); access != AccessNone {
+		t.Errorf("want no access after revoking but got %q", access)
+	}
+}
+
+func TestAthletes(t *testing.T) {
+	coaches, flush := mockCoachStore(t)
+	defer flush()
+
+	for i, date := range []string{"2026-08-01", "2026-10-01", "2026-10-18"} {
+		sessiontest.Log(t, coaches.SqlDatastore, sessiontest.Session{Owner: "athlete", Index: i + 1, Date: date})
+	}
+
+	if _, err := coaches.Invite("athlete", "coach", AccessRead); err != nil {
+		t.Fatal(err)
+	}
+
+	athletes, err := coaches.Athletes("coach")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(athletes) != 0 {
+		t.Errorf("want no athletes before accepting but got %v", athletes)
+	}
+
+	if _, err := coaches.Accept("coach", "athlete"); err != nil {
+		t.Fatal(err)
+	}
+
+	athletes, err = coaches.Athletes("coach")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(athletes) != 1 || athletes[0].LastSession != "2026-10-18" || athletes[0].Sessions != 2 {
+		t.Errorf("want athlete with 2 recent sessions last on 2026-10-18 but got %+v", athletes)
+	}
+}
+
+func mockCoachStore(t *testing.T) (*SQLCoachStore, func()) {
+	t.Helper()
+
+	config := storage.DatastoreConfig{
+		DatabaseURL:   "file://test.db?cache=shared&mode=memory",
+		MigrationPath: "migrations",
+		Overwrite:     false,
+	}
+
+	store, err := storage.NewSqlDatastore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users := user.NewSQLUserStore(store)
+	for _, username := range []string{"athlete", "coach"} {
+		if _, err := users.New(username, username+"@gmail.com", "secret"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	flush := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal()
+		}
+	}
+
+	coaches := NewSQLCoachStore(store)
+	coaches.now = func() time.Time { return time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC) }
+
+	return coaches, flush
+}
@@ -0,0 +1,45 @@
+package comment
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidFields = errors.New("contains invalid fields")
+	ErrForbidden     = errors.New("not allowed to change comment")
+)
+
+// CommentStore represents the comment repository
+type CommentStore interface {
+	Retreiver
+	Storer
+	Editor
+	Deleter
+}
+
+// Retreiver implementations allow for comments to be queried
+type Retreiver interface {
+	// Thread returns the comments on the resource with the
+	// replies nested under the comment they reply to
+	Thread(ref Ref) ([]Comment, error)
+}
+
+// Storer implementations allow for comments to be created
+type Storer interface {
+	// New comments as author on the resource, replying to
+	// the parent comment unless parent is 0
+	New(ref Ref, author string, parent int, body string) (Comment, error)
+}
+
+// Editor implementations allow for comments to be edited
+type Editor interface {
+	// Edit changes the body of the comment, it returns an
+	// ErrForbidden error if the caller is not the author
+	Edit(ref Ref, comment int, caller string, body string) (Comment, error)
+}
+
+// Deleter implementations allow for comments to be deleted
+type Deleter interface {
+	// Delete deletes the comment including its replies, it returns an ErrForbidden
+	// error if the caller is neither the author nor the owner of the resource
+	Delete(ref Ref, comment int, caller string) (Comment, error)
+}
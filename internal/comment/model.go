@@ -0,0 +1,105 @@
+package comment
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxLength is the maximum number of characters of a comment
+const MaxLength = 2000
+
+// Target is the kind of resource comments are left on
+type Target string
+
+const (
+	// TargetWorkout comments on a planned workout
+	TargetWorkout Target = "workout"
+
+	// TargetSession comments on a logged session
+	TargetSession Target = "session"
+)
+
+// Ref references the workout or session of Owner comments are left on
+type Ref struct {
+	Owner  string `json:"owner"`
+	Target Target `json:"target"`
+	Index  int    `json:"index"`
+}
+
+func (r Ref) String() string {
+	return fmt.Sprintf("%s %s/%d", r.Target, r.Owner, r.Index)
+}
+
+// Comment is feedback of Author on the workout or session Resource of
+// Owner, Parent is the index of the comment it replies to or 0 for a
+// top level comment
+type Comment struct {
+	Owner     string     `json:"owner"`
+	Target    Target     `json:"target"`
+	Resource  int        `json:"resource"`
+	Index     int        `json:"index"`
+	Parent    int        `json:"parent,omitempty"`
+	Author    string     `json:"author"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	Replies   []Comment  `json:"replies,omitempty"`
+}
+
+func (c Comment) String() string {
+	return fmt.Sprintf("comment %d by %s on %s", c.Index, c.Author, c.Ref())
+}
+
+// Ref returns the reference of the commented resource
+func (c Comment) Ref() Ref {
+	return Ref{Owner: c.Owner, Target: c.Target, Index: c.Resource}
+}
+
+// Validate checks if the body of the comment is not empty nor too long
+func Validate(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("%w: comment requires a body", ErrInvalidFields)
+	}
+
+	if n := len([]rune(body)); n > MaxLength {
+		return fmt.Errorf("%w: comment of %d characters, expected up to %d", ErrInvalidFields, n, MaxLength)
+	}
+
+	return nil
+}
+
+// Thread nests the replies of the comments under the comment they reply
+// to, the comments must be ordered by creation. Replies to unknown
+// comments are returned as top level comments
+func Thread(comments []Comment) []Comment {
+	children := make(map[int][]Comment)
+	known := make(map[int]bool, len(comments))
+	for _, c := range comments {
+		known[c.Index] = true
+	}
+
+	var roots []int
+	for i, c := range comments {
+		if c.Parent != 0 && known[c.Parent] {
+			children[c.Parent] = append(children[c.Parent], c)
+			continue
+		}
+		roots = append(roots, i)
+	}
+
+	var nest func(c Comment) Comment
+	nest = func(c Comment) Comment {
+		for _, r := range children[c.Index] {
+			c.Replies = append(c.Replies, nest(r))
+		}
+		return c
+	}
+
+	thread := make([]Comment, 0, len(roots))
+	for _, i := range roots {
+		thread = append(thread, nest(comments[i]))
+	}
+
+	return thread
+}
@@ -0,0 +1,185 @@
+package comment
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/scrot/musclemem-api/internal/api"
+)
+
+// NewThreadHandler returns the comments on a workout or session
+// with the replies nested under the comment they reply to
+// requires {username} and {workout} or {session} path variables
+func NewThreadHandler(l *slog.Logger, target Target, comments Retreiver) http.Handler {
+	l = l.With("handler", "ThreadHandler", "target", target)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l := l.With("user", r.PathValue("username"), string(target), r.PathValue(string(target)))
+
+		ref, err := parseRef(r, target)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		cs, err := comments.Thread(ref)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched comments", "count", len(cs))
+
+		if err := api.WriteJSON(w, http.StatusOK, cs); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewCreateHandler comments as the caller on a workout or session
+// requires {username} and {workout} or {session} path variables
+// requires json payload {"body": STRING, "parent": INT}
+func NewCreateHandler(l *slog.Logger, target Target, comments Storer) http.Handler {
+	l = l.With("handler", "CreateHandler", "target", target)
+
+	type Request struct {
+		Parent int    `json:"parent"`
+		Body   string `json:"body"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l := l.With("user", r.PathValue("username"), string(target), r.PathValue(string(target)))
+
+		ref, err := parseRef(r, target)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		req, err := api.ReadJSON[Request](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		c, err := comments.New(ref, api.Caller(r), req.Parent, req.Body)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s created", c))
+
+		if err := api.WriteJSON(w, http.StatusOK, c); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewEditHandler changes the body of a comment, only the author can edit a comment
+// requires {username}, {workout} or {session} and {comment} path variables
+// requires json payload {"body": STRING}
+func NewEditHandler(l *slog.Logger, target Target, comments Editor) http.Handler {
+	l = l.With("handler", "EditHandler", "target", target)
+
+	type Request struct {
+		Body string `json:"body"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l := l.With("user", r.PathValue("username"), string(target), r.PathValue(string(target)), "comment", r.PathValue("comment"))
+
+		ref, err := parseRef(r, target)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		ci, err := strconv.Atoi(r.PathValue("comment"))
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		req, err := api.ReadJSON[Request](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		c, err := comments.Edit(ref, ci, api.Caller(r), req.Body)
+		if err != nil {
+			if errors.Is(err, ErrForbidden) {
+				l.Debug(err.Error())
+				api.WriteForbiddenError(w)
+				return
+			}
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s edited", c))
+
+		if err := api.WriteJSON(w, http.StatusOK, c); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewDeleteHandler deletes a comment including its replies, only the
+// author or the owner of the workout or session can delete a comment
+// requires {username}, {workout} or {session} and {comment} path variables
+func NewDeleteHandler(l *slog.Logger, target Target, comments Deleter) http.Handler {
+	l = l.With("handler", "DeleteHandler", "target", target)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l := l.With("user", r.PathValue("username"), string(target), r.PathValue(string(target)), "comment", r.PathValue("comment"))
+
+		ref, err := parseRef(r, target)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		ci, err := strconv.Atoi(r.PathValue("comment"))
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		c, err := comments.Delete(ref, ci, api.Caller(r))
+		if err != nil {
+			if errors.Is(err, ErrForbidden) {
+				l.Debug(err.Error())
+				api.WriteForbiddenError(w)
+				return
+			}
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s deleted", c))
+
+		if err := api.WriteJSON(w, http.StatusOK, c); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// parseRef returns the reference of the commented resource from the path variables,
+// the index of the resource is the path variable named after the target
+func parseRef(r *http.Request, target Target) (Ref, error) {
+	index, err := strconv.Atoi(r.PathValue(string(target)))
+	if err != nil {
+		return Ref{}, fmt.Errorf("parse %s: %w", target, err)
+	}
+
+	return Ref{Owner: r.PathValue("username"), Target: target, Index: index}, nil
+}
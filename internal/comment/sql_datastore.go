@@ -0,0 +1,350 @@
+package comment
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/storage"
+)
+
+type SQLCommentStore struct {
+	*storage.SqlDatastore
+
+	// now returns the current time, replaceable for testing
+	now func() time.Time
+}
+
+func NewSQLCommentStore(db *storage.SqlDatastore) *SQLCommentStore {
+	return &SQLCommentStore{db, time.Now}
+}
+
+func (cs *SQLCommentStore) timestamp() time.Time {
+	return cs.now().UTC().Truncate(time.Second)
+}
+
+func (cs *SQLCommentStore) Thread(ref Ref) ([]Comment, error) {
+	if err := cs.exists(ref); err != nil {
+		return []Comment{}, fmt.Errorf("Thread: %w", err)
+	}
+
+	comments, err := cs.comments(ref)
+	if err != nil {
+		return []Comment{}, fmt.Errorf("Thread: %w", err)
+	}
+
+	return Thread(comments), nil
+}
+
+func (cs *SQLCommentStore) New(ref Ref, author string, parent int, body string) (Comment, error) {
+	const stmt = `
+  INSERT INTO %s (owner, %s, comment_index, parent, author, body, created_at)
+  VALUES ({{ .Owner }}, {{ .Resource }}, {{ .Index }}, {{ .Parent }}, {{ .Author }}, {{ .Body }}, {{ .CreatedAt }})
+  `
+
+	if author == "" {
+		return Comment{}, fmt.Errorf("New: %w: comment requires an author", ErrInvalidFields)
+	}
+
+	if err := Validate(body); err != nil {
+		return Comment{}, fmt.Errorf("New: %w", err)
+	}
+
+	if err := cs.exists(ref); err != nil {
+		return Comment{}, fmt.Errorf("New: %w", err)
+	}
+
+	if parent != 0 {
+		if _, err := cs.comment(ref, parent); err != nil {
+			return Comment{}, fmt.Errorf("New: parent: %w", err)
+		}
+	}
+
+	last, err := cs.lastIndex(ref)
+	if err != nil {
+		return Comment{}, fmt.Errorf("New: %w", err)
+	}
+
+	c := Comment{
+		Owner:     ref.Owner,
+		Target:    ref.Target,
+		Resource:  ref.Index,
+		Index:     last + 1,
+		Parent:    parent,
+		Author:    author,
+		Body:      body,
+		CreatedAt: cs.timestamp(),
+	}
+
+	table, column, err := ref.Target.columns()
+	if err != nil {
+		return Comment{}, fmt.Errorf("New: %w", err)
+	}
+
+	q, args, err := cs.CompileStatement(fmt.Sprintf(stmt, table, column), c)
+	if err != nil {
+		return Comment{}, fmt.Errorf("New: compile: %w", err)
+	}
+
+	if _, err := cs.Exec(q, args...); err != nil {
+		return Comment{}, fmt.Errorf("New: execute: %w", err)
+	}
+
+	return c, nil
+}
+
+func (cs *SQLCommentStore) Edit(ref Ref, comment int, caller string, body string) (Comment, error) {
+	const stmt = `
+  UPDATE %s
+  SET body = {{ .Body }}, updated_at = {{ .UpdatedAt }}
+  WHERE owner = {{ .Owner }} AND %s = {{ .Resource }} AND comment_index = {{ .Index }}
+  `
+
+	if err := Validate(body); err != nil {
+		return Comment{}, fmt.Errorf("Edit: %w", err)
+	}
+
+	c, err := cs.comment(ref, comment)
+	if err != nil {
+		return Comment{}, fmt.Errorf("Edit: %w", err)
+	}
+
+	if c.Author != caller {
+		return Comment{}, fmt.Errorf("Edit: %s by %s: %w", c, caller, ErrForbidden)
+	}
+
+	updated := cs.timestamp()
+	c.Body, c.UpdatedAt = body, &updated
+
+	table, column, err := ref.Target.columns()
+	if err != nil {
+		return Comment{}, fmt.Errorf("Edit: %w", err)
+	}
+
+	q, args, err := cs.CompileStatement(fmt.Sprintf(stmt, table, column), c)
+	if err != nil {
+		return Comment{}, fmt.Errorf("Edit: compile: %w", err)
+	}
+
+	if _, err := cs.Exec(q, args...); err != nil {
+		return Comment{}, fmt.Errorf("Edit: execute: %w", err)
+	}
+
+	return c, nil
+}
+
+func (cs *SQLCommentStore) Delete(ref Ref, comment int, caller string) (Comment, error) {
+	const stmt = `
+  DELETE FROM %s
+  WHERE owner = {{ .Owner }} AND %s = {{ .Resource }} AND comment_index = {{ .Index }}
+  `
+
+	c, err := cs.comment(ref, comment)
+	if err != nil {
+		return Comment{}, fmt.Errorf("Delete: %w", err)
+	}
+
+	if caller != c.Author && caller != c.Owner {
+		return Comment{}, fmt.Errorf("Delete: %s by %s: %w", c, caller, ErrForbidden)
+	}
+
+	comments, err := cs.comments(ref)
+	if err != nil {
+		return Comment{}, fmt.Errorf("Delete: %w", err)
+	}
+
+	table, column, err := ref.Target.columns()
+	if err != nil {
+		return Comment{}, fmt.Errorf("Delete: %w", err)
+	}
+
+	tx, err := cs.Begin()
+	if err != nil {
+		return Comment{}, fmt.Errorf("Delete: begin transaction: %w", err)
+	}
+
+	// replies are deleted with the comment they reply to
+	for _, d := range descendants(comments, c) {
+		q, args, err := cs.CompileStatement(fmt.Sprintf(stmt, table, column), d)
+		if err != nil {
+			tx.Rollback()
+			return Comment{}, fmt.Errorf("Delete: compile: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			tx.Rollback()
+			return Comment{}, fmt.Errorf("Delete: execute: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Comment{}, fmt.Errorf("Delete: commit transaction: %w", err)
+	}
+
+	return c, nil
+}
+
+// descendants returns c and all comments replying to it directly or indirectly
+func descendants(comments []Comment, c Comment) []Comment {
+	ds := []Comment{c}
+	for _, r := range comments {
+		if r.Parent == c.Index && r.Index != c.Index {
+			ds = append(ds, descendants(comments, r)...)
+		}
+	}
+	return ds
+}
+
+// columns returns the table the comments of the target are stored
+// in and the column referencing the commented resource
+func (t Target) columns() (string, string, error) {
+	switch t {
+	case TargetWorkout:
+		return "workout_comments", "workout", nil
+	case TargetSession:
+		return "session_comments", "session", nil
+	default:
+		return "", "", fmt.Errorf("%w: unknown target %q", ErrInvalidFields, t)
+	}
+}
+
+// resources returns the table of the resources of the target and its index column
+func (t Target) resources() (string, string, error) {
+	switch t {
+	case TargetWorkout:
+		return "workouts", "workout_index", nil
+	case TargetSession:
+		return "sessions", "session_index", nil
+	default:
+		return "", "", fmt.Errorf("%w: unknown target %q", ErrInvalidFields, t)
+	}
+}
+
+// exists returns an ErrNotFound error if the referenced resource doesn't exist
+func (cs *SQLCommentStore) exists(ref Ref) error {
+	const stmt = `
+  SELECT COUNT(*)
+  FROM %s
+  WHERE owner = {{ .Owner }} AND %s = {{ .Index }}
+  `
+
+	table, column, err := ref.Target.resources()
+	if err != nil {
+		return err
+	}
+
+	q, args, err := cs.CompileStatement(fmt.Sprintf(stmt, table, column), ref)
+	if err != nil {
+		return fmt.Errorf("exists: compile: %w", err)
+	}
+
+	var count int
+	if err := cs.QueryRow(q, args...).Scan(&count); err != nil {
+		return fmt.Errorf("exists: query: %w", err)
+	}
+
+	if count == 0 {
+		return fmt.Errorf("%s: %w", ref, ErrNotFound)
+	}
+
+	return nil
+}
+
+// comment returns the comment on the referenced resource,
+// it returns an ErrNotFound error if there is none
+func (cs *SQLCommentStore) comment(ref Ref, comment int) (Comment, error) {
+	comments, err := cs.comments(ref)
+	if err != nil {
+		return Comment{}, err
+	}
+
+	for _, c := range comments {
+		if c.Index == comment {
+			return c, nil
+		}
+	}
+
+	return Comment{}, fmt.Errorf("comment %d on %s: %w", comment, ref, ErrNotFound)
+}
+
+// comments returns all comments on the referenced resource ordered by creation
+func (cs *SQLCommentStore) comments(ref Ref) ([]Comment, error) {
+	const stmt = `
+  SELECT comment_index, parent, author, body, created_at, updated_at
+  FROM %s
+  WHERE owner = {{ .Owner }} AND %s = {{ .Index }}
+  ORDER BY comment_index
+  `
+
+	table, column, err := ref.Target.columns()
+	if err != nil {
+		return nil, err
+	}
+
+	q, args, err := cs.CompileStatement(fmt.Sprintf(stmt, table, column), ref)
+	if err != nil {
+		return nil, fmt.Errorf("comments: compile: %w", err)
+	}
+
+	rows, err := cs.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("comments: query: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []Comment{}
+	for rows.Next() {
+		var (
+			c       = Comment{Owner: ref.Owner, Target: ref.Target, Resource: ref.Index}
+			updated sql.NullTime
+		)
+
+		if err := rows.Scan(&c.Index, &c.Parent, &c.Author, &c.Body, &c.CreatedAt, &updated); err != nil {
+			return nil, fmt.Errorf("comments: scan: %w", err)
+		}
+
+		c.CreatedAt = c.CreatedAt.UTC()
+		if updated.Valid {
+			t := updated.Time.UTC()
+			c.UpdatedAt = &t
+		}
+		comments = append(comments, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("comments: rows: %w", err)
+	}
+
+	return comments, nil
+}
+
+// lastIndex returns the last comment index on the referenced resource
+// if the index is 0 and no error, then there are no comments
+func (cs *SQLCommentStore) lastIndex(ref Ref) (int, error) {
+	const stmt = `
+  SELECT MAX(comment_index)
+  FROM %s
+  WHERE owner = {{ .Owner }} AND %s = {{ .Index }}
+  `
+
+	table, column, err := ref.Target.columns()
+	if err != nil {
+		return 0, fmt.Errorf("lastIndex: %w", err)
+	}
+
+	q, args, err := cs.CompileStatement(fmt.Sprintf(stmt, table, column), ref)
+	if err != nil {
+		return 0, fmt.Errorf("lastIndex: compile: %w", err)
+	}
+
+	var index sql.NullInt32
+	if err := cs.QueryRow(q, args...).Scan(&index); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("lastIndex: query: %w", err)
+	}
+
+	return int(index.Int32), nil
+}
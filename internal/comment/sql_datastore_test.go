@@ -0,0 +1,147 @@
+package comment
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/user"
+	"github.com/scrot/musclemem-api/internal/workout"
+)
+
+func TestThread(t *testing.T) {
+	comments, flush := mockCommentStore(t)
+	defer flush()
+
+	ref := Ref{Owner: "user", Target: TargetWorkout, Index: 1}
+
+	if _, err := comments.New(Ref{Owner: "user", Target: TargetWorkout, Index: 2}, "coach", 0, "ok"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v commenting on unknown workout but got %v", ErrNotFound, err)
+	}
+
+	if _, err := comments.New(ref, "coach", 0, " "); !errors.Is(err, ErrInvalidFields) {
+		t.Errorf("want error %v commenting without body but got %v", ErrInvalidFields, err)
+	}
+
+	if _, err := comments.New(ref, "coach", 5, "reply"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v replying to unknown comment but got %v", ErrNotFound, err)
+	}
+
+	for _, c := range []struct {
+		author string
+		parent int
+		body   string
+	}{
+		{"coach", 0, "go deeper on the squats"},
+		{"user", 1, "will do"},
+		{"coach", 2, "great"},
+		{"user", 0, "felt heavy today"},
+	} {
+		if _, err := comments.New(ref, c.author, c.parent, c.body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	thread, err := comments.Thread(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(thread) != 2 || thread[0].Index != 1 || thread[1].Index != 4 {
+		t.Fatalf("want comments 1 and 4 at top level but got %+v", thread)
+	}
+
+	if len(thread[0].Replies) != 1 || len(thread[0].Replies[0].Replies) != 1 || thread[0].Replies[0].Replies[0].Body != "great" {
+		t.Errorf("want nested replies under comment 1 but got %+v", thread[0].Replies)
+	}
+
+	// comments on sessions are separate from comments on workouts
+	if _, err := comments.Thread(Ref{Owner: "user", Target: TargetSession, Index: 1}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v on unknown session but got %v", ErrNotFound, err)
+	}
+}
+
+func TestEditDelete(t *testing.T) {
+	comments, flush := mockCommentStore(t)
+	defer flush()
+
+	ref := Ref{Owner: "user", Target: TargetWorkout, Index: 1}
+
+	c, err := comments.New(ref, "coach", 0, "go deeper")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := comments.New(ref, "user", c.Index, "will do"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := comments.Edit(ref, c.Index, "user", "changed"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("want error %v editing comment of another author but got %v", ErrForbidden, err)
+	}
+
+	edited, err := comments.Edit(ref, c.Index, "coach", "go deeper on the squats")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if edited.Body != "go deeper on the squats" || edited.UpdatedAt == nil || !edited.UpdatedAt.Equal(edited.CreatedAt) {
+		t.Errorf("want edited comment with update timestamp but got %+v", edited)
+	}
+
+	if _, err := comments.Delete(ref, c.Index, "other"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("want error %v deleting as stranger but got %v", ErrForbidden, err)
+	}
+
+	// the owner of the workout moderates the comments on it
+	if _, err := comments.Delete(ref, c.Index, "user"); err != nil {
+		t.Fatal(err)
+	}
+
+	thread, err := comments.Thread(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(thread) != 0 {
+		t.Errorf("want comment deleted including replies but got %+v", thread)
+	}
+}
+
+func mockCommentStore(t *testing.T) (*SQLCommentStore, func()) {
+	t.Helper()
+
+	config := storage.DatastoreConfig{
+		DatabaseURL:   "file://test.db?cache=shared&mode=memory",
+		MigrationPath: "migrations",
+		Overwrite:     false,
+	}
+
+	store, err := storage.NewSqlDatastore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users := user.NewSQLUserStore(store)
+	for _, username := range []string{"user", "coach"} {
+		if _, err := users.New(username, username+"@gmail.com", "secret"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := workout.NewSQLWorkoutStore(store).New("user", "lower"); err != nil {
+		t.Fatal(err)
+	}
+
+	flush := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal()
+		}
+	}
+
+	comments := NewSQLCommentStore(store)
+	comments.now = func() time.Time { return time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC) }
+
+	return comments, flush
+}
@@ -0,0 +1,45 @@
+package equipment
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidFields = errors.New("contains invalid fields")
+)
+
+// EquipmentStore represents the equipment profile repository
+type EquipmentStore interface {
+	Retreiver
+	Storer
+	Deleter
+	Rounder
+}
+
+// Retreiver implementations allow for equipment profiles to be queried
+type Retreiver interface {
+	// ByOwner returns the equipment profile of owner,
+	// or the default profile if owner has not set one
+	ByOwner(owner string) (Profile, error)
+}
+
+// Storer implementations allow for equipment profiles to be set
+type Storer interface {
+	// Set replaces the equipment profile of owner
+	Set(owner string, profile Profile) (Profile, error)
+}
+
+// Deleter implementations allow for equipment profiles to be deleted
+type Deleter interface {
+	// Delete deletes the equipment profile of owner,
+	// it returns the default profile that is used from now on
+	Delete(owner string) (Profile, error)
+}
+
+// Rounder implementations allow for weights to be rounded to
+// what the equipment of a user can load
+type Rounder interface {
+	// Rounding returns the rounding of the equipment profile of owner for
+	// the equipment of the catalog entry, weights of exercises without
+	// a known catalog entry are not rounded
+	Rounding(owner string, catalog string) (Rounding, error)
+}
@@ -0,0 +1,395 @@
+package equipment
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/scrot/musclemem-api/internal/catalog"
+	"github.com/scrot/musclemem-api/internal/strength"
+)
+
+// Profile is the equipment available to a user, it
+// determines which weights can actually be loaded
+type Profile struct {
+	Owner     string    `json:"owner"`
+	Bars      []Bar     `json:"bars"`
+	Plates    []Plate   `json:"plates"`
+	Dumbbells Dumbbells `json:"dumbbells"`
+	Machines  []Machine `json:"machines"`
+}
+
+func (p Profile) String() string {
+	return fmt.Sprintf("equipment of %s: %d bars, %d plates, %d machines",
+		p.Owner, len(p.Bars), len(p.Plates), len(p.Machines))
+}
+
+// Bar is a bar plates are loaded on, the name refers to the catalog
+// equipment it is used for, like barbell, ez_bar or trap_bar
+type Bar struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+}
+
+// Plate is a plate denomination, Count is the total number of plates
+// available which are loaded in pairs, one on each side of the bar
+type Plate struct {
+	Weight float64 `json:"weight"`
+	Count  int     `json:"count"`
+}
+
+// Dumbbells is a dumbbell rack from Min to Max in steps of Increment
+type Dumbbells struct {
+	Min       float64 `json:"min"`
+	Max       float64 `json:"max"`
+	Increment float64 `json:"increment"`
+}
+
+// Machine is a weight stack from Min to Max in steps of Increment, the
+// name refers to the catalog equipment it is used for, like machine or cable
+type Machine struct {
+	Name      string  `json:"name"`
+	Min       float64 `json:"min"`
+	Max       float64 `json:"max"`
+	Increment float64 `json:"increment"`
+}
+
+// DefaultProfile returns the equipment of a typical commercial gym,
+// used for users that have not set up their own profile
+func DefaultProfile(owner string) Profile {
+	return Profile{
+		Owner: owner,
+		Bars: []Bar{
+			{string(catalog.EquipmentBarbell), 20},
+			{string(catalog.EquipmentEZBar), 10},
+			{string(catalog.EquipmentTrapBar), 25},
+			{string(catalog.EquipmentSmith), 15},
+		},
+		Plates:    []Plate{{25, 8}, {20, 4}, {15, 2}, {10, 4}, {5, 4}, {2.5, 4}, {1.25, 4}},
+		Dumbbells: Dumbbells{2.5, 50, 2.5},
+		Machines: []Machine{
+			{string(catalog.EquipmentMachine), 5, 150, 5},
+			{string(catalog.EquipmentCable), 2.5, 100, 2.5},
+		},
+	}
+}
+
+// Validate checks if the profile is consistent
+func (p Profile) Validate() error {
+	bars := make(map[string]bool)
+	for _, b := range p.Bars {
+		if b.Name == "" || b.Weight < 0 {
+			return fmt.Errorf("%w: bar requires a name and a positive weight", ErrInvalidFields)
+		}
+		if bars[b.Name] {
+			return fmt.Errorf("%w: bar %q listed twice", ErrInvalidFields, b.Name)
+		}
+		bars[b.Name] = true
+	}
+
+	plates := make(map[float64]bool)
+	for _, pl := range p.Plates {
+		if pl.Weight <= 0 || pl.Count < 0 {
+			return fmt.Errorf("%w: plate %.2f x %d, expected a positive weight and count", ErrInvalidFields, pl.Weight, pl.Count)
+		}
+		if plates[pl.Weight] {
+			return fmt.Errorf("%w: plate %.2f listed twice", ErrInvalidFields, pl.Weight)
+		}
+		plates[pl.Weight] = true
+	}
+
+	d := p.Dumbbells
+	if d != (Dumbbells{}) && (d.Min <= 0 || d.Max < d.Min || d.Increment <= 0) {
+		return fmt.Errorf("%w: dumbbells %.2f to %.2f by %.2f", ErrInvalidFields, d.Min, d.Max, d.Increment)
+	}
+
+	machines := make(map[string]bool)
+	for _, m := range p.Machines {
+		if m.Name == "" || m.Min < 0 || m.Max < m.Min || m.Increment <= 0 {
+			return fmt.Errorf("%w: machine %q %.2f to %.2f by %.2f", ErrInvalidFields, m.Name, m.Min, m.Max, m.Increment)
+		}
+		if machines[m.Name] {
+			return fmt.Errorf("%w: machine %q listed twice", ErrInvalidFields, m.Name)
+		}
+		machines[m.Name] = true
+	}
+
+	return nil
+}
+
+// Bar returns the bar with name, or the first bar when there is no
+// such bar, it reports false when the profile has no bars
+func (p Profile) Bar(name string) (Bar, bool) {
+	for _, b := range p.Bars {
+		if b.Name == name {
+			return b, true
+		}
+	}
+
+	if len(p.Bars) == 0 {
+		return Bar{}, false
+	}
+
+	return p.Bars[0], true
+}
+
+// Machine returns the machine with name, or the first machine when
+// there is no such machine, it reports false when the profile has no machines
+func (p Profile) Machine(name string) (Machine, bool) {
+	for _, m := range p.Machines {
+		if m.Name == name {
+			return m, true
+		}
+	}
+
+	if len(p.Machines) == 0 {
+		return Machine{}, false
+	}
+
+	return p.Machines[0], true
+}
+
+// Load is the result of the plate calculator, Weight is the weight nearest
+// to Target that can be loaded with Plates on each side of the Bar
+type Load struct {
+	Target float64 `json:"target"`
+	Weight float64 `json:"weight"`
+	Exact  bool    `json:"exact"`
+	Bar    Bar     `json:"bar"`
+	Plates []Plate `json:"plates_per_side"`
+}
+
+// Load returns the plates to load on each side of the bar with name to get
+// as close as possible to target, preferring the lighter weight on a tie
+func (p Profile) Load(target float64, bar string) (Load, error) {
+	b, ok := p.Bar(bar)
+	if !ok {
+		return Load{}, fmt.Errorf("%w: no bars available", ErrNotFound)
+	}
+
+	if target < 0 {
+		return Load{}, fmt.Errorf("%w: negative target %.2f", ErrInvalidFields, target)
+	}
+
+	sides := p.sides()
+
+	side := 0
+	if s := units(target-b.Weight) / 2; s > 0 {
+		side = nearest(sides.sums(), s)
+	}
+
+	l := Load{Target: target, Weight: b.Weight + 2*weight(side), Bar: b, Plates: sides.plates(side)}
+	l.Exact = units(l.Weight) == units(target)
+
+	return l, nil
+}
+
+// Rounding rounds weights to what can be loaded with the
+// equipment of a profile for a kind of catalog equipment
+type Rounding struct {
+	Profile   Profile           `json:"profile"`
+	Equipment catalog.Equipment `json:"equipment"`
+}
+
+// Rounding returns the rounding of the profile for equipment
+func (p Profile) Rounding(e catalog.Equipment) Rounding {
+	return Rounding{p, e}
+}
+
+// Round returns the loadable weight nearest to w, preferring the lighter
+// weight on a tie. Weights of equipment that isn't loaded, like bodyweight
+// or bands, are returned unchanged
+func (r Rounding) Round(w float64) float64 {
+	ws := r.weights()
+	if len(ws) == 0 {
+		return w
+	}
+	return weight(nearest(ws, units(w)))
+}
+
+// Up returns the lightest loadable weight at or above w, or the
+// heaviest loadable weight if w can't be reached
+func (r Rounding) Up(w float64) float64 {
+	ws := r.weights()
+	if len(ws) == 0 {
+		return w
+	}
+
+	i := sort.SearchInts(ws, units(w))
+	if i == len(ws) {
+		i--
+	}
+
+	return weight(ws[i])
+}
+
+// Min returns the lightest loadable weight, like the empty bar,
+// it returns 0 for equipment that isn't loaded
+func (r Rounding) Min() float64 {
+	ws := r.weights()
+	if len(ws) == 0 {
+		return 0
+	}
+	return weight(ws[0])
+}
+
+// Warmup returns the warm-up config rounding to the equipment starting from
+// the lightest loadable weight, configs with an explicit bar or plates
+// are returned unchanged
+func (r Rounding) Warmup(c strength.WarmupConfig) strength.WarmupConfig {
+	if c.Bar != 0 || len(c.Plates) != 0 || len(r.weights()) == 0 {
+		return c
+	}
+
+	c.Bar = r.Min()
+	c.Round = r.Round
+
+	return c
+}
+
+// weights returns the sorted loadable weights in units of the equipment
+func (r Rounding) weights() []int {
+	switch r.Equipment {
+	case catalog.EquipmentBarbell, catalog.EquipmentEZBar, catalog.EquipmentTrapBar, catalog.EquipmentSmith:
+		b, ok := r.Profile.Bar(string(r.Equipment))
+		if !ok {
+			return nil
+		}
+
+		var ws []int
+		for _, s := range r.Profile.sides().sums() {
+			ws = append(ws, units(b.Weight)+2*s)
+		}
+		return ws
+	case catalog.EquipmentDumbbell, catalog.EquipmentKettlebell:
+		d := r.Profile.Dumbbells
+		return steps(d.Min, d.Max, d.Increment)
+	case catalog.EquipmentMachine, catalog.EquipmentCable:
+		m, ok := r.Profile.Machine(string(r.Equipment))
+		if !ok {
+			return nil
+		}
+		return steps(m.Min, m.Max, m.Increment)
+	default:
+		return nil
+	}
+}
+
+// sides contains every pair of plates as a separate item, heaviest first
+type sides []int
+
+func (p Profile) sides() sides {
+	ps := append([]Plate{}, p.Plates...)
+	sort.Slice(ps, func(i, j int) bool { return ps[i].Weight > ps[j].Weight })
+
+	var s sides
+	for _, pl := range ps {
+		for i := 0; i < pl.Count/2; i++ {
+			s = append(s, units(pl.Weight))
+		}
+	}
+
+	return s
+}
+
+// reach returns for every weight that can be loaded on one side the index
+// of the last pair used to reach it, -1 for the empty side and -2 if the
+// weight can't be reached
+func (s sides) reach() []int {
+	total := 0
+	for _, w := range s {
+		total += w
+	}
+
+	last := make([]int, total+1)
+	for i := range last {
+		last[i] = -2
+	}
+	last[0] = -1
+
+	// every pair is used at most once, heavier pairs first
+	for i, w := range s {
+		for sum := total; sum >= w; sum-- {
+			if last[sum] == -2 && last[sum-w] != -2 {
+				last[sum] = i
+			}
+		}
+	}
+
+	return last
+}
+
+// sums returns the sorted weights that can be loaded on one side
+func (s sides) sums() []int {
+	var sums []int
+	for sum, i := range s.reach() {
+		if i != -2 {
+			sums = append(sums, sum)
+		}
+	}
+	return sums
+}
+
+// plates returns the plates loaded on one side to reach sum
+func (s sides) plates(sum int) []Plate {
+	var (
+		last   = s.reach()
+		plates = []Plate{}
+	)
+
+	for sum > 0 && last[sum] >= 0 {
+		w := s[last[sum]]
+		if n := len(plates); n > 0 && units(plates[n-1].Weight) == w {
+			plates[n-1].Count++
+		} else {
+			plates = append(plates, Plate{weight(w), 1})
+		}
+		sum -= w
+	}
+
+	sort.Slice(plates, func(i, j int) bool { return plates[i].Weight > plates[j].Weight })
+
+	return plates
+}
+
+// steps returns the weights from min to max in steps of increment in units
+func steps(min float64, max float64, increment float64) []int {
+	if increment <= 0 {
+		return nil
+	}
+
+	var ws []int
+	for w := units(min); w <= units(max); w += units(increment) {
+		ws = append(ws, w)
+	}
+
+	return ws
+}
+
+// nearest returns the value of the sorted values nearest to v,
+// preferring the lower value on a tie
+func nearest(values []int, v int) int {
+	i := sort.SearchInts(values, v)
+
+	switch {
+	case i == len(values):
+		return values[i-1]
+	case values[i] == v || i == 0:
+		return values[i]
+	case v-values[i-1] <= values[i]-v:
+		return values[i-1]
+	default:
+		return values[i]
+	}
+}
+
+// units converts a weight to hundredths, so weights can be compared
+// and summed without floating point errors
+func units(w float64) int {
+	return int(math.Round(w * 100))
+}
+
+// weight converts hundredths back to a weight
+func weight(u int) float64 {
+	return float64(u) / 100
+}
@@ -0,0 +1,104 @@
+package equipment
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/scrot/musclemem-api/internal/catalog"
+)
+
+func TestLoad(t *testing.T) {
+	limited := Profile{Bars: []Bar{{"barbell", 20}}, Plates: []Plate{{20, 2}, {5, 3}}}
+
+	cs := []struct {
+		name    string
+		profile Profile
+		target  float64
+		bar     string
+		want    Load
+	}{
+		{"exact", DefaultProfile("user"), 100, "", Load{100, 100, true, Bar{"barbell", 20}, []Plate{{20, 2}}}},
+		{"mixed", DefaultProfile("user"), 142.5, "", Load{142.5, 142.5, true, Bar{"barbell", 20}, []Plate{{25, 1}, {20, 1}, {15, 1}, {1.25, 1}}}},
+		{"nearest", DefaultProfile("user"), 101, "", Load{101, 100, false, Bar{"barbell", 20}, []Plate{{20, 2}}}},
+		{"namedBar", DefaultProfile("user"), 30, "ez_bar", Load{30, 30, true, Bar{"ez_bar", 10}, []Plate{{10, 1}}}},
+		{"belowBar", DefaultProfile("user"), 10, "", Load{10, 20, false, Bar{"barbell", 20}, []Plate{}}},
+		{"limitedPlates", limited, 100, "", Load{100, 70, false, Bar{"barbell", 20}, []Plate{{20, 1}, {5, 1}}}},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := c.profile.Load(c.target, c.bar)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("want %+v but got %+v", c.want, got)
+			}
+		})
+	}
+
+	if _, err := (Profile{}).Load(100, ""); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want error %v but got %v", ErrNotFound, err)
+	}
+}
+
+func TestRounding(t *testing.T) {
+	p := DefaultProfile("user")
+
+	cs := []struct {
+		equipment catalog.Equipment
+		weight    float64
+		round     float64
+		up        float64
+	}{
+		{catalog.EquipmentBarbell, 101, 100, 102.5},
+		{catalog.EquipmentBarbell, 101.25, 100, 102.5},
+		{catalog.EquipmentBarbell, 15, 20, 20},
+		{catalog.EquipmentBarbell, 1000, 405, 405},
+		{catalog.EquipmentTrapBar, 26, 25, 27.5},
+		{catalog.EquipmentDumbbell, 23, 22.5, 25},
+		{catalog.EquipmentDumbbell, 60, 50, 50},
+		{catalog.EquipmentMachine, 7, 5, 10},
+		{catalog.EquipmentCable, 7, 7.5, 7.5},
+		{catalog.EquipmentBodyweight, 7.3, 7.3, 7.3},
+		{"", 61.3, 61.3, 61.3},
+	}
+
+	for _, c := range cs {
+		r := p.Rounding(c.equipment)
+
+		if got := r.Round(c.weight); got != c.round {
+			t.Errorf("%s %.2f: want round %.2f but got %.2f", c.equipment, c.weight, c.round, got)
+		}
+
+		if got := r.Up(c.weight); got != c.up {
+			t.Errorf("%s %.2f: want up %.2f but got %.2f", c.equipment, c.weight, c.up, got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cs := []struct {
+		name    string
+		profile Profile
+		wantErr error
+	}{
+		{"default", DefaultProfile("user"), nil},
+		{"empty", Profile{}, nil},
+		{"duplicateBar", Profile{Bars: []Bar{{"barbell", 20}, {"barbell", 15}}}, ErrInvalidFields},
+		{"duplicatePlate", Profile{Plates: []Plate{{20, 2}, {20, 4}}}, ErrInvalidFields},
+		{"negativePlates", Profile{Plates: []Plate{{20, -2}}}, ErrInvalidFields},
+		{"dumbbellRange", Profile{Dumbbells: Dumbbells{10, 5, 2.5}}, ErrInvalidFields},
+		{"machineIncrement", Profile{Machines: []Machine{{"machine", 5, 100, 0}}}, ErrInvalidFields},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.profile.Validate(); !errors.Is(err, c.wantErr) {
+				t.Fatalf("want error %v but got %v", c.wantErr, err)
+			}
+		})
+	}
+}
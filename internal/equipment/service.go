@@ -0,0 +1,137 @@
+package equipment
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/scrot/musclemem-api/internal/api"
+)
+
+// NewFetchHandler returns the equipment profile of a user
+// requires {username} path variable
+func NewFetchHandler(l *slog.Logger, equipment Retreiver) http.Handler {
+	l = l.With("handler", "FetchHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		p, err := equipment.ByOwner(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("fetched %s", p))
+
+		if err := api.WriteJSON(w, http.StatusOK, p); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewSetHandler replaces the equipment profile of a user
+// requires {username} path variable
+// requires json payload {"bars": [{"name": NAME, "weight": WEIGHT}], "plates": [{"weight": WEIGHT, "count": N}],
+// "dumbbells": {"min": WEIGHT, "max": WEIGHT, "increment": WEIGHT},
+// "machines": [{"name": NAME, "min": WEIGHT, "max": WEIGHT, "increment": WEIGHT}]}
+func NewSetHandler(l *slog.Logger, equipment Storer) http.Handler {
+	l = l.With("handler", "SetHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		p, err := api.ReadJSON[Profile](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		set, err := equipment.Set(username, p)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s set", set))
+
+		if err := api.WriteJSON(w, http.StatusOK, set); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewDeleteHandler deletes the equipment profile of a user
+// returning the default profile that is used instead
+// requires {username} path variable
+func NewDeleteHandler(l *slog.Logger, equipment Deleter) http.Handler {
+	l = l.With("handler", "DeleteHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		p, err := equipment.Delete(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("equipment profile deleted")
+
+		if err := api.WriteJSON(w, http.StatusOK, p); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewPlatesHandler returns the plates to load on each side of a bar
+// for a target weight, or those of the nearest weight that can be loaded
+// requires {username} path variable and weight query parameter,
+// optional bar query parameter naming the bar, the first bar by default
+func NewPlatesHandler(l *slog.Logger, equipment Retreiver) http.Handler {
+	l = l.With("handler", "PlatesHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			params   = r.URL.Query()
+		)
+
+		l := l.With("user", username, "weight", params.Get("weight"), "bar", params.Get("bar"))
+
+		target, err := strconv.ParseFloat(params.Get("weight"), 64)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "invalid weight")
+			return
+		}
+
+		p, err := equipment.ByOwner(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		load, err := p.Load(target, params.Get("bar"))
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("calculated plates", "loaded", load.Weight, "exact", load.Exact)
+
+		if err := api.WriteJSON(w, http.StatusOK, load); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
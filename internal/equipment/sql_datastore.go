@@ -0,0 +1,313 @@
+package equipment
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/scrot/musclemem-api/internal/catalog"
+	"github.com/scrot/musclemem-api/internal/storage"
+)
+
+type SQLEquipmentStore struct {
+	*storage.SqlDatastore
+}
+
+func NewSQLEquipmentStore(db *storage.SqlDatastore) *SQLEquipmentStore {
+	return &SQLEquipmentStore{db}
+}
+
+func (es *SQLEquipmentStore) ByOwner(owner string) (Profile, error) {
+	const (
+		profileStmt = `
+    SELECT owner, dumbbell_min, dumbbell_max, dumbbell_increment
+    FROM equipment_profiles
+    WHERE owner = {{ . }}
+    `
+
+		barsStmt = `
+    SELECT name, weight
+    FROM equipment_bars
+    WHERE owner = {{ . }}
+    ORDER BY bar_index
+    `
+
+		platesStmt = `
+    SELECT weight, quantity
+    FROM equipment_plates
+    WHERE owner = {{ . }}
+    ORDER BY weight DESC
+    `
+
+		machinesStmt = `
+    SELECT name, min_weight, max_weight, increment
+    FROM equipment_machines
+    WHERE owner = {{ . }}
+    ORDER BY machine_index
+    `
+	)
+
+	if owner == "" {
+		return Profile{}, fmt.Errorf("ByOwner: %w", ErrInvalidFields)
+	}
+
+	q, args, err := es.CompileStatement(profileStmt, owner)
+	if err != nil {
+		return Profile{}, fmt.Errorf("ByOwner: compile: %w", err)
+	}
+
+	var p Profile
+	if err := es.QueryRow(q, args...).Scan(&p.Owner, &p.Dumbbells.Min, &p.Dumbbells.Max, &p.Dumbbells.Increment); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return DefaultProfile(owner), nil
+		}
+		return Profile{}, fmt.Errorf("ByOwner: query: %w", err)
+	}
+
+	// query executes stmt scanning each row using scan
+	query := func(stmt string, scan func(*sql.Rows) error) error {
+		q, args, err := es.CompileStatement(stmt, owner)
+		if err != nil {
+			return fmt.Errorf("compile: %w", err)
+		}
+
+		rows, err := es.Query(q, args...)
+		if err != nil {
+			return fmt.Errorf("query: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			if err := scan(rows); err != nil {
+				return fmt.Errorf("scan: %w", err)
+			}
+		}
+
+		return rows.Err()
+	}
+
+	p.Bars = []Bar{}
+	if err := query(barsStmt, func(rows *sql.Rows) error {
+		var b Bar
+		if err := rows.Scan(&b.Name, &b.Weight); err != nil {
+			return err
+		}
+		p.Bars = append(p.Bars, b)
+		return nil
+	}); err != nil {
+		return Profile{}, fmt.Errorf("ByOwner: bars: %w", err)
+	}
+
+	p.Plates = []Plate{}
+	if err := query(platesStmt, func(rows *sql.Rows) error {
+		var pl Plate
+		if err := rows.Scan(&pl.Weight, &pl.Count); err != nil {
+			return err
+		}
+		p.Plates = append(p.Plates, pl)
+		return nil
+	}); err != nil {
+		return Profile{}, fmt.Errorf("ByOwner: plates: %w", err)
+	}
+
+	p.Machines = []Machine{}
+	if err := query(machinesStmt, func(rows *sql.Rows) error {
+		var m Machine
+		if err := rows.Scan(&m.Name, &m.Min, &m.Max, &m.Increment); err != nil {
+			return err
+		}
+		p.Machines = append(p.Machines, m)
+		return nil
+	}); err != nil {
+		return Profile{}, fmt.Errorf("ByOwner: machines: %w", err)
+	}
+
+	return p, nil
+}
+
+func (es *SQLEquipmentStore) Set(owner string, profile Profile) (Profile, error) {
+	const (
+		profileStmt = `
+    INSERT INTO equipment_profiles (owner, dumbbell_min, dumbbell_max, dumbbell_increment)
+    VALUES ({{ .Owner }}, {{ .Dumbbells.Min }}, {{ .Dumbbells.Max }}, {{ .Dumbbells.Increment }})
+    ON CONFLICT (owner) DO UPDATE
+    SET dumbbell_min = excluded.dumbbell_min,
+      dumbbell_max = excluded.dumbbell_max,
+      dumbbell_increment = excluded.dumbbell_increment
+    `
+
+		barStmt = `
+    INSERT INTO equipment_bars (owner, bar_index, name, weight)
+    VALUES ({{ .Owner }}, {{ .Index }}, {{ .Name }}, {{ .Weight }})
+    `
+
+		plateStmt = `
+    INSERT INTO equipment_plates (owner, weight, quantity)
+    VALUES ({{ .Owner }}, {{ .Weight }}, {{ .Count }})
+    `
+
+		machineStmt = `
+    INSERT INTO equipment_machines (owner, machine_index, name, min_weight, max_weight, increment)
+    VALUES ({{ .Owner }}, {{ .Index }}, {{ .Name }}, {{ .Min }}, {{ .Max }}, {{ .Increment }})
+    `
+	)
+
+	if owner == "" {
+		return Profile{}, fmt.Errorf("Set: %w", ErrInvalidFields)
+	}
+
+	profile.Owner = owner
+	if err := profile.Validate(); err != nil {
+		return Profile{}, fmt.Errorf("Set: %w", err)
+	}
+
+	tx, err := es.Begin()
+	if err != nil {
+		return Profile{}, fmt.Errorf("Set: begin transaction: %w", err)
+	}
+
+	exec := func(stmt string, data any) error {
+		q, args, err := es.CompileStatement(stmt, data)
+		if err != nil {
+			return fmt.Errorf("compile: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			return fmt.Errorf("execute: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := exec(profileStmt, profile); err != nil {
+		tx.Rollback()
+		return Profile{}, fmt.Errorf("Set: %w", err)
+	}
+
+	for _, stmt := range clearStmts {
+		if err := exec(stmt, owner); err != nil {
+			tx.Rollback()
+			return Profile{}, fmt.Errorf("Set: clear: %w", err)
+		}
+	}
+
+	for i, b := range profile.Bars {
+		data := struct {
+			Owner string
+			Index int
+			Bar
+		}{owner, i + 1, b}
+
+		if err := exec(barStmt, data); err != nil {
+			tx.Rollback()
+			return Profile{}, fmt.Errorf("Set: bar: %w", err)
+		}
+	}
+
+	for _, pl := range profile.Plates {
+		data := struct {
+			Owner string
+			Plate
+		}{owner, pl}
+
+		if err := exec(plateStmt, data); err != nil {
+			tx.Rollback()
+			return Profile{}, fmt.Errorf("Set: plate: %w", err)
+		}
+	}
+
+	for i, m := range profile.Machines {
+		data := struct {
+			Owner string
+			Index int
+			Machine
+		}{owner, i + 1, m}
+
+		if err := exec(machineStmt, data); err != nil {
+			tx.Rollback()
+			return Profile{}, fmt.Errorf("Set: machine: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Profile{}, fmt.Errorf("Set: commit transaction: %w", err)
+	}
+
+	p, err := es.ByOwner(owner)
+	if err != nil {
+		return Profile{}, fmt.Errorf("Set: %w", err)
+	}
+
+	return p, nil
+}
+
+// clearStmts delete the equipment of a profile, the profile itself last
+var clearStmts = []string{
+	`DELETE FROM equipment_bars WHERE owner = {{ . }}`,
+	`DELETE FROM equipment_plates WHERE owner = {{ . }}`,
+	`DELETE FROM equipment_machines WHERE owner = {{ . }}`,
+}
+
+func (es *SQLEquipmentStore) Delete(owner string) (Profile, error) {
+	const stmt = `DELETE FROM equipment_profiles WHERE owner = {{ . }}`
+
+	if owner == "" {
+		return Profile{}, fmt.Errorf("Delete: %w", ErrInvalidFields)
+	}
+
+	tx, err := es.Begin()
+	if err != nil {
+		return Profile{}, fmt.Errorf("Delete: begin transaction: %w", err)
+	}
+
+	for _, stmt := range append(clearStmts, stmt) {
+		q, args, err := es.CompileStatement(stmt, owner)
+		if err != nil {
+			tx.Rollback()
+			return Profile{}, fmt.Errorf("Delete: compile: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			tx.Rollback()
+			return Profile{}, fmt.Errorf("Delete: execute: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Profile{}, fmt.Errorf("Delete: commit transaction: %w", err)
+	}
+
+	return DefaultProfile(owner), nil
+}
+
+func (es *SQLEquipmentStore) Rounding(owner string, entry string) (Rounding, error) {
+	const stmt = `
+  SELECT equipment
+  FROM catalog_exercises
+  WHERE (owner = '' AND slug = {{ . }}) OR owner || '/' || slug = {{ . }}
+  `
+
+	p, err := es.ByOwner(owner)
+	if err != nil {
+		return Rounding{}, fmt.Errorf("Rounding: %w", err)
+	}
+
+	if entry == "" {
+		return p.Rounding(""), nil
+	}
+
+	q, args, err := es.CompileStatement(stmt, entry)
+	if err != nil {
+		return Rounding{}, fmt.Errorf("Rounding: compile: %w", err)
+	}
+
+	var e catalog.Equipment
+	if err := es.QueryRow(q, args...).Scan(&e); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return p.Rounding(""), nil
+		}
+		return Rounding{}, fmt.Errorf("Rounding: query: %w", err)
+	}
+
+	return p.Rounding(e), nil
+}
@@ -0,0 +1,113 @@
+package equipment
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/scrot/musclemem-api/internal/catalog"
+	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/user"
+)
+
+func TestSetProfile(t *testing.T) {
+	equipment, flush := mockEquipmentStore(t)
+	defer flush()
+
+	got, err := equipment.ByOwner("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, DefaultProfile("user")) {
+		t.Fatalf("want default profile but got %+v", got)
+	}
+
+	home := Profile{
+		Bars:      []Bar{{"barbell", 15}},
+		Plates:    []Plate{{10, 4}, {2.5, 2}},
+		Dumbbells: Dumbbells{2, 24, 2},
+		Machines:  []Machine{},
+	}
+
+	if _, err := equipment.Set("user", Profile{Plates: []Plate{{0, 2}}}); !errors.Is(err, ErrInvalidFields) {
+		t.Fatalf("want error %v but got %v", ErrInvalidFields, err)
+	}
+
+	if _, err := equipment.Set("user", home); err != nil {
+		t.Fatal(err)
+	}
+
+	home.Owner = "user"
+	if got, err = equipment.ByOwner("user"); err != nil || !reflect.DeepEqual(got, home) {
+		t.Fatalf("want %+v but got %+v (%v)", home, got, err)
+	}
+
+	// the home profile has no machines, so machine weights are not rounded
+	cs := []struct {
+		entry  string
+		weight float64
+		want   float64
+	}{
+		{"barbell-back-squat", 57, 55},
+		{"dumbbell-curl", 12.5, 12},
+		{"leg-press", 37.3, 37.3},
+		{"", 37.3, 37.3},
+		{"unknown", 37.3, 37.3},
+	}
+
+	for _, c := range cs {
+		rounding, err := equipment.Rounding("user", c.entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := rounding.Round(c.weight); got != c.want {
+			t.Errorf("%q: want %.2f but got %.2f", c.entry, c.want, got)
+		}
+	}
+
+	if _, err := equipment.Delete("user"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err = equipment.ByOwner("user"); err != nil || !reflect.DeepEqual(got, DefaultProfile("user")) {
+		t.Fatalf("want default profile after delete but got %+v (%v)", got, err)
+	}
+}
+
+func mockEquipmentStore(t *testing.T) (*SQLEquipmentStore, func()) {
+	t.Helper()
+
+	config := storage.DatastoreConfig{
+		DatabaseURL:   "file://test.db?cache=shared&mode=memory",
+		MigrationPath: "migrations",
+		Overwrite:     false,
+	}
+
+	store, err := storage.NewSqlDatastore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := user.NewSQLUserStore(store).New("user", "test@gmail.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	library, err := catalog.Library()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := catalog.NewSQLCatalogStore(store).Seed(library); err != nil {
+		t.Fatal(err)
+	}
+
+	flush := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal()
+		}
+	}
+
+	return NewSQLEquipmentStore(store), flush
+}
@@ -11,6 +11,8 @@ var (
 
 	// TODO: custom error containing which fields are invalid
 	ErrInvalidFields = errors.New("contains invalid fields")
+
+	ErrSplitsGroup = errors.New("splits exercise group")
 )
 
 type ExerciseStore interface {
@@ -19,6 +21,8 @@ type ExerciseStore interface {
 	Updater
 	Deleter
 	Orderer
+	Grouper
+	Progressor
 }
 
 // Implementation of the Retreiver interface enables querying exercises
@@ -35,12 +39,18 @@ type Retreiver interface {
 type Storer interface {
 	// New stores an exercise at the tail,
 	// updating the references and returns the exercise.
-	// user and workout must exist before adding exercise
-	New(owner string, workout int, name string, weight float64, repetitions int) (Exercise, error)
+	// user and workout must exist before adding exercise,
+	// the fields of x must be valid for its kind. If x references
+	// a catalog entry the name defaults to the name of the entry
+	New(owner string, workout int, x Exercise) (Exercise, error)
 }
 
 // Implemention of Updater interface enables updating exercises
 type Updater interface {
+	// Update applies the patch to an existing exercise
+	// and validates the result before storing it
+	Update(owner string, workout int, exercise int, patch Patch) (Exercise, error)
+
 	// ChangeName updates the name of an existing exercise
 	ChangeName(owner string, workout int, exercise int, newName string) (Exercise, error)
 
@@ -60,8 +70,34 @@ type Deleter interface {
 type Orderer interface {
 	// Swap swaps the indices from the given exercises
 	// if the workout or index doesn't exist it returns an error
+	// exercises can only be swapped within the same group,
+	// otherwise it returns an ErrSplitsGroup error
 	Swap(owner string, workout int, e1 int, e2 int) error
 
 	// Len returns the length of all exercises of a workout
 	Len(owner string, workout int) (int, error)
 }
+
+// Implementation of the Grouper interface enables grouping
+// consecutive exercises into supersets, giant sets and circuits
+type Grouper interface {
+	// Group groups the consecutive exercises starting at from up until
+	// and including to, none of the exercises can be part of another group
+	Group(owner string, workout int, kind GroupKind, rounds int, from int, to int) (Group, error)
+
+	// Ungroup removes the group, leaving the exercises in place
+	Ungroup(owner string, workout int, group int) (Group, error)
+}
+
+// Implementation of the Progressor interface enables progressing
+// the planned load of exercises after each session
+type Progressor interface {
+	// Progress evaluates the progression rules of the workout exercises
+	// performed during a session, updating their planned load and
+	// returns the adjustments. Exercises that no longer exist or were
+	// already progressed for the session are skipped
+	Progress(owner string, session int, performed []Performed) ([]Adjustment, error)
+
+	// Progressions returns the adjustments of an exercise, most recent first
+	Progressions(owner string, workout int, exercise int) ([]Adjustment, error)
+}
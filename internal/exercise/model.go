@@ -10,23 +10,300 @@ import (
 // Exercise contains details of a single workout exercise
 // an exercise is a node in a linked list, determining the order
 type Exercise struct {
-	Owner       string  `json:"owner"`
-	Workout     int     `json:"workout"`
-	Index       int     `json:"index"`
-	Name        string  `json:"name"`
-	Weight      float64 `json:"weight"`
-	Repetitions int     `json:"repetitions"`
+	Owner           string       `json:"owner"`
+	Workout         int          `json:"workout"`
+	Index           int          `json:"index"`
+	Name            string       `json:"name"`
+	Catalog         string       `json:"catalog,omitempty"`
+	Kind            Kind         `json:"kind"`
+	Weight          float64      `json:"weight"`
+	Repetitions     int          `json:"repetitions"`
+	DurationSeconds int          `json:"duration_seconds,omitempty"`
+	DistanceMeters  float64      `json:"distance_meters,omitempty"`
+	RestSeconds     int          `json:"rest_seconds,omitempty"`
+	Tempo           string       `json:"tempo,omitempty"`
+	TargetRPE       float64      `json:"target_rpe,omitempty"`
+	TargetRIR       *int         `json:"target_rir,omitempty"`
+	Notes           string       `json:"notes,omitempty"`
+	Group           *Group       `json:"group,omitempty"`
+	Progression     *Progression `json:"progression,omitempty"`
+	E1RM            float64      `json:"e1rm,omitempty"`
 }
 
 // String prints the Exercise is a human readable format implementing
 // the String interface
 func (e Exercise) String() string {
-	return fmt.Sprintf("exercise %s: name %s, weight %.1f, reps %d",
-		e.Ref(),
-		e.Name,
-		e.Weight,
-		e.Repetitions,
-	)
+	switch e.Kind {
+	case KindDuration:
+		return fmt.Sprintf("exercise %s: name %s, kind %s, weight %.1f, duration %ds",
+			e.Ref(), e.Name, e.Kind, e.Weight, e.DurationSeconds)
+	case KindDistance:
+		return fmt.Sprintf("exercise %s: name %s, kind %s, weight %.1f, distance %.1fm",
+			e.Ref(), e.Name, e.Kind, e.Weight, e.DistanceMeters)
+	case KindDistanceTime:
+		return fmt.Sprintf("exercise %s: name %s, kind %s, distance %.1fm, duration %ds",
+			e.Ref(), e.Name, e.Kind, e.DistanceMeters, e.DurationSeconds)
+	default:
+		return fmt.Sprintf("exercise %s: name %s, kind %s, weight %.1f, reps %d",
+			e.Ref(), e.Name, e.Kind, e.Weight, e.Repetitions)
+	}
+}
+
+// Validate checks whether the fields of the exercise are consistent
+// with its kind, an empty kind is treated as KindWeighted
+func (e Exercise) Validate() error {
+	if e.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidFields)
+	}
+
+	if e.Weight < 0 || e.Repetitions < 0 || e.DurationSeconds < 0 || e.DistanceMeters < 0 {
+		return fmt.Errorf("%w: negative values are not allowed", ErrInvalidFields)
+	}
+
+	kind := e.Kind
+	if kind == "" {
+		kind = KindWeighted
+	}
+
+	switch kind {
+	case KindWeighted, KindBodyweight:
+		if e.Repetitions == 0 {
+			return fmt.Errorf("%w: %s requires repetitions", ErrInvalidFields, kind)
+		}
+	case KindAssisted:
+		if e.Repetitions == 0 || e.Weight == 0 {
+			return fmt.Errorf("%w: %s requires repetitions and assistance weight", ErrInvalidFields, kind)
+		}
+	case KindDuration:
+		if e.DurationSeconds == 0 {
+			return fmt.Errorf("%w: %s requires duration_seconds", ErrInvalidFields, kind)
+		}
+	case KindDistance:
+		if e.DistanceMeters == 0 {
+			return fmt.Errorf("%w: %s requires distance_meters", ErrInvalidFields, kind)
+		}
+	case KindDistanceTime:
+		if e.DistanceMeters == 0 || e.DurationSeconds == 0 {
+			return fmt.Errorf("%w: %s requires distance_meters and duration_seconds", ErrInvalidFields, kind)
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidFields, kind)
+	}
+
+	if !kind.UsesRepetitions() && e.Repetitions != 0 {
+		return fmt.Errorf("%w: %s does not take repetitions", ErrInvalidFields, kind)
+	}
+
+	if !kind.usesDuration() && e.DurationSeconds != 0 {
+		return fmt.Errorf("%w: %s does not take duration_seconds", ErrInvalidFields, kind)
+	}
+
+	if !kind.usesDistance() && e.DistanceMeters != 0 {
+		return fmt.Errorf("%w: %s does not take distance_meters", ErrInvalidFields, kind)
+	}
+
+	if kind == KindDistanceTime && e.Weight != 0 {
+		return fmt.Errorf("%w: %s does not take weight", ErrInvalidFields, kind)
+	}
+
+	if e.RestSeconds < 0 {
+		return fmt.Errorf("%w: negative rest_seconds", ErrInvalidFields)
+	}
+
+	if e.Tempo != "" && !validTempo(e.Tempo) {
+		return fmt.Errorf("%w: tempo %q, expected four phases like 3-1-1-0", ErrInvalidFields, e.Tempo)
+	}
+
+	// RPE is rated in half points, 0 means no target
+	if e.TargetRPE != 0 && (e.TargetRPE < 1 || e.TargetRPE > 10 || e.TargetRPE*2 != float64(int(e.TargetRPE*2))) {
+		return fmt.Errorf("%w: target_rpe %.1f, expected 1 to 10 in steps of 0.5", ErrInvalidFields, e.TargetRPE)
+	}
+
+	if e.TargetRIR != nil && (*e.TargetRIR < 0 || *e.TargetRIR > 10) {
+		return fmt.Errorf("%w: target_rir %d, expected 0 to 10", ErrInvalidFields, *e.TargetRIR)
+	}
+
+	if len(e.Notes) > maxNotesLength {
+		return fmt.Errorf("%w: notes exceed %d characters", ErrInvalidFields, maxNotesLength)
+	}
+
+	if p := e.Progression; p != nil {
+		if err := p.Validate(kind); err != nil {
+			return err
+		}
+
+		if p.Rule == RuleDouble && (e.Repetitions < p.MinRepetitions || e.Repetitions > p.MaxRepetitions) {
+			return fmt.Errorf("%w: repetitions %d outside progression range %d to %d",
+				ErrInvalidFields, e.Repetitions, p.MinRepetitions, p.MaxRepetitions)
+		}
+	}
+
+	return nil
+}
+
+const maxNotesLength = 1000
+
+// validTempo checks if the tempo consists of four phases separated by
+// dashes: eccentric, bottom pause, concentric, top pause. Each phase is
+// a number of seconds or X meaning as explosive as possible
+func validTempo(tempo string) bool {
+	phases := strings.Split(tempo, "-")
+	if len(phases) != 4 {
+		return false
+	}
+
+	for _, p := range phases {
+		if p == "X" || p == "x" {
+			continue
+		}
+		if n, err := strconv.Atoi(p); err != nil || n < 0 || n > 99 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Kind determines how an exercise is measured, each kind
+// requires its own set of fields to be filled in
+type Kind string
+
+const (
+	// KindWeighted are repetitions with an external load, e.g. bench press
+	KindWeighted Kind = "weighted"
+
+	// KindBodyweight are repetitions with the body as load, the weight
+	// is additional load on top of the body weight, e.g. pull-ups
+	KindBodyweight Kind = "bodyweight"
+
+	// KindAssisted are bodyweight repetitions where the weight is
+	// the assistance subtracted from the body weight, e.g. assisted dips
+	KindAssisted Kind = "assisted"
+
+	// KindDuration are held for a duration, e.g. planks
+	KindDuration Kind = "duration"
+
+	// KindDistance are performed for a distance, e.g. farmer's walk
+	KindDistance Kind = "distance"
+
+	// KindDistanceTime are performed for a distance within a duration, e.g. rowing intervals
+	KindDistanceTime Kind = "distance_time"
+)
+
+// UsesRepetitions reports whether exercises of kind are measured in repetitions
+func (k Kind) UsesRepetitions() bool {
+	return k == KindWeighted || k == KindBodyweight || k == KindAssisted
+}
+
+func (k Kind) usesDuration() bool {
+	return k == KindDuration || k == KindDistanceTime
+}
+
+func (k Kind) usesDistance() bool {
+	return k == KindDistance || k == KindDistanceTime
+}
+
+// Patch contains the fields of an exercise that need to be updated,
+// fields that are nil are left untouched
+type Patch struct {
+	Name            *string      `json:"name"`
+	Catalog         *string      `json:"catalog"`
+	Kind            *Kind        `json:"kind"`
+	Weight          *float64     `json:"weight"`
+	Repetitions     *int         `json:"repetitions"`
+	DurationSeconds *int         `json:"duration_seconds"`
+	DistanceMeters  *float64     `json:"distance_meters"`
+	RestSeconds     *int         `json:"rest_seconds"`
+	Tempo           *string      `json:"tempo"`
+	TargetRPE       *float64     `json:"target_rpe"`
+	TargetRIR       *int         `json:"target_rir"`
+	Notes           *string      `json:"notes"`
+	Progression     *Progression `json:"progression"`
+}
+
+// Apply returns a copy of e with the patch applied, changing the kind
+// resets the fields the new kind doesn't use unless they are patched as well.
+// A negative TargetRIR clears the target and a Progression without rule clears
+// the progression
+func (p Patch) Apply(e Exercise) Exercise {
+	if p.Name != nil {
+		e.Name = *p.Name
+	}
+
+	if p.Catalog != nil {
+		e.Catalog = *p.Catalog
+	}
+
+	if p.Kind != nil && *p.Kind != e.Kind {
+		e.Kind = *p.Kind
+		if !e.Kind.UsesRepetitions() {
+			e.Repetitions = 0
+		}
+		if !e.Kind.usesDuration() {
+			e.DurationSeconds = 0
+		}
+		if !e.Kind.usesDistance() {
+			e.DistanceMeters = 0
+		}
+		if e.Kind == KindDistanceTime {
+			e.Weight = 0
+		}
+		if e.Kind != KindWeighted {
+			e.Progression = nil
+		}
+	}
+
+	if p.Weight != nil {
+		e.Weight = *p.Weight
+	}
+
+	if p.Repetitions != nil {
+		e.Repetitions = *p.Repetitions
+	}
+
+	if p.DurationSeconds != nil {
+		e.DurationSeconds = *p.DurationSeconds
+	}
+
+	if p.DistanceMeters != nil {
+		e.DistanceMeters = *p.DistanceMeters
+	}
+
+	if p.RestSeconds != nil {
+		e.RestSeconds = *p.RestSeconds
+	}
+
+	if p.Tempo != nil {
+		e.Tempo = *p.Tempo
+	}
+
+	if p.TargetRPE != nil {
+		e.TargetRPE = *p.TargetRPE
+	}
+
+	if p.TargetRIR != nil {
+		if *p.TargetRIR < 0 {
+			e.TargetRIR = nil
+		} else {
+			rir := *p.TargetRIR
+			e.TargetRIR = &rir
+		}
+	}
+
+	if p.Notes != nil {
+		e.Notes = *p.Notes
+	}
+
+	if p.Progression != nil {
+		if p.Progression.Rule == "" {
+			e.Progression = nil
+		} else {
+			progression := *p.Progression
+			e.Progression = &progression
+		}
+	}
+
+	return e
 }
 
 // Key prints unique identifiable key
@@ -60,6 +337,69 @@ func ParseRef(s string) (ExerciseRef, error) {
 	return ExerciseRef{Username: ss[0], WorkoutIndex: wi}, nil
 }
 
+// Group is a set of consecutive exercises within a workout
+// that are performed back-to-back for a number of rounds
+type Group struct {
+	Owner   string    `json:"owner"`
+	Workout int       `json:"workout"`
+	Index   int       `json:"index"`
+	Kind    GroupKind `json:"kind"`
+	Rounds  int       `json:"rounds"`
+}
+
+func (g Group) String() string {
+	return fmt.Sprintf("group %s/%d/%d: %s of %d rounds", g.Owner, g.Workout, g.Index, g.Kind, g.Rounds)
+}
+
+// GroupKind determines how the exercises in a group are performed
+type GroupKind string
+
+const (
+	// GroupSuperset pairs two exercises without rest in between
+	GroupSuperset GroupKind = "superset"
+
+	// GroupGiantSet chains three or more exercises without rest in between
+	GroupGiantSet GroupKind = "giant_set"
+
+	// GroupCircuit cycles through two or more exercises for multiple rounds
+	GroupCircuit GroupKind = "circuit"
+)
+
+// validate checks if a group of kind can contain size exercises
+func (k GroupKind) validate(size int, rounds int) error {
+	if rounds < 1 {
+		return fmt.Errorf("%w: group requires at least one round", ErrInvalidFields)
+	}
+
+	switch k {
+	case GroupSuperset:
+		if size != 2 {
+			return fmt.Errorf("%w: %s requires exactly 2 exercises", ErrInvalidFields, k)
+		}
+	case GroupGiantSet:
+		if size < 3 {
+			return fmt.Errorf("%w: %s requires at least 3 exercises", ErrInvalidFields, k)
+		}
+	case GroupCircuit:
+		if size < 2 {
+			return fmt.Errorf("%w: %s requires at least 2 exercises", ErrInvalidFields, k)
+		}
+	default:
+		return fmt.Errorf("%w: unknown group kind %q", ErrInvalidFields, k)
+	}
+
+	return nil
+}
+
+// groupIndex returns the index of the group the exercise belongs to
+// or 0 if the exercise is not part of a group
+func (e Exercise) groupIndex() int {
+	if e.Group == nil {
+		return 0
+	}
+	return e.Group.Index
+}
+
 // With is used to represent the target exercise used to swap exercises
 type With struct {
 	Exercise ExerciseRef
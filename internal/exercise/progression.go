@@ -0,0 +1,173 @@
+package exercise
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Rule determines how the planned load of an exercise progresses
+type Rule string
+
+const (
+	// RuleLinear adds the increment to the weight
+	// when all sets were completed
+	RuleLinear Rule = "linear"
+
+	// RuleDouble adds a repetition when all sets were completed until the
+	// maximum repetitions are reached, then adds the increment to the
+	// weight and starts again at the minimum repetitions
+	RuleDouble Rule = "double"
+)
+
+// Progression is the rule that adjusts the planned load of an exercise after
+// each session. When DeloadAfter is set, the weight is reduced by
+// DeloadPercent after that many consecutive failed sessions. Failures
+// is the current number of consecutive failed sessions
+type Progression struct {
+	Rule           Rule    `json:"rule"`
+	Increment      float64 `json:"increment"`
+	MinRepetitions int     `json:"min_repetitions,omitempty"`
+	MaxRepetitions int     `json:"max_repetitions,omitempty"`
+	DeloadAfter    int     `json:"deload_after,omitempty"`
+	DeloadPercent  float64 `json:"deload_percent,omitempty"`
+	Failures       int     `json:"failures"`
+}
+
+// Validate checks if the progression is valid for an exercise of kind
+func (p Progression) Validate(kind Kind) error {
+	if kind != KindWeighted && kind != "" {
+		return fmt.Errorf("%w: progression requires a %s exercise", ErrInvalidFields, KindWeighted)
+	}
+
+	if p.Increment <= 0 {
+		return fmt.Errorf("%w: progression increment %.2f, expected more than 0", ErrInvalidFields, p.Increment)
+	}
+
+	switch p.Rule {
+	case RuleLinear:
+		if p.MinRepetitions != 0 || p.MaxRepetitions != 0 {
+			return fmt.Errorf("%w: %s progression has no repetition range", ErrInvalidFields, p.Rule)
+		}
+	case RuleDouble:
+		if p.MinRepetitions < 1 || p.MaxRepetitions <= p.MinRepetitions {
+			return fmt.Errorf("%w: %s progression requires a repetition range, got %d to %d",
+				ErrInvalidFields, p.Rule, p.MinRepetitions, p.MaxRepetitions)
+		}
+	default:
+		return fmt.Errorf("%w: unknown progression rule %q", ErrInvalidFields, p.Rule)
+	}
+
+	if p.DeloadAfter < 0 {
+		return fmt.Errorf("%w: negative deload after", ErrInvalidFields)
+	}
+
+	if p.DeloadAfter > 0 && (p.DeloadPercent <= 0 || p.DeloadPercent >= 100) {
+		return fmt.Errorf("%w: deload percent %.1f, expected between 0 and 100", ErrInvalidFields, p.DeloadPercent)
+	}
+
+	return nil
+}
+
+// Outcome is the result of a session for a single exercise
+type Outcome string
+
+const (
+	OutcomeProgressed Outcome = "progressed"
+	OutcomeFailed     Outcome = "failed"
+	OutcomeDeloaded   Outcome = "deloaded"
+)
+
+// Performance summarizes the sets logged for an exercise during a session
+type Performance struct {
+	Sets      int
+	Completed bool
+}
+
+// Performed is the performance of a workout exercise during a session
+type Performed struct {
+	Workout  int
+	Exercise int
+	Performance
+}
+
+// Adjustment records how and why the planned load of an
+// exercise was adjusted after a session
+type Adjustment struct {
+	Owner           string    `json:"owner"`
+	Workout         int       `json:"workout"`
+	Exercise        int       `json:"exercise"`
+	Session         int       `json:"session"`
+	Rule            Rule      `json:"rule"`
+	Outcome         Outcome   `json:"outcome"`
+	Reason          string    `json:"reason"`
+	FromWeight      float64   `json:"from_weight"`
+	ToWeight        float64   `json:"to_weight"`
+	FromRepetitions int       `json:"from_repetitions"`
+	ToRepetitions   int       `json:"to_repetitions"`
+	AppliedAt       time.Time `json:"applied_at"`
+}
+
+// Evaluate applies the progression rule of e given the performance of a
+// session, it returns the adjusted exercise and the adjustment. A session
+// without sets is not evaluated and returns ok false
+func (e Exercise) Evaluate(perf Performance) (Exercise, Adjustment, bool) {
+	p := e.Progression
+	if p == nil || perf.Sets == 0 {
+		return e, Adjustment{}, false
+	}
+
+	next := e
+	np := *p
+	next.Progression = &np
+
+	a := Adjustment{
+		Owner:           e.Owner,
+		Workout:         e.Workout,
+		Exercise:        e.Index,
+		Rule:            p.Rule,
+		FromWeight:      e.Weight,
+		FromRepetitions: e.Repetitions,
+	}
+
+	switch {
+	case perf.Completed && p.Rule == RuleDouble && e.Repetitions < p.MaxRepetitions:
+		next.Repetitions = max(e.Repetitions+1, p.MinRepetitions)
+		np.Failures = 0
+		a.Outcome = OutcomeProgressed
+		a.Reason = fmt.Sprintf("all sets completed, +1 repetition to %d", next.Repetitions)
+	case perf.Completed:
+		next.Weight = e.Weight + p.Increment
+		if p.Rule == RuleDouble {
+			next.Repetitions = p.MinRepetitions
+		}
+		np.Failures = 0
+		a.Outcome = OutcomeProgressed
+		a.Reason = fmt.Sprintf("all sets completed, +%g weight", p.Increment)
+	case p.DeloadAfter > 0 && p.Failures+1 >= p.DeloadAfter:
+		next.Weight = roundTo(e.Weight*(1-p.DeloadPercent/100), p.Increment)
+		np.Failures = 0
+		a.Outcome = OutcomeDeloaded
+		a.Reason = fmt.Sprintf("%d consecutive failed sessions, deload %g%%", p.Failures+1, p.DeloadPercent)
+	default:
+		np.Failures = p.Failures + 1
+		a.Outcome = OutcomeFailed
+		a.Reason = "not all sets completed, load unchanged"
+		if p.DeloadAfter > 0 {
+			a.Reason = fmt.Sprintf("not all sets completed, failure %d of %d before deload", np.Failures, p.DeloadAfter)
+		}
+	}
+
+	a.ToWeight = next.Weight
+	a.ToRepetitions = next.Repetitions
+
+	return next, a, true
+}
+
+// roundTo rounds v down to a multiple of step
+func roundTo(v float64, step float64) float64 {
+	if step <= 0 {
+		return v
+	}
+	return math.Floor(v/step+1e-9) * step
+}
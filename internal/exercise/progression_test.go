@@ -0,0 +1,60 @@
+package exercise
+
+import "testing"
+
+func TestEvaluate(t *testing.T) {
+	linear := &Progression{Rule: RuleLinear, Increment: 2.5, DeloadAfter: 3, DeloadPercent: 10}
+	double := &Progression{Rule: RuleDouble, Increment: 2.5, MinRepetitions: 8, MaxRepetitions: 12}
+
+	cs := []struct {
+		name         string
+		progression  *Progression
+		failures     int
+		repetitions  int
+		perf         Performance
+		wantOutcome  Outcome
+		wantWeight   float64
+		wantReps     int
+		wantFailures int
+	}{
+		{"linearCompleted", linear, 1, 5, Performance{3, true}, OutcomeProgressed, 102.5, 5, 0},
+		{"linearFailed", linear, 0, 5, Performance{3, false}, OutcomeFailed, 100, 5, 1},
+		{"linearDeload", linear, 2, 5, Performance{3, false}, OutcomeDeloaded, 90, 5, 0},
+		{"doubleAddRepetition", double, 0, 10, Performance{3, true}, OutcomeProgressed, 100, 11, 0},
+		{"doubleAddWeight", double, 0, 12, Performance{3, true}, OutcomeProgressed, 102.5, 8, 0},
+		{"doubleFailedWithoutDeload", double, 4, 12, Performance{3, false}, OutcomeFailed, 100, 12, 5},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			p := *c.progression
+			p.Failures = c.failures
+			x := Exercise{Name: "squat", Kind: KindWeighted, Weight: 100, Repetitions: c.repetitions, Progression: &p}
+
+			got, a, ok := x.Evaluate(c.perf)
+			if !ok {
+				t.Fatal("want evaluation but got none")
+			}
+
+			if a.Outcome != c.wantOutcome {
+				t.Errorf("want outcome %s but got %s: %s", c.wantOutcome, a.Outcome, a.Reason)
+			}
+
+			if got.Weight != c.wantWeight || got.Repetitions != c.wantReps {
+				t.Errorf("want %.1fx%d but got %.1fx%d", c.wantWeight, c.wantReps, got.Weight, got.Repetitions)
+			}
+
+			if got.Progression.Failures != c.wantFailures {
+				t.Errorf("want %d failures but got %d", c.wantFailures, got.Progression.Failures)
+			}
+
+			if x.Progression.Failures != c.failures {
+				t.Errorf("want original exercise untouched but got %d failures", x.Progression.Failures)
+			}
+		})
+	}
+
+	if _, _, ok := (Exercise{Progression: linear}).Evaluate(Performance{}); ok {
+		t.Error("want no evaluation without sets")
+	}
+}
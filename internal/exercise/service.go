@@ -5,8 +5,11 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/scrot/musclemem-api/internal/api"
+	"github.com/scrot/musclemem-api/internal/equipment"
+	"github.com/scrot/musclemem-api/internal/strength"
 )
 
 func NewFetchHandler(l *slog.Logger, exercises Retreiver) http.Handler {
@@ -103,7 +106,7 @@ func NewCreateHandler(l *slog.Logger, exercises Storer) http.Handler {
 			return
 		}
 
-		created, err := exercises.New(username, wid, add.Name, add.Weight, add.Repetitions)
+		created, err := exercises.New(username, wid, add)
 		if err != nil {
 			api.WriteInternalError(l, w, err, "")
 			return
@@ -157,7 +160,8 @@ func NewDeleteHandler(l *slog.Logger, exercises Deleter) http.Handler {
 	})
 }
 
-// TODO merge all update functions into a single one
+// NewUpdateHandler updates the fields of an exercise present in the payload
+// requires {username}, {workout}, and {exercise} path variables
 func NewUpdateHandler(l *slog.Logger, exercises Updater) http.Handler {
 	l = l.With("handler", "UpdateHandler")
 
@@ -182,43 +186,19 @@ func NewUpdateHandler(l *slog.Logger, exercises Updater) http.Handler {
 			return
 		}
 
-		patch, err := api.ReadJSON[Exercise](r)
+		patch, err := api.ReadJSON[Patch](r)
 		if err != nil {
 			api.WriteInternalError(l, w, err, "")
 			return
 		}
 
-		var updated Exercise
-		if patch.Name != "" {
-			l = l.With("name", patch.Name)
-			cx, err := exercises.ChangeName(username, wi, ei, patch.Name)
-			if err != nil {
-				api.WriteInternalError(l, w, err, "")
-				return
-			}
-			updated.Name = cx.Name
-		}
-
-		if patch.Weight > 0 {
-			l = l.With("weight", patch.Weight)
-			cx, err := exercises.UpdateWeight(username, wi, ei, patch.Weight)
-			if err != nil {
-				api.WriteInternalError(l, w, err, "")
-				return
-			}
-			updated.Weight = cx.Weight
-
+		updated, err := exercises.Update(username, wi, ei, patch)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
 		}
 
-		if patch.Repetitions > 0 {
-			l = l.With("repetitions", patch.Repetitions)
-			cx, err := exercises.UpdateRepetitions(username, wi, ei, patch.Repetitions)
-			if err != nil {
-				api.WriteInternalError(l, w, err, "")
-				return
-			}
-			updated.Repetitions = cx.Repetitions
-		}
+		l.Debug(fmt.Sprintf("exercise %s updated", updated.Ref()))
 
 		if err := api.WriteJSON(w, http.StatusOK, updated); err != nil {
 			api.WriteInternalError(l, w, err, "")
@@ -370,3 +350,229 @@ func NewSwapHandler(l *slog.Logger, exercises Orderer) http.Handler {
 		l.Debug("swapped exercises")
 	})
 }
+
+// NewGroupHandler groups consecutive exercises into a superset, giant set or circuit
+// requires {username} and {workout} path variables
+// requires json payload {"kind": KIND, "rounds": ROUNDS, "from": INDEX, "to": INDEX}
+func NewGroupHandler(l *slog.Logger, exercises Grouper) http.Handler {
+	l = l.With("handler", "GroupHandler")
+
+	type Request struct {
+		Kind   GroupKind `json:"kind"`
+		Rounds int       `json:"rounds"`
+		From   int       `json:"from"`
+		To     int       `json:"to"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			workout  = r.PathValue("workout")
+		)
+
+		l := l.With("user", username, "workout", workout)
+
+		wi, err := strconv.Atoi(workout)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		req, err := api.ReadJSON[Request](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		if req.Rounds == 0 {
+			req.Rounds = 1
+		}
+
+		g, err := exercises.Group(username, wi, req.Kind, req.Rounds, req.From, req.To)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s created", g), "from", req.From, "to", req.To)
+
+		if err := api.WriteJSON(w, http.StatusOK, g); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewUngroupHandler removes a group leaving its exercises in place
+// requires {username}, {workout} and {group} path variables
+func NewUngroupHandler(l *slog.Logger, exercises Grouper) http.Handler {
+	l = l.With("handler", "UngroupHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			workout  = r.PathValue("workout")
+			group    = r.PathValue("group")
+		)
+
+		l := l.With("user", username, "workout", workout, "group", group)
+
+		wi, err := strconv.Atoi(workout)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		gi, err := strconv.Atoi(group)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		g, err := exercises.Ungroup(username, wi, gi)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s removed", g))
+
+		if err := api.WriteJSON(w, http.StatusOK, g); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewProgressionsHandler returns the adjustments made by the progression
+// rule of an exercise, most recent first
+// requires {username}, {workout} and {exercise} path variables
+func NewProgressionsHandler(l *slog.Logger, exercises Progressor) http.Handler {
+	l = l.With("handler", "ProgressionsHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			workout  = r.PathValue("workout")
+			exercise = r.PathValue("exercise")
+		)
+
+		l := l.With("user", username, "workout", workout, "exercise", exercise)
+
+		wi, err := strconv.Atoi(workout)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		ei, err := strconv.Atoi(exercise)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		adjustments, err := exercises.Progressions(username, wi, ei)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched exercise progressions", "count", len(adjustments))
+
+		if err := api.WriteJSON(w, http.StatusOK, adjustments); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewWarmupHandler returns the warm-up sets leading up to the planned weight of an exercise,
+// rounded to the equipment of the user unless a bar or plates are given
+// requires {username}, {workout} and {exercise} path variables,
+// optional scheme, bar, plates (comma separated) and step query parameters
+func NewWarmupHandler(l *slog.Logger, exercises Retreiver, equipments equipment.Rounder) http.Handler {
+	l = l.With("handler", "WarmupHandler")
+
+	type Response struct {
+		Weight float64              `json:"weight"`
+		Scheme strength.Scheme      `json:"scheme"`
+		Sets   []strength.WarmupSet `json:"sets"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			workout  = r.PathValue("workout")
+			exercise = r.PathValue("exercise")
+			params   = r.URL.Query()
+		)
+
+		l := l.With("user", username, "workout", workout, "exercise", exercise)
+
+		wi, err := strconv.Atoi(workout)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		ei, err := strconv.Atoi(exercise)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		config := strength.WarmupConfig{Scheme: strength.Scheme(params.Get("scheme"))}
+
+		if v := params.Get("bar"); v != "" {
+			if config.Bar, err = strconv.ParseFloat(v, 64); err != nil {
+				api.WriteInternalError(l, w, err, "invalid bar")
+				return
+			}
+		}
+
+		if v := params.Get("step"); v != "" {
+			if config.Step, err = strconv.ParseFloat(v, 64); err != nil {
+				api.WriteInternalError(l, w, err, "invalid step")
+				return
+			}
+		}
+
+		if v := params.Get("plates"); v != "" {
+			for _, p := range strings.Split(v, ",") {
+				plate, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+				if err != nil {
+					api.WriteInternalError(l, w, err, "invalid plates")
+					return
+				}
+				config.Plates = append(config.Plates, plate)
+			}
+		}
+
+		x, err := exercises.ByID(username, wi, ei)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		rounding, err := equipments.Rounding(username, x.Catalog)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		sets, err := x.Warmup(rounding.Warmup(config))
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		scheme, _ := strength.ParseScheme(string(config.Scheme))
+
+		l.Debug("generated warm-up", "weight", x.Weight, "sets", len(sets))
+
+		if err := api.WriteJSON(w, http.StatusOK, Response{x.Weight, scheme, sets}); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
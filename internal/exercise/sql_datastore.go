@@ -5,16 +5,116 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"time"
 
+	"github.com/scrot/musclemem-api/internal/catalog"
+	"github.com/scrot/musclemem-api/internal/equipment"
 	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/strength"
 )
 
 type SQLExerciseStore struct {
 	*storage.SqlDatastore
+
+	// rounder rounds progressed weights to the equipment of the owner
+	rounder equipment.Rounder
+}
+
+func NewSQLExerciseStore(db *storage.SqlDatastore, rounder equipment.Rounder) *SQLExerciseStore {
+	return &SQLExerciseStore{db, rounder}
+}
+
+// exerciseColumns are the columns selected for each exercise
+// in the same order as expected by scanExercise, requires exerciseTables
+const exerciseColumns = `
+    x.owner, x.workout, x.exercise_index, x.name, x.catalog, x.kind, x.weight, x.repetitions,
+    x.duration_seconds, x.distance_meters, x.rest_seconds, x.tempo, x.target_rpe,
+    x.target_rir, x.notes, x.progression, x.progression_increment, x.progression_min_repetitions,
+    x.progression_max_repetitions, x.progression_deload_after, x.progression_deload_percent,
+    x.progression_failures, g.group_index, g.kind, g.rounds
+    `
+
+// exerciseTables joins exercises with the group they belong to
+const exerciseTables = `
+    exercises x
+    LEFT JOIN exercise_groups g
+      ON g.owner = x.owner AND g.workout = x.workout AND g.group_index = x.exercise_group
+    `
+
+type scanner interface {
+	Scan(dest ...any) error
 }
 
-func NewSQLExerciseStore(db *storage.SqlDatastore) *SQLExerciseStore {
-	return &SQLExerciseStore{db}
+// withProgression returns the exercise with its progression as P, the zero
+// progression is stored for exercises without a progression rule
+func withProgression(e Exercise) any {
+	var p Progression
+	if e.Progression != nil {
+		p = *e.Progression
+	}
+
+	return struct {
+		Exercise
+		P Progression
+	}{e, p}
+}
+
+func scanExercise(s scanner) (Exercise, error) {
+	var (
+		e           Exercise
+		p           Progression
+		groupIndex  sql.NullInt32
+		groupKind   sql.NullString
+		groupRounds sql.NullInt32
+	)
+
+	err := s.Scan(
+		&e.Owner,
+		&e.Workout,
+		&e.Index,
+		&e.Name,
+		&e.Catalog,
+		&e.Kind,
+		&e.Weight,
+		&e.Repetitions,
+		&e.DurationSeconds,
+		&e.DistanceMeters,
+		&e.RestSeconds,
+		&e.Tempo,
+		&e.TargetRPE,
+		&e.TargetRIR,
+		&e.Notes,
+		&p.Rule,
+		&p.Increment,
+		&p.MinRepetitions,
+		&p.MaxRepetitions,
+		&p.DeloadAfter,
+		&p.DeloadPercent,
+		&p.Failures,
+		&groupIndex,
+		&groupKind,
+		&groupRounds,
+	)
+
+	if p.Rule != "" {
+		e.Progression = &p
+	}
+
+	if e.Kind == KindWeighted {
+		e.E1RM = strength.OneRepMax(e.Weight, e.Repetitions)
+	}
+
+	if groupIndex.Valid {
+		e.Group = &Group{
+			Owner:   e.Owner,
+			Workout: e.Workout,
+			Index:   int(groupIndex.Int32),
+			Kind:    GroupKind(groupKind.String),
+			Rounds:  int(groupRounds.Int32),
+		}
+	}
+
+	return e, err
 }
 
 // ExerciseByID returns an exercise from the database if exists
@@ -22,9 +122,9 @@ func NewSQLExerciseStore(db *storage.SqlDatastore) *SQLExerciseStore {
 func (xs *SQLExerciseStore) ByID(owner string, workout int, exercise int) (Exercise, error) {
 	const (
 		stmt = `
-    SELECT owner, workout, exercise_index, name, weight, repetitions
-    FROM exercises
-    WHERE owner = {{ .Owner }} AND workout = {{ .Workout }} AND exercise_index = {{ .Exercise }}
+    SELECT` + exerciseColumns + `
+    FROM` + exerciseTables + `
+    WHERE x.owner = {{ .Owner }} AND x.workout = {{ .Workout }} AND x.exercise_index = {{ .Exercise }}
     `
 	)
 
@@ -43,17 +143,10 @@ func (xs *SQLExerciseStore) ByID(owner string, workout int, exercise int) (Exerc
 		return Exercise{}, fmt.Errorf("WithID: compile: %w", err)
 	}
 
-	var e Exercise
-	if err := xs.QueryRow(q, args...).Scan(
-		&e.Owner,
-		&e.Workout,
-		&e.Index,
-		&e.Name,
-		&e.Weight,
-		&e.Repetitions,
-	); err != nil {
+	e, err := scanExercise(xs.QueryRow(q, args...))
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return Exercise{}, nil
+			return Exercise{}, fmt.Errorf("WithID: %w", ErrNotFound)
 		}
 		return Exercise{}, fmt.Errorf("WithID: %w", err)
 	}
@@ -64,9 +157,9 @@ func (xs *SQLExerciseStore) ByID(owner string, workout int, exercise int) (Exerc
 func (xs *SQLExerciseStore) ByWorkout(owner string, workout int) ([]Exercise, error) {
 	const (
 		selectStmt = `
-    SELECT owner, workout, exercise_index, name, weight, repetitions
-    FROM exercises
-    WHERE owner = {{ .Owner }} AND workout = {{ .Workout }}
+    SELECT` + exerciseColumns + `
+    FROM` + exerciseTables + `
+    WHERE x.owner = {{ .Owner }} AND x.workout = {{ .Workout }}
     `
 	)
 
@@ -88,19 +181,11 @@ func (xs *SQLExerciseStore) ByWorkout(owner string, workout int) ([]Exercise, er
 	if err != nil {
 		return []Exercise{}, fmt.Errorf("ByWorkout: query: %w", err)
 	}
+	defer rs.Close()
 
 	var es []Exercise
 	for rs.Next() {
-		var e Exercise
-
-		err := rs.Scan(
-			&e.Owner,
-			&e.Workout,
-			&e.Index,
-			&e.Name,
-			&e.Weight,
-			&e.Repetitions,
-		)
+		e, err := scanExercise(rs)
 		if err != nil {
 			return []Exercise{}, fmt.Errorf("ByWorkout: scan: %w", err)
 		}
@@ -113,16 +198,44 @@ func (xs *SQLExerciseStore) ByWorkout(owner string, workout int) ([]Exercise, er
 	return es, nil
 }
 
-func (xs *SQLExerciseStore) New(owner string, workout int, name string, weight float64, repetitions int) (Exercise, error) {
+func (xs *SQLExerciseStore) New(owner string, workout int, x Exercise) (Exercise, error) {
 	const stmt = `
-  INSERT INTO exercises (owner, workout, exercise_index, name, weight, repetitions)
-  VALUES ({{ .Owner }}, {{ .Workout }}, {{ .Index }}, {{ .Name }}, {{ .Weight }}, {{ .Repetitions }})
+  INSERT INTO exercises (
+    owner, workout, exercise_index, name, catalog, kind, weight, repetitions, duration_seconds, distance_meters,
+    rest_seconds, tempo, target_rpe, target_rir, notes, progression, progression_increment,
+    progression_min_repetitions, progression_max_repetitions, progression_deload_after,
+    progression_deload_percent, progression_failures
+  )
+  VALUES (
+    {{ .Owner }}, {{ .Workout }}, {{ .Index }}, {{ .Name }}, {{ .Catalog }}, {{ .Kind }}, {{ .Weight }}, {{ .Repetitions }},
+    {{ .DurationSeconds }}, {{ .DistanceMeters }}, {{ .RestSeconds }}, {{ .Tempo }}, {{ .TargetRPE }},
+    {{ .TargetRIR }}, {{ .Notes }}, {{ .P.Rule }}, {{ .P.Increment }}, {{ .P.MinRepetitions }},
+    {{ .P.MaxRepetitions }}, {{ .P.DeloadAfter }}, {{ .P.DeloadPercent }}, {{ .P.Failures }}
+  )
   `
 
-	if owner == "" || workout <= 0 || name == "" || weight < 0 || repetitions < 0 {
+	if x.Kind == "" {
+		x.Kind = KindWeighted
+	}
+
+	if owner == "" || workout <= 0 {
 		return Exercise{}, fmt.Errorf("New: %w", ErrInvalidFields)
 	}
 
+	if x.Catalog != "" {
+		name, err := xs.catalogName(owner, x.Catalog)
+		if err != nil {
+			return Exercise{}, fmt.Errorf("New: %w", err)
+		}
+		if x.Name == "" {
+			x.Name = name
+		}
+	}
+
+	if err := x.Validate(); err != nil {
+		return Exercise{}, fmt.Errorf("New: %w", err)
+	}
+
 	if !xs.workoutExists(owner, workout) {
 		return Exercise{}, fmt.Errorf("New: check workout %s/%d: %w", owner, workout, ErrNotFound)
 	}
@@ -132,16 +245,11 @@ func (xs *SQLExerciseStore) New(owner string, workout int, name string, weight f
 		return Exercise{}, fmt.Errorf("New: get last index: %w", err)
 	}
 
-	x := Exercise{
-		Owner:       owner,
-		Workout:     workout,
-		Index:       last + 1,
-		Name:        name,
-		Weight:      weight,
-		Repetitions: repetitions,
-	}
+	x.Owner = owner
+	x.Workout = workout
+	x.Index = last + 1
 
-	q, args, err := xs.CompileStatement(stmt, x)
+	q, args, err := xs.CompileStatement(stmt, withProgression(x))
 	if err != nil {
 		return Exercise{}, fmt.Errorf("New: compile: %w", err)
 	}
@@ -158,108 +266,93 @@ func (xs *SQLExerciseStore) New(owner string, workout int, name string, weight f
 	return ne, nil
 }
 
-func (xs *SQLExerciseStore) ChangeName(owner string, workout int, exercise int, name string) (Exercise, error) {
+func (xs *SQLExerciseStore) Update(owner string, workout int, exercise int, patch Patch) (Exercise, error) {
 	const stmt = `
   UPDATE exercises
-  SET name = {{ .Name }}
-  WHERE owner = {{ .Owner }} AND workout = {{ .Workout }} AND exercise_index = {{ .Exercise }}
+  SET name = {{ .Name }}, catalog = {{ .Catalog }}, kind = {{ .Kind }}, weight = {{ .Weight }}, repetitions = {{ .Repetitions }},
+    duration_seconds = {{ .DurationSeconds }}, distance_meters = {{ .DistanceMeters }},
+    rest_seconds = {{ .RestSeconds }}, tempo = {{ .Tempo }}, target_rpe = {{ .TargetRPE }},
+    target_rir = {{ .TargetRIR }}, notes = {{ .Notes }}, progression = {{ .P.Rule }},
+    progression_increment = {{ .P.Increment }}, progression_min_repetitions = {{ .P.MinRepetitions }},
+    progression_max_repetitions = {{ .P.MaxRepetitions }}, progression_deload_after = {{ .P.DeloadAfter }},
+    progression_deload_percent = {{ .P.DeloadPercent }}, progression_failures = {{ .P.Failures }}
+  WHERE owner = {{ .Owner }} AND workout = {{ .Workout }} AND exercise_index = {{ .Index }}
   `
-	if owner == "" || workout <= 0 || exercise <= 0 || name == "" {
-		return Exercise{}, ErrInvalidFields
-	}
 
-	data := struct {
-		Owner    string
-		Workout  int
-		Exercise int
-		Name     string
-	}{owner, workout, exercise, name}
+	if owner == "" || workout <= 0 || exercise <= 0 {
+		return Exercise{}, fmt.Errorf("Update: %w", ErrInvalidFields)
+	}
 
-	q, args, err := xs.CompileStatement(stmt, data)
+	e, err := xs.ByID(owner, workout, exercise)
 	if err != nil {
-		return Exercise{}, fmt.Errorf("ChangeName: compile: %w", err)
+		return Exercise{}, fmt.Errorf("Update: fetch exercise %s/%d/%d: %w", owner, workout, exercise, err)
+	}
+
+	e = patch.Apply(e)
+
+	if patch.Catalog != nil && e.Catalog != "" {
+		if _, err := xs.catalogName(owner, e.Catalog); err != nil {
+			return Exercise{}, fmt.Errorf("Update: %w", err)
+		}
+	}
+
+	if err := e.Validate(); err != nil {
+		return Exercise{}, fmt.Errorf("Update: %w", err)
 	}
 
-	_, err = xs.Exec(q, args...)
+	q, args, err := xs.CompileStatement(stmt, withProgression(e))
 	if err != nil {
-		return Exercise{}, fmt.Errorf("ChangeName: execute: %w", err)
+		return Exercise{}, fmt.Errorf("Update: compile: %w", err)
 	}
 
-	e, err := xs.ByID(owner, workout, exercise)
+	if _, err := xs.Exec(q, args...); err != nil {
+		return Exercise{}, fmt.Errorf("Update: execute: %w", err)
+	}
+
+	e, err = xs.ByID(owner, workout, exercise)
 	if err != nil {
-		return Exercise{}, fmt.Errorf("ChangeName: fetch exercise %s/%d/%d: %w", owner, workout, exercise, err)
+		return Exercise{}, fmt.Errorf("Update: fetch exercise %s/%d/%d: %w", owner, workout, exercise, err)
 	}
 
 	return e, nil
 }
 
-func (xs *SQLExerciseStore) UpdateWeight(owner string, workout int, exercise int, weight float64) (Exercise, error) {
-	const updateStmt = `
-  UPDATE exercises
-  SET weight = {{ .Weight }}
-  WHERE owner = {{ .Owner }} AND workout = {{ .Workout }} AND exercise_index = {{ .Exercise }}
-  `
-	if owner == "" || workout <= 0 || exercise <= 0 || weight < 0 {
+func (xs *SQLExerciseStore) ChangeName(owner string, workout int, exercise int, name string) (Exercise, error) {
+	if owner == "" || workout <= 0 || exercise <= 0 || name == "" {
 		return Exercise{}, ErrInvalidFields
 	}
 
-	data := struct {
-		Owner    string
-		Workout  int
-		Exercise int
-		Weight   float64
-	}{owner, workout, exercise, weight}
-
-	q, args, err := xs.CompileStatement(updateStmt, data)
+	e, err := xs.Update(owner, workout, exercise, Patch{Name: &name})
 	if err != nil {
-		return Exercise{}, fmt.Errorf("UpdateWeights: compile: %w", err)
+		return Exercise{}, fmt.Errorf("ChangeName: %w", err)
 	}
 
-	_, err = xs.Exec(q, args...)
-	if err != nil {
-		return Exercise{}, fmt.Errorf("UpdateWeights: execute: %w", err)
+	return e, nil
+}
+
+func (xs *SQLExerciseStore) UpdateWeight(owner string, workout int, exercise int, weight float64) (Exercise, error) {
+	if owner == "" || workout <= 0 || exercise <= 0 || weight < 0 {
+		return Exercise{}, ErrInvalidFields
 	}
 
-	e, err := xs.ByID(owner, workout, exercise)
+	e, err := xs.Update(owner, workout, exercise, Patch{Weight: &weight})
 	if err != nil {
-		return Exercise{}, fmt.Errorf("UpdateWeights: fetch exercise: %w", err)
+		return Exercise{}, fmt.Errorf("UpdateWeights: %w", err)
 	}
 
 	return e, nil
 }
 
 func (xs *SQLExerciseStore) UpdateRepetitions(owner string, workout int, exercise int, repetitions int) (Exercise, error) {
-	const updateStmt = `
-  UPDATE exercises
-  SET repetitions = {{ .Repetitions }}
-  WHERE owner = {{ .Owner }} AND workout = {{ .Workout }} AND exercise_index = {{ .Exercise }}
-  `
 	if owner == "" || workout <= 0 || exercise <= 0 || repetitions < 0 {
 		return Exercise{}, ErrInvalidFields
 	}
 
-	data := struct {
-		Owner       string
-		Workout     int
-		Exercise    int
-		Repetitions int
-	}{owner, workout, exercise, repetitions}
-
-	q, args, err := xs.CompileStatement(updateStmt, data)
-	if err != nil {
-		return Exercise{}, fmt.Errorf("UpdateRepetitions: %w", err)
-	}
-
-	_, err = xs.Exec(q, args...)
+	e, err := xs.Update(owner, workout, exercise, Patch{Repetitions: &repetitions})
 	if err != nil {
 		return Exercise{}, fmt.Errorf("UpdateRepetitions: %w", err)
 	}
 
-	e, err := xs.ByID(owner, workout, exercise)
-	if err != nil {
-		return Exercise{}, fmt.Errorf("UpdateRepetitions: fetch exercise: %w", err)
-	}
-
 	return e, nil
 }
 
@@ -288,20 +381,39 @@ func (xs *SQLExerciseStore) Delete(owner string, workout int, exercise int) (Exe
 		return Exercise{}, fmt.Errorf("Delete: compile: %w", err)
 	}
 
-	res, err := xs.Exec(q, args...)
+	tx, err := xs.Begin()
+	if err != nil {
+		return Exercise{}, fmt.Errorf("Delete: new transaction: %w", err)
+	}
+
+	res, err := tx.Exec(q, args...)
 	if err != nil {
+		tx.Rollback()
 		return Exercise{}, fmt.Errorf("Delete: execute: %w", err)
 	}
 
 	c, err := res.RowsAffected()
 	if err != nil {
+		tx.Rollback()
 		return Exercise{}, fmt.Errorf("Delete: %w", err)
 	}
 
 	if c == 0 {
+		tx.Rollback()
 		return Exercise{}, fmt.Errorf("Delete: %w", ErrNotFound)
 	}
 
+	if e.Group != nil {
+		if err := xs.pruneGroup(tx, owner, workout, e.Group.Index); err != nil {
+			tx.Rollback()
+			return Exercise{}, fmt.Errorf("Delete: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Exercise{}, fmt.Errorf("Delete: commit transaction: %w", err)
+	}
+
 	return e, nil
 }
 
@@ -316,14 +428,21 @@ func (xs *SQLExerciseStore) Swap(owner string, workout int, e1 int, e2 int) erro
 		return fmt.Errorf("Swap: %w", ErrInvalidFields)
 	}
 
-	if _, err := xs.ByID(owner, workout, e1); err != nil {
+	x1, err := xs.ByID(owner, workout, e1)
+	if err != nil {
 		return fmt.Errorf("Swap: check index %d: %w", e1, err)
 	}
 
-	if _, err := xs.ByID(owner, workout, e2); err != nil {
+	x2, err := xs.ByID(owner, workout, e2)
+	if err != nil {
 		return fmt.Errorf("Swap: check index %d: %w", e2, err)
 	}
 
+	// moving an exercise in or out of a group would split the group
+	if x1.groupIndex() != x2.groupIndex() {
+		return fmt.Errorf("Swap: %d and %d: %w", e1, e2, ErrSplitsGroup)
+	}
+
 	var (
 		temp = struct {
 			Owner           string
@@ -388,6 +507,147 @@ func (xs *SQLExerciseStore) Swap(owner string, workout int, e1 int, e2 int) erro
 	return nil
 }
 
+func (xs *SQLExerciseStore) Group(owner string, workout int, kind GroupKind, rounds int, from int, to int) (Group, error) {
+	const (
+		groupStmt = `
+    INSERT INTO exercise_groups (owner, workout, group_index, kind, rounds)
+    VALUES ({{ .Owner }}, {{ .Workout }}, {{ .Index }}, {{ .Kind }}, {{ .Rounds }})
+    `
+
+		memberStmt = `
+    UPDATE exercises
+    SET exercise_group = {{ .Group }}
+    WHERE owner = {{ .Owner }} AND workout = {{ .Workout }} AND exercise_index = {{ .Index }}
+    `
+	)
+
+	if owner == "" || workout <= 0 || from <= 0 || to <= from {
+		return Group{}, fmt.Errorf("Group: %w", ErrInvalidFields)
+	}
+
+	exercises, err := xs.ByWorkout(owner, workout)
+	if err != nil {
+		return Group{}, fmt.Errorf("Group: fetch exercises: %w", err)
+	}
+
+	// exercises are sorted by index, gaps left by deleted
+	// exercises don't break the consecutiveness
+	var members []Exercise
+	for _, x := range exercises {
+		if x.Index < from || x.Index > to {
+			continue
+		}
+		if x.Group != nil {
+			return Group{}, fmt.Errorf("Group: exercise %d already in group %d: %w", x.Index, x.Group.Index, ErrInvalidFields)
+		}
+		members = append(members, x)
+	}
+
+	if len(members) == 0 || members[0].Index != from || members[len(members)-1].Index != to {
+		return Group{}, fmt.Errorf("Group: exercises %d to %d: %w", from, to, ErrNotFound)
+	}
+
+	if err := kind.validate(len(members), rounds); err != nil {
+		return Group{}, fmt.Errorf("Group: %w", err)
+	}
+
+	last, err := xs.lastGroupIndex(owner, workout)
+	if err != nil {
+		return Group{}, fmt.Errorf("Group: get last index: %w", err)
+	}
+
+	g := Group{Owner: owner, Workout: workout, Index: last + 1, Kind: kind, Rounds: rounds}
+
+	tx, err := xs.Begin()
+	if err != nil {
+		return Group{}, fmt.Errorf("Group: new transaction: %w", err)
+	}
+
+	q, args, err := xs.CompileStatement(groupStmt, g)
+	if err != nil {
+		tx.Rollback()
+		return Group{}, fmt.Errorf("Group: compile: %w", err)
+	}
+
+	if _, err := tx.Exec(q, args...); err != nil {
+		tx.Rollback()
+		return Group{}, fmt.Errorf("Group: execute: %w", err)
+	}
+
+	for _, m := range members {
+		data := struct {
+			Owner   string
+			Workout int
+			Index   int
+			Group   int
+		}{owner, workout, m.Index, g.Index}
+
+		q, args, err := xs.CompileStatement(memberStmt, data)
+		if err != nil {
+			tx.Rollback()
+			return Group{}, fmt.Errorf("Group: compile member %d: %w", m.Index, err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			tx.Rollback()
+			return Group{}, fmt.Errorf("Group: update member %d: %w", m.Index, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Group{}, fmt.Errorf("Group: commit transaction: %w", err)
+	}
+
+	return g, nil
+}
+
+func (xs *SQLExerciseStore) Ungroup(owner string, workout int, group int) (Group, error) {
+	const stmt = `
+  SELECT owner, workout, group_index, kind, rounds
+  FROM exercise_groups
+  WHERE owner = {{ .Owner }} AND workout = {{ .Workout }} AND group_index = {{ .Group }}
+  `
+
+	if owner == "" || workout <= 0 || group <= 0 {
+		return Group{}, fmt.Errorf("Ungroup: %w", ErrInvalidFields)
+	}
+
+	data := struct {
+		Owner   string
+		Workout int
+		Group   int
+	}{owner, workout, group}
+
+	q, args, err := xs.CompileStatement(stmt, data)
+	if err != nil {
+		return Group{}, fmt.Errorf("Ungroup: compile: %w", err)
+	}
+
+	var g Group
+	if err := xs.QueryRow(q, args...).Scan(&g.Owner, &g.Workout, &g.Index, &g.Kind, &g.Rounds); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Group{}, fmt.Errorf("Ungroup: %w", ErrNotFound)
+		}
+		return Group{}, fmt.Errorf("Ungroup: query: %w", err)
+	}
+
+	tx, err := xs.Begin()
+	if err != nil {
+		return Group{}, fmt.Errorf("Ungroup: new transaction: %w", err)
+	}
+
+	if err := xs.deleteGroup(tx, owner, workout, group); err != nil {
+		tx.Rollback()
+		return Group{}, fmt.Errorf("Ungroup: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Group{}, fmt.Errorf("Ungroup: commit transaction: %w", err)
+	}
+
+	return g, nil
+}
+
 func (xs *SQLExerciseStore) Len(owner string, workout int) (int, error) {
 	const stmt = `
   SELECT COUNT(*)
@@ -420,6 +680,198 @@ func (xs *SQLExerciseStore) Len(owner string, workout int) (int, error) {
 	return int(count.Int32), nil
 }
 
+func (xs *SQLExerciseStore) Progress(owner string, session int, performed []Performed) ([]Adjustment, error) {
+	const (
+		exerciseStmt = `
+    SELECT` + exerciseColumns + `
+    FROM` + exerciseTables + `
+    WHERE x.owner = {{ .Owner }} AND x.workout = {{ .Workout }} AND x.exercise_index = {{ .Exercise }}
+    `
+
+		existsStmt = `
+    SELECT COUNT(*)
+    FROM progressions
+    WHERE owner = {{ .Owner }} AND workout = {{ .Workout }} AND exercise = {{ .Exercise }} AND session = {{ .Session }}
+    `
+
+		updateStmt = `
+    UPDATE exercises
+    SET weight = {{ .Weight }}, repetitions = {{ .Repetitions }}, progression_failures = {{ .P.Failures }}
+    WHERE owner = {{ .Owner }} AND workout = {{ .Workout }} AND exercise_index = {{ .Index }}
+    `
+
+		logStmt = `
+    INSERT INTO progressions (
+      owner, workout, exercise, session, rule, outcome, reason, from_weight,
+      to_weight, from_repetitions, to_repetitions, applied_at
+    )
+    VALUES (
+      {{ .Owner }}, {{ .Workout }}, {{ .Exercise }}, {{ .Session }}, {{ .Rule }}, {{ .Outcome }}, {{ .Reason }},
+      {{ .FromWeight }}, {{ .ToWeight }}, {{ .FromRepetitions }}, {{ .ToRepetitions }}, {{ .AppliedAt }}
+    )
+    `
+	)
+
+	if owner == "" || session <= 0 {
+		return []Adjustment{}, fmt.Errorf("Progress: %w", ErrInvalidFields)
+	}
+
+	tx, err := xs.Begin()
+	if err != nil {
+		return []Adjustment{}, fmt.Errorf("Progress: begin transaction: %w", err)
+	}
+
+	adjustments := []Adjustment{}
+	for _, p := range performed {
+		key := Adjustment{Owner: owner, Workout: p.Workout, Exercise: p.Exercise, Session: session}
+
+		q, args, err := xs.CompileStatement(exerciseStmt, key)
+		if err != nil {
+			tx.Rollback()
+			return []Adjustment{}, fmt.Errorf("Progress: compile exercise: %w", err)
+		}
+
+		x, err := scanExercise(tx.QueryRow(q, args...))
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			tx.Rollback()
+			return []Adjustment{}, fmt.Errorf("Progress: exercise: %w", err)
+		}
+
+		if x.Progression == nil {
+			continue
+		}
+
+		q, args, err = xs.CompileStatement(existsStmt, key)
+		if err != nil {
+			tx.Rollback()
+			return []Adjustment{}, fmt.Errorf("Progress: compile exists: %w", err)
+		}
+
+		var count int
+		if err := tx.QueryRow(q, args...).Scan(&count); err != nil {
+			tx.Rollback()
+			return []Adjustment{}, fmt.Errorf("Progress: query exists: %w", err)
+		}
+
+		if count > 0 {
+			continue
+		}
+
+		next, a, ok := x.Evaluate(p.Performance)
+		if !ok {
+			continue
+		}
+
+		if next.Weight != x.Weight {
+			rounding, err := xs.rounder.Rounding(owner, x.Catalog)
+			if err != nil {
+				tx.Rollback()
+				return []Adjustment{}, fmt.Errorf("Progress: %w", err)
+			}
+
+			// never round a progression back down to the current weight
+			if next.Weight > x.Weight {
+				next.Weight = rounding.Up(next.Weight)
+			} else {
+				next.Weight = rounding.Round(next.Weight)
+			}
+			a.ToWeight = next.Weight
+		}
+
+		a.Session = session
+		a.AppliedAt = time.Now().UTC().Truncate(time.Second)
+
+		q, args, err = xs.CompileStatement(updateStmt, withProgression(next))
+		if err != nil {
+			tx.Rollback()
+			return []Adjustment{}, fmt.Errorf("Progress: compile update: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			tx.Rollback()
+			return []Adjustment{}, fmt.Errorf("Progress: update %s: %w", x.Ref(), err)
+		}
+
+		q, args, err = xs.CompileStatement(logStmt, a)
+		if err != nil {
+			tx.Rollback()
+			return []Adjustment{}, fmt.Errorf("Progress: compile log: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			tx.Rollback()
+			return []Adjustment{}, fmt.Errorf("Progress: log %s: %w", x.Ref(), err)
+		}
+
+		adjustments = append(adjustments, a)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return []Adjustment{}, fmt.Errorf("Progress: commit transaction: %w", err)
+	}
+
+	return adjustments, nil
+}
+
+func (xs *SQLExerciseStore) Progressions(owner string, workout int, exercise int) ([]Adjustment, error) {
+	const stmt = `
+  SELECT owner, workout, exercise, session, rule, outcome, reason, from_weight,
+    to_weight, from_repetitions, to_repetitions, applied_at
+  FROM progressions
+  WHERE owner = {{ .Owner }} AND workout = {{ .Workout }} AND exercise = {{ .Exercise }}
+  ORDER BY session DESC
+  `
+
+	if owner == "" || workout <= 0 || exercise <= 0 {
+		return []Adjustment{}, fmt.Errorf("Progressions: %w", ErrInvalidFields)
+	}
+
+	data := struct {
+		Owner    string
+		Workout  int
+		Exercise int
+	}{owner, workout, exercise}
+
+	q, args, err := xs.CompileStatement(stmt, data)
+	if err != nil {
+		return []Adjustment{}, fmt.Errorf("Progressions: compile: %w", err)
+	}
+
+	rows, err := xs.Query(q, args...)
+	if err != nil {
+		return []Adjustment{}, fmt.Errorf("Progressions: query: %w", err)
+	}
+	defer rows.Close()
+
+	adjustments := []Adjustment{}
+	for rows.Next() {
+		var a Adjustment
+		if err := rows.Scan(
+			&a.Owner,
+			&a.Workout,
+			&a.Exercise,
+			&a.Session,
+			&a.Rule,
+			&a.Outcome,
+			&a.Reason,
+			&a.FromWeight,
+			&a.ToWeight,
+			&a.FromRepetitions,
+			&a.ToRepetitions,
+			&a.AppliedAt,
+		); err != nil {
+			return []Adjustment{}, fmt.Errorf("Progressions: scan: %w", err)
+		}
+		a.AppliedAt = a.AppliedAt.UTC()
+		adjustments = append(adjustments, a)
+	}
+
+	return adjustments, nil
+}
+
 // lastIndex returns the last exercise index of a workout
 // if the index is 0 and no error, then there are no exercises
 func (xs *SQLExerciseStore) lastIndex(owner string, workout int) (int, error) {
@@ -479,3 +931,171 @@ func (xs *SQLExerciseStore) workoutExists(owner string, workout int) bool {
 
 	return exists
 }
+
+// lastGroupIndex returns the last group index of a workout
+// if the index is 0 and no error, then there are no groups
+func (xs *SQLExerciseStore) lastGroupIndex(owner string, workout int) (int, error) {
+	const stmt = `
+    SELECT MAX(group_index)
+    FROM exercise_groups
+    WHERE owner = {{ .Owner }} AND workout = {{.Workout }}
+    `
+
+	data := struct {
+		Owner   string
+		Workout int
+	}{owner, workout}
+
+	q, args, err := xs.CompileStatement(stmt, data)
+	if err != nil {
+		return 0, fmt.Errorf("lastGroupIndex: compile: %w", err)
+	}
+
+	var index sql.NullInt32
+	if err := xs.QueryRow(q, args...).Scan(&index); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("lastGroupIndex: query: %w", err)
+	}
+
+	if !index.Valid {
+		return 0, nil
+	}
+
+	return int(index.Int32), nil
+}
+
+// pruneGroup re-validates a group after one of its exercises is deleted,
+// a giant set of two exercises left becomes a superset and any other
+// group that is no longer valid is removed
+func (xs *SQLExerciseStore) pruneGroup(tx *sql.Tx, owner string, workout int, group int) error {
+	const (
+		stmt = `
+    SELECT g.kind, g.rounds, COUNT(x.exercise_index)
+    FROM exercise_groups g
+    LEFT JOIN exercises x ON x.owner = g.owner AND x.workout = g.workout AND x.exercise_group = g.group_index
+    WHERE g.owner = {{ .Owner }} AND g.workout = {{ .Workout }} AND g.group_index = {{ .Group }}
+    GROUP BY g.kind, g.rounds
+    `
+
+		kindStmt = `
+    UPDATE exercise_groups
+    SET kind = {{ .Kind }}
+    WHERE owner = {{ .Owner }} AND workout = {{ .Workout }} AND group_index = {{ .Group }}
+    `
+	)
+
+	data := struct {
+		Owner   string
+		Workout int
+		Group   int
+		Kind    GroupKind
+	}{owner, workout, group, GroupSuperset}
+
+	q, args, err := xs.CompileStatement(stmt, data)
+	if err != nil {
+		return fmt.Errorf("pruneGroup: compile: %w", err)
+	}
+
+	var (
+		kind   GroupKind
+		rounds int
+		count  int
+	)
+
+	if err := tx.QueryRow(q, args...).Scan(&kind, &rounds, &count); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("pruneGroup: query: %w", err)
+	}
+
+	if kind.validate(count, rounds) == nil {
+		return nil
+	}
+
+	if kind != GroupGiantSet || GroupSuperset.validate(count, rounds) != nil {
+		return xs.deleteGroup(tx, owner, workout, group)
+	}
+
+	q, args, err = xs.CompileStatement(kindStmt, data)
+	if err != nil {
+		return fmt.Errorf("pruneGroup: compile kind: %w", err)
+	}
+
+	if _, err := tx.Exec(q, args...); err != nil {
+		return fmt.Errorf("pruneGroup: execute kind: %w", err)
+	}
+
+	return nil
+}
+
+// deleteGroup removes the group and releases its exercises
+func (xs *SQLExerciseStore) deleteGroup(tx *sql.Tx, owner string, workout int, group int) error {
+	const (
+		releaseStmt = `
+    UPDATE exercises
+    SET exercise_group = 0
+    WHERE owner = {{ .Owner }} AND workout = {{ .Workout }} AND exercise_group = {{ .Group }}
+    `
+
+		deleteStmt = `
+    DELETE FROM exercise_groups
+    WHERE owner = {{ .Owner }} AND workout = {{ .Workout }} AND group_index = {{ .Group }}
+    `
+	)
+
+	data := struct {
+		Owner   string
+		Workout int
+		Group   int
+	}{owner, workout, group}
+
+	for _, stmt := range []string{releaseStmt, deleteStmt} {
+		q, args, err := xs.CompileStatement(stmt, data)
+		if err != nil {
+			return fmt.Errorf("deleteGroup: compile: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			return fmt.Errorf("deleteGroup: execute: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// catalogName returns the name of the catalog entry the reference points to,
+// owners can only reference built-in entries and their own custom entries
+func (xs *SQLExerciseStore) catalogName(owner string, ref string) (string, error) {
+	const stmt = `
+  SELECT name
+  FROM catalog_exercises
+  WHERE owner = {{ .Owner }} AND slug = {{ .Slug }}
+  `
+
+	er, err := catalog.ParseRef(ref)
+	if err != nil {
+		return "", fmt.Errorf("catalogName: %w: %w", ErrInvalidFields, err)
+	}
+
+	if er.Owner != "" && er.Owner != owner {
+		return "", fmt.Errorf("catalogName: %s: %w", er, ErrNotFound)
+	}
+
+	q, args, err := xs.CompileStatement(stmt, er)
+	if err != nil {
+		return "", fmt.Errorf("catalogName: compile: %w", err)
+	}
+
+	var name string
+	if err := xs.QueryRow(q, args...).Scan(&name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("catalogName: %s: %w", er, ErrNotFound)
+		}
+		return "", fmt.Errorf("catalogName: query: %w", err)
+	}
+
+	return name, nil
+}
@@ -0,0 +1,395 @@
+package exercise
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scrot/musclemem-api/internal/catalog"
+	"github.com/scrot/musclemem-api/internal/equipment"
+	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/user"
+	"github.com/scrot/musclemem-api/internal/workout"
+)
+
+func TestNewExerciseKinds(t *testing.T) {
+	exercises, flush := mockExerciseStore(t)
+	defer flush()
+
+	cs := []struct {
+		name    string
+		input   Exercise
+		want    Kind
+		wantErr error
+	}{
+		{"defaultKind", Exercise{Name: "squat", Weight: 100, Repetitions: 5}, KindWeighted, nil},
+		{"weighted", Exercise{Name: "bench", Kind: KindWeighted, Weight: 80, Repetitions: 5}, KindWeighted, nil},
+		{"weightedMissingReps", Exercise{Name: "bench", Kind: KindWeighted, Weight: 80}, "", ErrInvalidFields},
+		{"bodyweight", Exercise{Name: "pull-up", Kind: KindBodyweight, Repetitions: 8}, KindBodyweight, nil},
+		{"assisted", Exercise{Name: "dip", Kind: KindAssisted, Weight: 20, Repetitions: 8}, KindAssisted, nil},
+		{"assistedMissingWeight", Exercise{Name: "dip", Kind: KindAssisted, Repetitions: 8}, "", ErrInvalidFields},
+		{"duration", Exercise{Name: "plank", Kind: KindDuration, DurationSeconds: 60}, KindDuration, nil},
+		{"durationWithReps", Exercise{Name: "plank", Kind: KindDuration, DurationSeconds: 60, Repetitions: 3}, "", ErrInvalidFields},
+		{"distance", Exercise{Name: "carry", Kind: KindDistance, Weight: 30, DistanceMeters: 40}, KindDistance, nil},
+		{"distanceTime", Exercise{Name: "row", Kind: KindDistanceTime, DistanceMeters: 500, DurationSeconds: 120}, KindDistanceTime, nil},
+		{"distanceTimeMissingDuration", Exercise{Name: "row", Kind: KindDistanceTime, DistanceMeters: 500}, "", ErrInvalidFields},
+		{"unknownKind", Exercise{Name: "yoga", Kind: "stretch", DurationSeconds: 60}, "", ErrInvalidFields},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := exercises.New("user", 1, c.input)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("want error %v but got %v", c.wantErr, err)
+			}
+
+			if got.Kind != c.want {
+				t.Errorf("want kind %q but got %q", c.want, got.Kind)
+			}
+		})
+	}
+}
+
+func TestUpdateExerciseKind(t *testing.T) {
+	exercises, flush := mockExerciseStore(t)
+	defer flush()
+
+	created, err := exercises.New("user", 1, Exercise{Name: "plank", Weight: 10, Repetitions: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		duration = KindDuration
+		seconds  = 90
+	)
+
+	if _, err := exercises.Update("user", 1, created.Index, Patch{Kind: &duration}); !errors.Is(err, ErrInvalidFields) {
+		t.Fatalf("want error %v but got %v", ErrInvalidFields, err)
+	}
+
+	got, err := exercises.Update("user", 1, created.Index, Patch{Kind: &duration, DurationSeconds: &seconds})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Kind != KindDuration || got.DurationSeconds != seconds || got.Repetitions != 0 {
+		t.Errorf("want duration exercise of %ds without reps but got %s", seconds, got)
+	}
+
+	if got.Weight != created.Weight {
+		t.Errorf("want weight %.1f to be untouched but got %.1f", created.Weight, got.Weight)
+	}
+}
+
+func TestUpdateExerciseCues(t *testing.T) {
+	exercises, flush := mockExerciseStore(t)
+	defer flush()
+
+	created, err := exercises.New("user", 1, Exercise{Name: "squat", Weight: 100, Repetitions: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created.TargetRIR != nil {
+		t.Fatalf("want no target_rir but got %d", *created.TargetRIR)
+	}
+
+	var (
+		rest       = 180
+		tempo      = "3-1-X-0"
+		rpe        = 8.5
+		rir        = 0
+		notes      = "pause at bottom"
+		wrongTempo = "3-1-1"
+	)
+
+	if _, err := exercises.Update("user", 1, created.Index, Patch{Tempo: &wrongTempo}); !errors.Is(err, ErrInvalidFields) {
+		t.Fatalf("want error %v but got %v", ErrInvalidFields, err)
+	}
+
+	got, err := exercises.Update("user", 1, created.Index, Patch{
+		RestSeconds: &rest,
+		Tempo:       &tempo,
+		TargetRPE:   &rpe,
+		TargetRIR:   &rir,
+		Notes:       &notes,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.RestSeconds != rest || got.Tempo != tempo || got.TargetRPE != rpe || got.Notes != notes {
+		t.Errorf("want rest %d, tempo %s, rpe %.1f, notes %q but got %+v", rest, tempo, rpe, notes, got)
+	}
+
+	if got.TargetRIR == nil || *got.TargetRIR != rir {
+		t.Errorf("want target_rir %d but got %v", rir, got.TargetRIR)
+	}
+
+	xs, err := exercises.ByWorkout("user", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(xs) != 1 || xs[0].Tempo != tempo {
+		t.Errorf("want exercise with tempo %s in workout but got %v", tempo, xs)
+	}
+}
+
+func TestGroupExercises(t *testing.T) {
+	exercises, flush := mockExerciseStore(t)
+	defer flush()
+
+	for _, name := range []string{"squat", "bench", "row", "curl"} {
+		if _, err := exercises.New("user", 1, Exercise{Name: name, Weight: 50, Repetitions: 10}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := exercises.Group("user", 1, GroupSuperset, 1, 1, 3); !errors.Is(err, ErrInvalidFields) {
+		t.Fatalf("want error %v for superset of three but got %v", ErrInvalidFields, err)
+	}
+
+	g, err := exercises.Group("user", 1, GroupSuperset, 3, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := exercises.Group("user", 1, GroupCircuit, 2, 3, 4); !errors.Is(err, ErrInvalidFields) {
+		t.Fatalf("want error %v for overlapping group but got %v", ErrInvalidFields, err)
+	}
+
+	xs, err := exercises.ByWorkout("user", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, x := range xs {
+		grouped := x.Index == 2 || x.Index == 3
+		if grouped != (x.Group != nil) {
+			t.Errorf("want exercise %d grouped %t but got %v", x.Index, grouped, x.Group)
+		}
+		if grouped && (x.Group.Index != g.Index || x.Group.Kind != GroupSuperset || x.Group.Rounds != 3) {
+			t.Errorf("want exercise %d in %s but got %s", x.Index, g, x.Group)
+		}
+	}
+
+	if err := exercises.Swap("user", 1, 1, 2); !errors.Is(err, ErrSplitsGroup) {
+		t.Errorf("want error %v but got %v", ErrSplitsGroup, err)
+	}
+
+	if err := exercises.Swap("user", 1, 2, 3); err != nil {
+		t.Errorf("want swap within group but got %v", err)
+	}
+
+	if err := exercises.Swap("user", 1, 1, 4); err != nil {
+		t.Errorf("want swap of ungrouped exercises but got %v", err)
+	}
+
+	if _, err := exercises.Delete("user", 1, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	remaining, err := exercises.ByID("user", 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if remaining.Group != nil {
+		t.Errorf("want group removed when one exercise remains but got %s", remaining.Group)
+	}
+}
+
+func TestPruneGroup(t *testing.T) {
+	exercises, flush := mockExerciseStore(t)
+	defer flush()
+
+	for _, name := range []string{"squat", "bench", "row", "curl"} {
+		if _, err := exercises.New("user", 1, Exercise{Name: name, Weight: 50, Repetitions: 10}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := exercises.Group("user", 1, GroupGiantSet, 2, 2, 4); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := exercises.Delete("user", 1, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, i := range []int{2, 4} {
+		x, err := exercises.ByID("user", 1, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if x.Group == nil || x.Group.Kind != GroupSuperset || x.Group.Rounds != 2 {
+			t.Errorf("want exercise %d in a superset of 2 rounds but got %v", i, x.Group)
+		}
+	}
+
+	if _, err := exercises.Delete("user", 1, 4); err != nil {
+		t.Fatal(err)
+	}
+
+	x, err := exercises.ByID("user", 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if x.Group != nil {
+		t.Errorf("want group removed when one exercise remains but got %s", x.Group)
+	}
+}
+
+func TestNewExerciseFromCatalog(t *testing.T) {
+	exercises, flush := mockExerciseStore(t)
+	defer flush()
+
+	got, err := exercises.New("user", 1, Exercise{Catalog: "barbell-back-squat", Weight: 100, Repetitions: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Name != "Barbell Back Squat" || got.Catalog != "barbell-back-squat" {
+		t.Errorf("want name and catalog from entry but got %s (%s)", got.Name, got.Catalog)
+	}
+
+	if _, err := exercises.New("user", 1, Exercise{Catalog: "unknown", Weight: 100, Repetitions: 5}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v but got %v", ErrNotFound, err)
+	}
+
+	if _, err := exercises.New("user", 1, Exercise{Catalog: "other/squat", Weight: 100, Repetitions: 5}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v for entry of other user but got %v", ErrNotFound, err)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	exercises, flush := mockExerciseStore(t)
+	defer flush()
+
+	squat := Exercise{
+		Name:        "squat",
+		Weight:      100,
+		Repetitions: 5,
+		Progression: &Progression{Rule: RuleLinear, Increment: 2.5},
+	}
+
+	if _, err := exercises.New("user", 1, squat); err != nil {
+		t.Fatal(err)
+	}
+
+	// session 1 completes all sets, session 2 misses a repetition
+	completed := []Performed{{Workout: 1, Exercise: 1, Performance: Performance{Sets: 3, Completed: true}}}
+	failed := []Performed{{Workout: 1, Exercise: 1, Performance: Performance{Sets: 3}}}
+
+	cs := []struct {
+		session     int
+		performed   []Performed
+		wantOutcome Outcome
+		wantWeight  float64
+	}{
+		{1, completed, OutcomeProgressed, 102.5},
+		{2, failed, OutcomeFailed, 102.5},
+	}
+
+	for _, c := range cs {
+		as, err := exercises.Progress("user", c.session, c.performed)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(as) != 1 || as[0].Outcome != c.wantOutcome {
+			t.Fatalf("session %d: want outcome %s but got %v", c.session, c.wantOutcome, as)
+		}
+
+		x, err := exercises.ByID("user", 1, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if x.Weight != c.wantWeight {
+			t.Errorf("session %d: want weight %.1f but got %.1f", c.session, c.wantWeight, x.Weight)
+		}
+	}
+
+	// progressing a session twice has no effect
+	again, err := exercises.Progress("user", 1, completed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(again) != 0 {
+		t.Errorf("want no adjustments but got %v", again)
+	}
+
+	// exercises that no longer exist are skipped
+	missing := []Performed{{Workout: 1, Exercise: 9, Performance: Performance{Sets: 3, Completed: true}}}
+	if skipped, err := exercises.Progress("user", 3, missing); err != nil || len(skipped) != 0 {
+		t.Errorf("want no adjustments for a missing exercise but got %v (%v)", skipped, err)
+	}
+
+	// renamed exercises are still progressed
+	if _, err := exercises.ChangeName("user", 1, 1, "back squat"); err != nil {
+		t.Fatal(err)
+	}
+
+	renamed, err := exercises.Progress("user", 3, completed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(renamed) != 1 || renamed[0].ToWeight != 105 {
+		t.Errorf("want renamed exercise progressed to 105 but got %v", renamed)
+	}
+
+	log, err := exercises.Progressions("user", 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(log) != 3 || log[0].Session != 3 {
+		t.Errorf("want 3 adjustments, most recent first but got %v", log)
+	}
+}
+
+func mockExerciseStore(t *testing.T) (ExerciseStore, func()) {
+	t.Helper()
+
+	config := storage.DatastoreConfig{
+		DatabaseURL:   "file://test.db?cache=shared&mode=memory",
+		MigrationPath: "migrations",
+		Overwrite:     false,
+	}
+
+	store, err := storage.NewSqlDatastore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := user.NewSQLUserStore(store).New("user", "test@gmail.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := workout.NewSQLWorkoutStore(store).New("user", "workout"); err != nil {
+		t.Fatal(err)
+	}
+
+	library, err := catalog.Library()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := catalog.NewSQLCatalogStore(store).Seed(library); err != nil {
+		t.Fatal(err)
+	}
+
+	flush := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal()
+		}
+	}
+
+	return NewSQLExerciseStore(store, equipment.NewSQLEquipmentStore(store)), flush
+}
@@ -0,0 +1,22 @@
+package exercise
+
+import (
+	"fmt"
+
+	"github.com/scrot/musclemem-api/internal/strength"
+)
+
+// Warmup returns the warm-up sets leading up to the planned weight
+// of the exercise, only weighted exercises can be warmed up
+func (e Exercise) Warmup(c strength.WarmupConfig) ([]strength.WarmupSet, error) {
+	if e.Kind != KindWeighted && e.Kind != "" {
+		return []strength.WarmupSet{}, fmt.Errorf("%w: warm-up requires a %s exercise", ErrInvalidFields, KindWeighted)
+	}
+
+	sets, err := strength.Warmup(e.Weight, c)
+	if err != nil {
+		return []strength.WarmupSet{}, fmt.Errorf("%w: %w", ErrInvalidFields, err)
+	}
+
+	return sets, nil
+}
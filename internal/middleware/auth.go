@@ -1,9 +1,109 @@
 package middleware
 
-import "net/http"
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
 
-func Auth(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		next.ServeHTTP(w, r)
-	})
+	"github.com/scrot/musclemem-api/internal/api"
+	"github.com/scrot/musclemem-api/internal/coach"
+	"github.com/scrot/musclemem-api/internal/user"
+)
+
+// Authenticator authenticates users by their credentials
+type Authenticator interface {
+	Authenticate(username string, password string) (user.User, error)
+}
+
+// Auth returns middleware that authenticates the caller using HTTP basic
+// authentication and authorizes access to the resources of the {username}
+// path variable. The user itself has full access, coaches only have the
+// access the user granted them to the workouts of the user and can read
+// and comment on the sessions of the user, the authenticated caller is
+// stored in the request context
+func Auth(l *slog.Logger, users Authenticator, coaches coach.Authorizer) func(http.Handler) http.Handler {
+	l = l.With("middleware", "Auth")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			if !ok {
+				api.WriteUnauthorizedError(w)
+				return
+			}
+
+			l := l.With("caller", username, "path", r.URL.Path)
+
+			if _, err := users.Authenticate(username, password); err != nil {
+				if errors.Is(err, user.ErrWrongPassword) || errors.Is(err, user.ErrUnknownUser) || errors.Is(err, user.ErrEmptyField) {
+					l.Debug("authentication failed", "error", err)
+					api.WriteUnauthorizedError(w)
+					return
+				}
+				api.WriteInternalError(l, w, err, "")
+				return
+			}
+
+			r = r.WithContext(api.WithCaller(r.Context(), username))
+
+			owner := r.PathValue("username")
+			if owner == "" || owner == username {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !delegable(r, owner) {
+				l.Debug("access denied", "owner", owner)
+				api.WriteForbiddenError(w)
+				return
+			}
+
+			access, err := coaches.Access(owner, username)
+			if err != nil {
+				api.WriteInternalError(l, w, err, "")
+				return
+			}
+
+			if !access.Allows(r.Method) {
+				l.Debug("access denied", "owner", owner, "access", access)
+				api.WriteForbiddenError(w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// delegable reports whether owner can delegate access to the resource
+// requested by r to coaches, which are the workouts and their exercises
+// and comments of owner, and reading the sessions of owner including
+// commenting on them. Importing workouts and sharing them stay with the owner
+func delegable(r *http.Request, owner string) bool {
+	var (
+		workouts = "/users/" + owner + "/workouts"
+		sessions = "/users/" + owner + "/sessions"
+		path     = r.URL.Path
+	)
+
+	if path == workouts || strings.HasPrefix(path, workouts+"/") {
+		parts := strings.Split(strings.TrimPrefix(path, workouts), "/")
+		if len(parts) >= 2 && parts[1] == "import" {
+			return false
+		}
+		return len(parts) < 3 || parts[2] != "shares"
+	}
+
+	if path != sessions && !strings.HasPrefix(path, sessions+"/") {
+		return false
+	}
+
+	// sessions are logged by the owner, coaches can only read and comment
+	parts := strings.Split(strings.TrimPrefix(path, sessions), "/")
+	if len(parts) >= 3 && parts[2] == "comments" {
+		return true
+	}
+
+	return r.Method == http.MethodGet && len(parts) <= 2
 }
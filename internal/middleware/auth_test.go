@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/scrot/musclemem-api/internal/api"
+	"github.com/scrot/musclemem-api/internal/coach"
+	"github.com/scrot/musclemem-api/internal/user"
+)
+
+type users map[string]string
+
+func (us users) Authenticate(username string, password string) (user.User, error) {
+	p, ok := us[username]
+	if !ok {
+		return user.User{}, user.ErrUnknownUser
+	}
+
+	if p != password {
+		return user.User{}, user.ErrWrongPassword
+	}
+
+	return user.User{Username: username}, nil
+}
+
+type grants map[[2]string]coach.Access
+
+func (gs grants) Access(athlete string, coach string) (coach.Access, error) {
+	return gs[[2]string{athlete, coach}], nil
+}
+
+func TestAuth(t *testing.T) {
+	var (
+		us = users{"athlete": "secret", "coach": "secret", "reader": "secret", "other": "secret"}
+		gs = grants{{"athlete", "coach"}: coach.AccessWrite, {"athlete", "reader"}: coach.AccessRead}
+	)
+
+	auth := Auth(slog.Default(), us, gs)
+	caller := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(api.Caller(r)))
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /users/{username}/workouts", auth(caller))
+	mux.Handle("POST /users/{username}/workouts", auth(caller))
+	mux.Handle("POST /users/{username}/workouts/import", auth(caller))
+	mux.Handle("GET /users/{username}/workouts/{workout}/shares", auth(caller))
+	mux.Handle("POST /users/{username}/workouts/{workout}/shares", auth(caller))
+	mux.Handle("GET /users/{username}/sessions", auth(caller))
+	mux.Handle("GET /users/{username}/sessions/{session}", auth(caller))
+	mux.Handle("POST /users/{username}/sessions/{session}/finish", auth(caller))
+	mux.Handle("POST /users/{username}/sessions/{session}/comments", auth(caller))
+	mux.Handle("GET /users/{username}/history", auth(caller))
+
+	cs := []struct {
+		name     string
+		method   string
+		path     string
+		username string
+		password string
+		want     int
+	}{
+		{"anonymous", http.MethodGet, "/users/athlete/workouts", "", "", http.StatusUnauthorized},
+		{"wrongPassword", http.MethodGet, "/users/athlete/workouts", "athlete", "wrong", http.StatusUnauthorized},
+		{"owner", http.MethodPost, "/users/athlete/workouts", "athlete", "secret", http.StatusOK},
+		{"otherUser", http.MethodGet, "/users/athlete/workouts", "other", "secret", http.StatusForbidden},
+		{"coachRead", http.MethodGet, "/users/athlete/workouts", "coach", "secret", http.StatusOK},
+		{"coachWrite", http.MethodPost, "/users/athlete/workouts", "coach", "secret", http.StatusOK},
+		{"coachImport", http.MethodPost, "/users/athlete/workouts/import", "coach", "secret", http.StatusForbidden},
+		{"coachShares", http.MethodGet, "/users/athlete/workouts/1/shares", "coach", "secret", http.StatusForbidden},
+		{"coachShare", http.MethodPost, "/users/athlete/workouts/1/shares", "coach", "secret", http.StatusForbidden},
+		{"readerWrite", http.MethodPost, "/users/athlete/workouts", "reader", "secret", http.StatusForbidden},
+		{"coachSessions", http.MethodGet, "/users/athlete/sessions", "coach", "secret", http.StatusOK},
+		{"coachSession", http.MethodGet, "/users/athlete/sessions/1", "coach", "secret", http.StatusOK},
+		{"coachFinish", http.MethodPost, "/users/athlete/sessions/1/finish", "coach", "secret", http.StatusForbidden},
+		{"coachComment", http.MethodPost, "/users/athlete/sessions/1/comments", "coach", "secret", http.StatusOK},
+		{"readerComment", http.MethodPost, "/users/athlete/sessions/1/comments", "reader", "secret", http.StatusForbidden},
+		{"otherSessions", http.MethodGet, "/users/athlete/sessions", "other", "secret", http.StatusForbidden},
+		{"coachHistory", http.MethodGet, "/users/athlete/history", "coach", "secret", http.StatusForbidden},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(c.method, c.path, nil)
+			if c.username != "" {
+				r.SetBasicAuth(c.username, c.password)
+			}
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+
+			if w.Code != c.want {
+				t.Fatalf("want status %d but got %d", c.want, w.Code)
+			}
+
+			if c.want == http.StatusOK && w.Body.String() != c.username {
+				t.Errorf("want caller %s but got %s", c.username, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package program
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidFields = errors.New("contains invalid fields")
+	ErrNotEnrolled   = errors.New("not enrolled")
+	ErrNotStarted    = errors.New("program not started")
+)
+
+// ProgramStore represents the training program repository
+type ProgramStore interface {
+	Retreiver
+	Storer
+	Deleter
+	Enroller
+	Instantiator
+}
+
+// Retreiver implementations allow for programs to be queried
+type Retreiver interface {
+	// ByID returns the program including its phases and prescriptions
+	ByID(owner string, program int) (Program, error)
+
+	// ByOwner returns all programs of owner
+	ByOwner(owner string) ([]Program, error)
+}
+
+// Storer implementations allow for programs to be created
+type Storer interface {
+	// New stores the program, the prescribed workout
+	// exercises must exist and belong to owner
+	New(owner string, program Program) (Program, error)
+}
+
+// Deleter implementations allow for programs to be deleted
+type Deleter interface {
+	// Delete deletes the program including its enrollment
+	Delete(owner string, program int) (Program, error)
+}
+
+// Enroller implementations allow for users to enroll in programs
+type Enroller interface {
+	// Enroll enrolls owner in the program replacing an existing enrollment,
+	// the training maxes must cover all lifts of the program
+	Enroll(owner string, program int, enrollment Enrollment) (Enrollment, error)
+
+	// Enrollment returns the enrollment of owner in the program,
+	// it returns an ErrNotEnrolled error if owner is not enrolled
+	Enrollment(owner string, program int) (Enrollment, error)
+
+	// Unenroll removes the enrollment of owner in the program
+	Unenroll(owner string, program int) (Enrollment, error)
+
+	// Current returns the current week of the program owner is enrolled
+	// in with the prescribed loads resolved to concrete weights
+	Current(owner string, program int) (Week, error)
+}
+
+// Instantiator implementations allow for built-in templates to be instantiated
+type Instantiator interface {
+	// Instantiate creates the workouts and exercises of the template for owner,
+	// a program prescribing them and enrolls owner with the training maxes
+	Instantiate(owner string, template string, enrollment Enrollment) (Instance, error)
+}
@@ -0,0 +1,260 @@
+package program
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// DateLayout is the layout of the start date of an enrollment
+const DateLayout = time.DateOnly
+
+// Program groups the workouts of a user into consecutive phases of weeks,
+// the exercises of the workouts are prescribed as a percentage of the
+// training max of a lift
+type Program struct {
+	Owner         string         `json:"owner"`
+	Index         int            `json:"index"`
+	Name          string         `json:"name"`
+	Phases        []Phase        `json:"phases"`
+	Prescriptions []Prescription `json:"prescriptions"`
+}
+
+func (p Program) String() string {
+	return fmt.Sprintf("program %s: %s of %d weeks", p.Ref(), p.Name, p.Weeks())
+}
+
+// Ref returns the unique key that references the Program
+func (p Program) Ref() ProgramRef {
+	return ProgramRef{p.Owner, p.Index}
+}
+
+// ProgramRef represents the unique key that references a Program
+type ProgramRef struct {
+	Username     string
+	ProgramIndex int
+}
+
+func (pr ProgramRef) String() string {
+	return fmt.Sprintf("%s/%d", pr.Username, pr.ProgramIndex)
+}
+
+// Weeks returns the total number of weeks of the program
+func (p Program) Weeks() int {
+	var weeks int
+	for _, ph := range p.Phases {
+		weeks += ph.Weeks
+	}
+	return weeks
+}
+
+// Phase is a block of weeks with a common goal, the prescribed percentages
+// are scaled by Intensity, e.g. an intensity of 60 for a deload phase
+type Phase struct {
+	Name      string  `json:"name"`
+	Weeks     int     `json:"weeks"`
+	Intensity float64 `json:"intensity"`
+}
+
+// Prescription prescribes the load of a workout exercise as Percent of the
+// training max of Lift for Repetitions. A prescription applies to every week
+// of the program unless Week is set, prescriptions for a specific week take
+// precedence over prescriptions for every week
+type Prescription struct {
+	Week        int     `json:"week,omitempty"`
+	Workout     int     `json:"workout"`
+	Exercise    int     `json:"exercise"`
+	Lift        string  `json:"lift"`
+	Percent     float64 `json:"percent"`
+	Repetitions int     `json:"repetitions"`
+}
+
+// Lifts returns the distinct lifts the prescriptions are based on
+func (p Program) Lifts() []string {
+	var (
+		lifts []string
+		seen  = make(map[string]bool)
+	)
+
+	for _, ps := range p.Prescriptions {
+		if !seen[ps.Lift] {
+			seen[ps.Lift] = true
+			lifts = append(lifts, ps.Lift)
+		}
+	}
+
+	return lifts
+}
+
+// Validate checks if the program is consistent and defaults
+// the intensity of phases to 100 percent
+func (p *Program) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidFields)
+	}
+
+	if len(p.Phases) == 0 {
+		return fmt.Errorf("%w: program requires at least one phase", ErrInvalidFields)
+	}
+
+	for i := range p.Phases {
+		ph := &p.Phases[i]
+		if ph.Intensity == 0 {
+			ph.Intensity = 100
+		}
+
+		if ph.Name == "" || ph.Weeks < 1 {
+			return fmt.Errorf("%w: phase %d requires a name and at least one week", ErrInvalidFields, i+1)
+		}
+
+		if ph.Intensity < 0 || ph.Intensity > 150 {
+			return fmt.Errorf("%w: phase %s intensity %.1f, expected 0 to 150", ErrInvalidFields, ph.Name, ph.Intensity)
+		}
+	}
+
+	seen := make(map[[3]int]bool)
+	for _, ps := range p.Prescriptions {
+		if ps.Week < 0 || ps.Week > p.Weeks() {
+			return fmt.Errorf("%w: week %d, expected 1 to %d", ErrInvalidFields, ps.Week, p.Weeks())
+		}
+
+		if ps.Workout <= 0 || ps.Exercise <= 0 || ps.Lift == "" {
+			return fmt.Errorf("%w: prescription requires a workout, exercise and lift", ErrInvalidFields)
+		}
+
+		if ps.Percent <= 0 || ps.Percent > 150 || ps.Repetitions < 1 {
+			return fmt.Errorf("%w: prescription %.1f%% for %d, expected up to 150%% for at least 1 repetition",
+				ErrInvalidFields, ps.Percent, ps.Repetitions)
+		}
+
+		key := [3]int{ps.Week, ps.Workout, ps.Exercise}
+		if seen[key] {
+			return fmt.Errorf("%w: multiple prescriptions for exercise %d/%d in week %d",
+				ErrInvalidFields, ps.Workout, ps.Exercise, ps.Week)
+		}
+		seen[key] = true
+	}
+
+	return nil
+}
+
+// Enrollment enrolls a user in a program starting at StartDate,
+// TrainingMaxes contains the training max of each lift of the program
+type Enrollment struct {
+	Owner         string             `json:"owner"`
+	Program       int                `json:"program"`
+	StartDate     string             `json:"start_date"`
+	TrainingMaxes map[string]float64 `json:"training_maxes"`
+}
+
+// Validate checks if the enrollment covers the lifts of program
+func (e Enrollment) Validate(program Program) error {
+	if _, err := time.Parse(DateLayout, e.StartDate); err != nil {
+		return fmt.Errorf("%w: start date %q, expected %s", ErrInvalidFields, e.StartDate, DateLayout)
+	}
+
+	for _, lift := range program.Lifts() {
+		if e.TrainingMaxes[lift] <= 0 {
+			return fmt.Errorf("%w: missing training max for %s", ErrInvalidFields, lift)
+		}
+	}
+
+	return nil
+}
+
+// Week is the resolved week of a program an enrolled user is in
+type Week struct {
+	Program  int               `json:"program"`
+	Week     int               `json:"week"`
+	Phase    string            `json:"phase"`
+	Finished bool              `json:"finished,omitempty"`
+	Workouts []ResolvedWorkout `json:"workouts"`
+}
+
+// ResolvedWorkout contains the exercises of a workout with concrete weights
+type ResolvedWorkout struct {
+	Workout   int                `json:"workout"`
+	Exercises []ResolvedExercise `json:"exercises"`
+}
+
+// ResolvedExercise is a prescription resolved to a concrete weight
+type ResolvedExercise struct {
+	Exercise    int     `json:"exercise"`
+	Lift        string  `json:"lift"`
+	Percent     float64 `json:"percent"`
+	Weight      float64 `json:"weight"`
+	Repetitions int     `json:"repetitions"`
+}
+
+// Resolve returns the week of the program on date for the enrollment, with
+// the prescriptions resolved to weights. After the last week the
+// last week is returned as finished
+func (p Program) Resolve(e Enrollment, date time.Time) (Week, error) {
+	start, err := time.Parse(DateLayout, e.StartDate)
+	if err != nil {
+		return Week{}, fmt.Errorf("%w: start date %q", ErrInvalidFields, e.StartDate)
+	}
+
+	days := int(date.Sub(start).Hours() / 24)
+	if days < 0 {
+		return Week{}, fmt.Errorf("%w: program starts on %s", ErrNotStarted, e.StartDate)
+	}
+
+	w := Week{Program: p.Index, Week: days/7 + 1, Workouts: []ResolvedWorkout{}}
+	if w.Week > p.Weeks() {
+		w.Week = p.Weeks()
+		w.Finished = true
+	}
+
+	var phase Phase
+	for i, end := 0, 0; i < len(p.Phases); i++ {
+		end += p.Phases[i].Weeks
+		if w.Week <= end {
+			phase = p.Phases[i]
+			break
+		}
+	}
+	w.Phase = phase.Name
+
+	// week specific prescriptions take precedence
+	prescribed := make(map[[2]int]Prescription)
+	for _, ps := range p.Prescriptions {
+		key := [2]int{ps.Workout, ps.Exercise}
+		if ps.Week == w.Week || (ps.Week == 0 && prescribed[key].Week == 0) {
+			prescribed[key] = ps
+		}
+	}
+
+	// position of each workout in the resolved week
+	workouts := make(map[int]int)
+
+	for _, ps := range p.Prescriptions {
+		key := [2]int{ps.Workout, ps.Exercise}
+		if prescribed[key] != ps {
+			continue
+		}
+
+		percent := ps.Percent * phase.Intensity / 100
+		x := ResolvedExercise{
+			Exercise:    ps.Exercise,
+			Lift:        ps.Lift,
+			Percent:     round(percent),
+			Weight:      round(e.TrainingMaxes[ps.Lift] * percent / 100),
+			Repetitions: ps.Repetitions,
+		}
+
+		i, ok := workouts[ps.Workout]
+		if !ok {
+			i = len(w.Workouts)
+			workouts[ps.Workout] = i
+			w.Workouts = append(w.Workouts, ResolvedWorkout{Workout: ps.Workout})
+		}
+		w.Workouts[i].Exercises = append(w.Workouts[i].Exercises, x)
+	}
+
+	return w, nil
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
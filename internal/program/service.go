@@ -0,0 +1,338 @@
+package program
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/scrot/musclemem-api/internal/api"
+)
+
+// NewFetchAllHandler returns all programs of a user
+// requires {username} path variable
+func NewFetchAllHandler(l *slog.Logger, programs Retreiver) http.Handler {
+	l = l.With("handler", "FetchAllHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		ps, err := programs.ByOwner(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched user programs", "count", len(ps))
+
+		if err := api.WriteJSON(w, http.StatusOK, ps); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewFetchHandler returns a program including its phases and prescriptions
+// requires {username} and {program} path variables
+func NewFetchHandler(l *slog.Logger, programs Retreiver) http.Handler {
+	l = l.With("handler", "FetchHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			program  = r.PathValue("program")
+		)
+
+		l := l.With("user", username, "program", program)
+
+		pi, err := strconv.Atoi(program)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		p, err := programs.ByID(username, pi)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("fetched %s", p))
+
+		if err := api.WriteJSON(w, http.StatusOK, p); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewCreateHandler creates a new program for a user
+// requires {username} path variable
+// requires json payload {"name": NAME, "phases": [{"name": NAME, "weeks": N, "intensity": PCT}],
+// "prescriptions": [{"week": N, "workout": INDEX, "exercise": INDEX, "lift": LIFT, "percent": PCT, "repetitions": N}]}
+func NewCreateHandler(l *slog.Logger, programs Storer) http.Handler {
+	l = l.With("handler", "CreateHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		p, err := api.ReadJSON[Program](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		created, err := programs.New(username, p)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s created", created))
+
+		if err := api.WriteJSON(w, http.StatusOK, created); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewDeleteHandler deletes a program including its enrollment
+// requires {username} and {program} path variables
+func NewDeleteHandler(l *slog.Logger, programs Deleter) http.Handler {
+	l = l.With("handler", "DeleteHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			program  = r.PathValue("program")
+		)
+
+		l := l.With("user", username, "program", program)
+
+		pi, err := strconv.Atoi(program)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		deleted, err := programs.Delete(username, pi)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("program deleted", "key", deleted.Ref())
+
+		if err := api.WriteJSON(w, http.StatusOK, deleted); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewEnrollHandler enrolls a user in a program
+// requires {username} and {program} path variables
+// requires json payload {"start_date": DATE, "training_maxes": {LIFT: WEIGHT}}
+func NewEnrollHandler(l *slog.Logger, programs Enroller) http.Handler {
+	l = l.With("handler", "EnrollHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			program  = r.PathValue("program")
+		)
+
+		l := l.With("user", username, "program", program)
+
+		pi, err := strconv.Atoi(program)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		e, err := api.ReadJSON[Enrollment](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		enrolled, err := programs.Enroll(username, pi, e)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("user enrolled", "start", enrolled.StartDate)
+
+		if err := api.WriteJSON(w, http.StatusOK, enrolled); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewEnrollmentHandler returns the enrollment of a user in a program
+// requires {username} and {program} path variables
+func NewEnrollmentHandler(l *slog.Logger, programs Enroller) http.Handler {
+	l = l.With("handler", "EnrollmentHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			program  = r.PathValue("program")
+		)
+
+		l := l.With("user", username, "program", program)
+
+		pi, err := strconv.Atoi(program)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		e, err := programs.Enrollment(username, pi)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched enrollment", "start", e.StartDate)
+
+		if err := api.WriteJSON(w, http.StatusOK, e); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewUnenrollHandler removes the enrollment of a user in a program
+// requires {username} and {program} path variables
+func NewUnenrollHandler(l *slog.Logger, programs Enroller) http.Handler {
+	l = l.With("handler", "UnenrollHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			program  = r.PathValue("program")
+		)
+
+		l := l.With("user", username, "program", program)
+
+		pi, err := strconv.Atoi(program)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		e, err := programs.Unenroll(username, pi)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("user unenrolled")
+
+		if err := api.WriteJSON(w, http.StatusOK, e); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewCurrentHandler returns the current week of an enrolled program
+// with the prescribed loads resolved to concrete weights
+// requires {username} and {program} path variables
+func NewCurrentHandler(l *slog.Logger, programs Enroller) http.Handler {
+	l = l.With("handler", "CurrentHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			program  = r.PathValue("program")
+		)
+
+		l := l.With("user", username, "program", program)
+
+		pi, err := strconv.Atoi(program)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		week, err := programs.Current(username, pi)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("resolved current week", "week", week.Week, "phase", week.Phase)
+
+		if err := api.WriteJSON(w, http.StatusOK, week); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewTemplatesHandler returns the built-in program templates
+func NewTemplatesHandler(l *slog.Logger) http.Handler {
+	l = l.With("handler", "TemplatesHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts, err := Templates()
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched program templates", "count", len(ts))
+
+		if err := api.WriteJSON(w, http.StatusOK, ts); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewInstantiateHandler creates the workouts, exercises and program of a
+// built-in template for a user and enrolls the user in the program
+// requires {username} path variable
+// requires json payload {"template": SLUG, "start_date": DATE, "training_maxes": {LIFT: WEIGHT}}
+func NewInstantiateHandler(l *slog.Logger, programs Instantiator) http.Handler {
+	l = l.With("handler", "InstantiateHandler")
+
+	type Request struct {
+		Template string `json:"template"`
+		Enrollment
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		req, err := api.ReadJSON[Request](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		instance, err := programs.Instantiate(username, req.Template, req.Enrollment)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s instantiated", instance.Program), "template", req.Template)
+
+		if err := api.WriteJSON(w, http.StatusOK, instance); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
@@ -0,0 +1,741 @@
+package program
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/equipment"
+	"github.com/scrot/musclemem-api/internal/exercise"
+	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/workout"
+)
+
+type SQLProgramStore struct {
+	*storage.SqlDatastore
+
+	// rounder rounds prescribed weights to the equipment of the owner
+	rounder equipment.Rounder
+
+	// now returns the current time, replaceable for testing
+	now func() time.Time
+}
+
+func NewSQLProgramStore(db *storage.SqlDatastore, rounder equipment.Rounder) *SQLProgramStore {
+	return &SQLProgramStore{db, rounder, time.Now}
+}
+
+// key is the data used to compile statements for a single program
+type key struct {
+	Owner   string
+	Program int
+}
+
+func (ps *SQLProgramStore) ByID(owner string, program int) (Program, error) {
+	const (
+		programStmt = `
+    SELECT owner, program_index, name
+    FROM programs
+    WHERE owner = {{ .Owner }} AND program_index = {{ .Program }}
+    `
+
+		phasesStmt = `
+    SELECT name, weeks, intensity
+    FROM program_phases
+    WHERE owner = {{ .Owner }} AND program = {{ .Program }}
+    ORDER BY phase_index
+    `
+
+		prescriptionsStmt = `
+    SELECT week, workout, exercise, lift, percent, repetitions
+    FROM program_prescriptions
+    WHERE owner = {{ .Owner }} AND program = {{ .Program }}
+    ORDER BY prescription_index
+    `
+	)
+
+	if owner == "" || program <= 0 {
+		return Program{}, fmt.Errorf("ByID: %w", ErrInvalidFields)
+	}
+
+	data := key{owner, program}
+
+	q, args, err := ps.CompileStatement(programStmt, data)
+	if err != nil {
+		return Program{}, fmt.Errorf("ByID: compile: %w", err)
+	}
+
+	var p Program
+	if err := ps.QueryRow(q, args...).Scan(&p.Owner, &p.Index, &p.Name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Program{}, fmt.Errorf("ByID: %s/%d: %w", owner, program, ErrNotFound)
+		}
+		return Program{}, fmt.Errorf("ByID: query: %w", err)
+	}
+
+	q, args, err = ps.CompileStatement(phasesStmt, data)
+	if err != nil {
+		return Program{}, fmt.Errorf("ByID: compile phases: %w", err)
+	}
+
+	rows, err := ps.Query(q, args...)
+	if err != nil {
+		return Program{}, fmt.Errorf("ByID: query phases: %w", err)
+	}
+	defer rows.Close()
+
+	p.Phases = []Phase{}
+	for rows.Next() {
+		var ph Phase
+		if err := rows.Scan(&ph.Name, &ph.Weeks, &ph.Intensity); err != nil {
+			return Program{}, fmt.Errorf("ByID: scan phase: %w", err)
+		}
+		p.Phases = append(p.Phases, ph)
+	}
+
+	q, args, err = ps.CompileStatement(prescriptionsStmt, data)
+	if err != nil {
+		return Program{}, fmt.Errorf("ByID: compile prescriptions: %w", err)
+	}
+
+	rows, err = ps.Query(q, args...)
+	if err != nil {
+		return Program{}, fmt.Errorf("ByID: query prescriptions: %w", err)
+	}
+	defer rows.Close()
+
+	p.Prescriptions = []Prescription{}
+	for rows.Next() {
+		var x Prescription
+		if err := rows.Scan(&x.Week, &x.Workout, &x.Exercise, &x.Lift, &x.Percent, &x.Repetitions); err != nil {
+			return Program{}, fmt.Errorf("ByID: scan prescription: %w", err)
+		}
+		p.Prescriptions = append(p.Prescriptions, x)
+	}
+
+	return p, nil
+}
+
+func (ps *SQLProgramStore) ByOwner(owner string) ([]Program, error) {
+	const stmt = `
+  SELECT program_index
+  FROM programs
+  WHERE owner = {{ . }}
+  ORDER BY program_index
+  `
+
+	if owner == "" {
+		return []Program{}, fmt.Errorf("ByOwner: %w", ErrInvalidFields)
+	}
+
+	q, args, err := ps.CompileStatement(stmt, owner)
+	if err != nil {
+		return []Program{}, fmt.Errorf("ByOwner: compile: %w", err)
+	}
+
+	rows, err := ps.Query(q, args...)
+	if err != nil {
+		return []Program{}, fmt.Errorf("ByOwner: query: %w", err)
+	}
+	defer rows.Close()
+
+	var indices []int
+	for rows.Next() {
+		var i int
+		if err := rows.Scan(&i); err != nil {
+			return []Program{}, fmt.Errorf("ByOwner: scan: %w", err)
+		}
+		indices = append(indices, i)
+	}
+
+	if err := rows.Close(); err != nil {
+		return []Program{}, fmt.Errorf("ByOwner: close: %w", err)
+	}
+
+	programs := []Program{}
+	for _, i := range indices {
+		p, err := ps.ByID(owner, i)
+		if err != nil {
+			return []Program{}, fmt.Errorf("ByOwner: %w", err)
+		}
+		programs = append(programs, p)
+	}
+
+	return programs, nil
+}
+
+func (ps *SQLProgramStore) New(owner string, program Program) (Program, error) {
+	if owner == "" {
+		return Program{}, fmt.Errorf("New: %w", ErrInvalidFields)
+	}
+
+	if err := program.Validate(); err != nil {
+		return Program{}, fmt.Errorf("New: %w", err)
+	}
+
+	for _, x := range program.Prescriptions {
+		if !ps.exerciseExists(owner, x.Workout, x.Exercise) {
+			return Program{}, fmt.Errorf("New: exercise %s/%d/%d: %w", owner, x.Workout, x.Exercise, ErrNotFound)
+		}
+	}
+
+	tx, err := ps.Begin()
+	if err != nil {
+		return Program{}, fmt.Errorf("New: begin transaction: %w", err)
+	}
+
+	if program, err = ps.insert(tx, owner, program); err != nil {
+		tx.Rollback()
+		return Program{}, fmt.Errorf("New: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Program{}, fmt.Errorf("New: commit transaction: %w", err)
+	}
+
+	return program, nil
+}
+
+func (ps *SQLProgramStore) Delete(owner string, program int) (Program, error) {
+	stmts := []string{
+		`DELETE FROM training_maxes WHERE owner = {{ .Owner }} AND program = {{ .Program }}`,
+		`DELETE FROM enrollments WHERE owner = {{ .Owner }} AND program = {{ .Program }}`,
+		`DELETE FROM program_prescriptions WHERE owner = {{ .Owner }} AND program = {{ .Program }}`,
+		`DELETE FROM program_phases WHERE owner = {{ .Owner }} AND program = {{ .Program }}`,
+		`DELETE FROM programs WHERE owner = {{ .Owner }} AND program_index = {{ .Program }}`,
+	}
+
+	p, err := ps.ByID(owner, program)
+	if err != nil {
+		return Program{}, fmt.Errorf("Delete: %w", err)
+	}
+
+	if err := ps.execAll(stmts, key{owner, program}); err != nil {
+		return Program{}, fmt.Errorf("Delete: %w", err)
+	}
+
+	return p, nil
+}
+
+func (ps *SQLProgramStore) Enroll(owner string, program int, enrollment Enrollment) (Enrollment, error) {
+	p, err := ps.ByID(owner, program)
+	if err != nil {
+		return Enrollment{}, fmt.Errorf("Enroll: %w", err)
+	}
+
+	enrollment.Owner = owner
+	enrollment.Program = program
+
+	if enrollment.StartDate == "" {
+		enrollment.StartDate = ps.now().UTC().Format(DateLayout)
+	}
+
+	if err := enrollment.Validate(p); err != nil {
+		return Enrollment{}, fmt.Errorf("Enroll: %w", err)
+	}
+
+	tx, err := ps.Begin()
+	if err != nil {
+		return Enrollment{}, fmt.Errorf("Enroll: begin transaction: %w", err)
+	}
+
+	if err := ps.enroll(tx, enrollment); err != nil {
+		tx.Rollback()
+		return Enrollment{}, fmt.Errorf("Enroll: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Enrollment{}, fmt.Errorf("Enroll: commit transaction: %w", err)
+	}
+
+	return enrollment, nil
+}
+
+func (ps *SQLProgramStore) Enrollment(owner string, program int) (Enrollment, error) {
+	const (
+		enrollmentStmt = `
+    SELECT start_date
+    FROM enrollments
+    WHERE owner = {{ .Owner }} AND program = {{ .Program }}
+    `
+
+		maxesStmt = `
+    SELECT lift, weight
+    FROM training_maxes
+    WHERE owner = {{ .Owner }} AND program = {{ .Program }}
+    `
+	)
+
+	if owner == "" || program <= 0 {
+		return Enrollment{}, fmt.Errorf("Enrollment: %w", ErrInvalidFields)
+	}
+
+	data := key{owner, program}
+
+	q, args, err := ps.CompileStatement(enrollmentStmt, data)
+	if err != nil {
+		return Enrollment{}, fmt.Errorf("Enrollment: compile: %w", err)
+	}
+
+	e := Enrollment{Owner: owner, Program: program, TrainingMaxes: make(map[string]float64)}
+	if err := ps.QueryRow(q, args...).Scan(&e.StartDate); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Enrollment{}, fmt.Errorf("Enrollment: %s/%d: %w", owner, program, ErrNotEnrolled)
+		}
+		return Enrollment{}, fmt.Errorf("Enrollment: query: %w", err)
+	}
+
+	q, args, err = ps.CompileStatement(maxesStmt, data)
+	if err != nil {
+		return Enrollment{}, fmt.Errorf("Enrollment: compile maxes: %w", err)
+	}
+
+	rows, err := ps.Query(q, args...)
+	if err != nil {
+		return Enrollment{}, fmt.Errorf("Enrollment: query maxes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			lift   string
+			weight float64
+		)
+
+		if err := rows.Scan(&lift, &weight); err != nil {
+			return Enrollment{}, fmt.Errorf("Enrollment: scan max: %w", err)
+		}
+		e.TrainingMaxes[lift] = weight
+	}
+
+	return e, nil
+}
+
+func (ps *SQLProgramStore) Unenroll(owner string, program int) (Enrollment, error) {
+	stmts := []string{
+		`DELETE FROM training_maxes WHERE owner = {{ .Owner }} AND program = {{ .Program }}`,
+		`DELETE FROM enrollments WHERE owner = {{ .Owner }} AND program = {{ .Program }}`,
+	}
+
+	e, err := ps.Enrollment(owner, program)
+	if err != nil {
+		return Enrollment{}, fmt.Errorf("Unenroll: %w", err)
+	}
+
+	if err := ps.execAll(stmts, key{owner, program}); err != nil {
+		return Enrollment{}, fmt.Errorf("Unenroll: %w", err)
+	}
+
+	return e, nil
+}
+
+func (ps *SQLProgramStore) Current(owner string, program int) (Week, error) {
+	p, err := ps.ByID(owner, program)
+	if err != nil {
+		return Week{}, fmt.Errorf("Current: %w", err)
+	}
+
+	e, err := ps.Enrollment(owner, program)
+	if err != nil {
+		return Week{}, fmt.Errorf("Current: %w", err)
+	}
+
+	now := ps.now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	w, err := p.Resolve(e, today)
+	if err != nil {
+		return Week{}, fmt.Errorf("Current: %w", err)
+	}
+
+	for i, rw := range w.Workouts {
+		for j, x := range rw.Exercises {
+			rounding, err := ps.rounder.Rounding(owner, ps.catalogOf(owner, rw.Workout, x.Exercise))
+			if err != nil {
+				return Week{}, fmt.Errorf("Current: %w", err)
+			}
+			w.Workouts[i].Exercises[j].Weight = rounding.Round(x.Weight)
+		}
+	}
+
+	return w, nil
+}
+
+func (ps *SQLProgramStore) Instantiate(owner string, template string, enrollment Enrollment) (Instance, error) {
+	const (
+		userStmt = `
+    SELECT COUNT(*)
+    FROM users
+    WHERE username = {{ . }}
+    `
+
+		indexStmt = `
+    SELECT COALESCE(MAX(workout_index), 0)
+    FROM workouts
+    WHERE owner = {{ . }}
+    `
+
+		workoutStmt = `
+    INSERT INTO workouts (owner, workout_index, name)
+    VALUES ({{ .Owner }}, {{ .Index }}, {{ .Name }})
+    `
+
+		exerciseStmt = `
+    INSERT INTO exercises (
+      owner, workout, exercise_index, name, catalog, kind, weight, repetitions, rest_seconds,
+      progression, progression_increment, progression_min_repetitions, progression_max_repetitions,
+      progression_deload_after, progression_deload_percent, progression_failures
+    )
+    VALUES (
+      {{ .Owner }}, {{ .Workout }}, {{ .Index }}, {{ .Name }}, {{ .Catalog }}, {{ .Kind }}, {{ .Weight }},
+      {{ .Repetitions }}, {{ .RestSeconds }}, {{ .P.Rule }}, {{ .P.Increment }}, {{ .P.MinRepetitions }},
+      {{ .P.MaxRepetitions }}, {{ .P.DeloadAfter }}, {{ .P.DeloadPercent }}, 0
+    )
+    `
+	)
+
+	if owner == "" {
+		return Instance{}, fmt.Errorf("Instantiate: %w", ErrInvalidFields)
+	}
+
+	t, err := FindTemplate(template)
+	if err != nil {
+		return Instance{}, fmt.Errorf("Instantiate: %w", err)
+	}
+
+	if err := t.Validate(enrollment.TrainingMaxes); err != nil {
+		return Instance{}, fmt.Errorf("Instantiate: %w", err)
+	}
+
+	q, args, err := ps.CompileStatement(userStmt, owner)
+	if err != nil {
+		return Instance{}, fmt.Errorf("Instantiate: compile user: %w", err)
+	}
+
+	var count int
+	if err := ps.QueryRow(q, args...).Scan(&count); err != nil {
+		return Instance{}, fmt.Errorf("Instantiate: query user: %w", err)
+	}
+
+	if count == 0 {
+		return Instance{}, fmt.Errorf("Instantiate: user %s: %w", owner, ErrNotFound)
+	}
+
+	// the exercises are rounded to the equipment of owner before
+	// anything is written, so a failing lookup leaves nothing behind
+	exercises := make([][]exercise.Exercise, len(t.Workouts))
+
+	for i, tw := range t.Workouts {
+		for _, te := range tw.Exercises {
+			rounding, err := ps.rounder.Rounding(owner, te.Catalog)
+			if err != nil {
+				return Instance{}, fmt.Errorf("Instantiate: %w", err)
+			}
+
+			x := te.Exercise(enrollment.TrainingMaxes)
+			x.Weight = rounding.Round(x.Weight)
+			if x.Kind == "" {
+				x.Kind = exercise.KindWeighted
+			}
+
+			if err := x.Validate(); err != nil {
+				return Instance{}, fmt.Errorf("Instantiate: exercise %s: %w", te.Name, err)
+			}
+			exercises[i] = append(exercises[i], x)
+		}
+	}
+
+	tx, err := ps.Begin()
+	if err != nil {
+		return Instance{}, fmt.Errorf("Instantiate: begin transaction: %w", err)
+	}
+
+	q, args, err = ps.CompileStatement(indexStmt, owner)
+	if err != nil {
+		tx.Rollback()
+		return Instance{}, fmt.Errorf("Instantiate: compile index: %w", err)
+	}
+
+	var last int
+	if err := tx.QueryRow(q, args...).Scan(&last); err != nil {
+		tx.Rollback()
+		return Instance{}, fmt.Errorf("Instantiate: query index: %w", err)
+	}
+
+	var (
+		instance = Instance{Workouts: []workout.Workout{}}
+		program  = Program{Name: t.Name, Phases: t.Phases, Prescriptions: []Prescription{}}
+	)
+
+	for i, tw := range t.Workouts {
+		w := workout.Workout{Owner: owner, Index: last + i + 1, Name: tw.Name}
+		if err := ps.exec(tx, workoutStmt, w); err != nil {
+			tx.Rollback()
+			return Instance{}, fmt.Errorf("Instantiate: workout %s: %w", tw.Name, err)
+		}
+		instance.Workouts = append(instance.Workouts, w)
+
+		for j, x := range exercises[i] {
+			x.Owner = owner
+			x.Workout = w.Index
+			x.Index = j + 1
+
+			var p exercise.Progression
+			if x.Progression != nil {
+				p = *x.Progression
+			}
+
+			data := struct {
+				exercise.Exercise
+				P exercise.Progression
+			}{x, p}
+
+			if err := ps.exec(tx, exerciseStmt, data); err != nil {
+				tx.Rollback()
+				return Instance{}, fmt.Errorf("Instantiate: exercise %s: %w", x.Name, err)
+			}
+			program.Prescriptions = append(program.Prescriptions, tw.Exercises[j].Prescriptions(w.Index, x.Index)...)
+		}
+	}
+
+	if err := program.Validate(); err != nil {
+		tx.Rollback()
+		return Instance{}, fmt.Errorf("Instantiate: %w", err)
+	}
+
+	if instance.Program, err = ps.insert(tx, owner, program); err != nil {
+		tx.Rollback()
+		return Instance{}, fmt.Errorf("Instantiate: %w", err)
+	}
+
+	enrollment.Owner = owner
+	enrollment.Program = instance.Program.Index
+
+	if enrollment.StartDate == "" {
+		enrollment.StartDate = ps.now().UTC().Format(DateLayout)
+	}
+
+	if err := enrollment.Validate(instance.Program); err != nil {
+		tx.Rollback()
+		return Instance{}, fmt.Errorf("Instantiate: %w", err)
+	}
+
+	if err := ps.enroll(tx, enrollment); err != nil {
+		tx.Rollback()
+		return Instance{}, fmt.Errorf("Instantiate: %w", err)
+	}
+	instance.Enrollment = enrollment
+
+	if err := tx.Commit(); err != nil {
+		return Instance{}, fmt.Errorf("Instantiate: commit transaction: %w", err)
+	}
+
+	return instance, nil
+}
+
+// insert stores program of owner at the next program index within tx,
+// the program is expected to be valid
+func (ps *SQLProgramStore) insert(tx *sql.Tx, owner string, program Program) (Program, error) {
+	const (
+		indexStmt = `
+    SELECT COALESCE(MAX(program_index), 0)
+    FROM programs
+    WHERE owner = {{ . }}
+    `
+
+		programStmt = `
+    INSERT INTO programs (owner, program_index, name)
+    VALUES ({{ .Owner }}, {{ .Index }}, {{ .Name }})
+    `
+
+		phaseStmt = `
+    INSERT INTO program_phases (owner, program, phase_index, name, weeks, intensity)
+    VALUES ({{ .Owner }}, {{ .Program }}, {{ .Index }}, {{ .Name }}, {{ .Weeks }}, {{ .Intensity }})
+    `
+
+		prescriptionStmt = `
+    INSERT INTO program_prescriptions (owner, program, prescription_index, week, workout, exercise, lift, percent, repetitions)
+    VALUES (
+      {{ .Owner }}, {{ .Program }}, {{ .Index }}, {{ .Week }}, {{ .Workout }},
+      {{ .Exercise }}, {{ .Lift }}, {{ .Percent }}, {{ .Repetitions }}
+    )
+    `
+	)
+
+	q, args, err := ps.CompileStatement(indexStmt, owner)
+	if err != nil {
+		return Program{}, fmt.Errorf("insert: compile index: %w", err)
+	}
+
+	var last int
+	if err := tx.QueryRow(q, args...).Scan(&last); err != nil {
+		return Program{}, fmt.Errorf("insert: query index: %w", err)
+	}
+
+	program.Owner = owner
+	program.Index = last + 1
+
+	if err := ps.exec(tx, programStmt, program); err != nil {
+		return Program{}, fmt.Errorf("insert: %w", err)
+	}
+
+	for i, ph := range program.Phases {
+		data := struct {
+			key
+			Index int
+			Phase
+		}{key{owner, program.Index}, i + 1, ph}
+
+		if err := ps.exec(tx, phaseStmt, data); err != nil {
+			return Program{}, fmt.Errorf("insert: phase: %w", err)
+		}
+	}
+
+	for i, x := range program.Prescriptions {
+		data := struct {
+			key
+			Index int
+			Prescription
+		}{key{owner, program.Index}, i + 1, x}
+
+		if err := ps.exec(tx, prescriptionStmt, data); err != nil {
+			return Program{}, fmt.Errorf("insert: prescription: %w", err)
+		}
+	}
+
+	return program, nil
+}
+
+// enroll stores the enrollment replacing the training maxes within tx,
+// the enrollment is expected to be valid
+func (ps *SQLProgramStore) enroll(tx *sql.Tx, enrollment Enrollment) error {
+	const (
+		enrollStmt = `
+    INSERT INTO enrollments (owner, program, start_date)
+    VALUES ({{ .Owner }}, {{ .Program }}, {{ .StartDate }})
+    ON CONFLICT (owner, program) DO UPDATE
+    SET start_date = excluded.start_date
+    `
+
+		clearStmt = `
+    DELETE FROM training_maxes
+    WHERE owner = {{ .Owner }} AND program = {{ .Program }}
+    `
+
+		maxStmt = `
+    INSERT INTO training_maxes (owner, program, lift, weight)
+    VALUES ({{ .Owner }}, {{ .Program }}, {{ .Lift }}, {{ .Weight }})
+    `
+	)
+
+	for _, stmt := range []string{enrollStmt, clearStmt} {
+		if err := ps.exec(tx, stmt, enrollment); err != nil {
+			return fmt.Errorf("enroll: %w", err)
+		}
+	}
+
+	for lift, weight := range enrollment.TrainingMaxes {
+		data := struct {
+			key
+			Lift   string
+			Weight float64
+		}{key{enrollment.Owner, enrollment.Program}, lift, weight}
+
+		if err := ps.exec(tx, maxStmt, data); err != nil {
+			return fmt.Errorf("enroll: training max: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// exec compiles and executes stmt within tx
+func (ps *SQLProgramStore) exec(tx *sql.Tx, stmt string, data any) error {
+	q, args, err := ps.CompileStatement(stmt, data)
+	if err != nil {
+		return fmt.Errorf("compile: %w", err)
+	}
+
+	if _, err := tx.Exec(q, args...); err != nil {
+		return fmt.Errorf("execute: %w", err)
+	}
+
+	return nil
+}
+
+// execAll executes the statements in a single transaction
+func (ps *SQLProgramStore) execAll(stmts []string, data any) error {
+	tx, err := ps.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	for _, stmt := range stmts {
+		if err := ps.exec(tx, stmt, data); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// catalogOf returns the catalog entry of the workout exercise of owner,
+// it returns an empty string if the exercise has no catalog entry
+func (ps *SQLProgramStore) catalogOf(owner string, workout int, exercise int) string {
+	const stmt = `
+  SELECT catalog
+  FROM exercises
+  WHERE owner = {{ .Owner }} AND workout = {{ .Workout }} AND exercise_index = {{ .Exercise }}
+  `
+
+	data := struct {
+		Owner    string
+		Workout  int
+		Exercise int
+	}{owner, workout, exercise}
+
+	q, args, err := ps.CompileStatement(stmt, data)
+	if err != nil {
+		return ""
+	}
+
+	var catalog string
+	if err := ps.QueryRow(q, args...).Scan(&catalog); err != nil {
+		return ""
+	}
+
+	return catalog
+}
+
+// exerciseExists checks if the workout exercise of owner exists
+func (ps *SQLProgramStore) exerciseExists(owner string, workout int, exercise int) bool {
+	const stmt = `
+  SELECT COUNT(*)
+  FROM exercises
+  WHERE owner = {{ .Owner }} AND workout = {{ .Workout }} AND exercise_index = {{ .Exercise }}
+  `
+
+	data := struct {
+		Owner    string
+		Workout  int
+		Exercise int
+	}{owner, workout, exercise}
+
+	q, args, err := ps.CompileStatement(stmt, data)
+	if err != nil {
+		return false
+	}
+
+	var count int
+	if err := ps.QueryRow(q, args...).Scan(&count); err != nil {
+		return false
+	}
+
+	return count > 0
+}
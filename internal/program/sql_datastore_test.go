@@ -0,0 +1,262 @@
+package program
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/catalog"
+	"github.com/scrot/musclemem-api/internal/equipment"
+	"github.com/scrot/musclemem-api/internal/exercise"
+	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/user"
+	"github.com/scrot/musclemem-api/internal/workout"
+)
+
+func TestNewProgram(t *testing.T) {
+	programs, flush := mockProgramStore(t)
+	defer flush()
+
+	phases := []Phase{{Name: "volume", Weeks: 4}}
+
+	cs := []struct {
+		name    string
+		input   Program
+		wantErr error
+	}{
+		{"valid", Program{Name: "block", Phases: phases, Prescriptions: []Prescription{{0, 1, 1, "squat", 75, 5}}}, nil},
+		{"noName", Program{Phases: phases}, ErrInvalidFields},
+		{"noPhases", Program{Name: "block"}, ErrInvalidFields},
+		{"weekOutOfRange", Program{Name: "block", Phases: phases, Prescriptions: []Prescription{{5, 1, 1, "squat", 75, 5}}}, ErrInvalidFields},
+		{"duplicate", Program{Name: "block", Phases: phases, Prescriptions: []Prescription{{0, 1, 1, "squat", 75, 5}, {0, 1, 1, "squat", 80, 3}}}, ErrInvalidFields},
+		{"unknownExercise", Program{Name: "block", Phases: phases, Prescriptions: []Prescription{{0, 1, 9, "squat", 75, 5}}}, ErrNotFound},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := programs.New("user", c.input)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("want error %v but got %v", c.wantErr, err)
+			}
+		})
+	}
+
+	got, err := programs.ByOwner("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 1 || got[0].Index != 1 || got[0].Phases[0].Intensity != 100 {
+		t.Fatalf("want a single program with default intensity but got %+v", got)
+	}
+
+	if _, err := programs.Delete("user", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := programs.ByID("user", 1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want error %v but got %v", ErrNotFound, err)
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	programs, flush := mockProgramStore(t)
+	defer flush()
+
+	p := Program{
+		Name: "strength block",
+		Phases: []Phase{
+			{Name: "accumulation", Weeks: 3},
+			{Name: "deload", Weeks: 1, Intensity: 60},
+		},
+		Prescriptions: []Prescription{
+			{Workout: 1, Exercise: 1, Lift: "squat", Percent: 75, Repetitions: 5},
+			{Workout: 1, Exercise: 2, Lift: "bench", Percent: 70, Repetitions: 8},
+			{Week: 3, Workout: 1, Exercise: 1, Lift: "squat", Percent: 85, Repetitions: 3},
+		},
+	}
+
+	created, err := programs.New("user", p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := programs.Current("user", created.Index); !errors.Is(err, ErrNotEnrolled) {
+		t.Fatalf("want error %v but got %v", ErrNotEnrolled, err)
+	}
+
+	missing := Enrollment{StartDate: "2024-01-01", TrainingMaxes: map[string]float64{"squat": 140}}
+	if _, err := programs.Enroll("user", created.Index, missing); !errors.Is(err, ErrInvalidFields) {
+		t.Fatalf("want error %v but got %v", ErrInvalidFields, err)
+	}
+
+	enrollment := Enrollment{StartDate: "2024-01-01", TrainingMaxes: map[string]float64{"squat": 140, "bench": 100}}
+	if _, err := programs.Enroll("user", created.Index, enrollment); err != nil {
+		t.Fatal(err)
+	}
+
+	cs := []struct {
+		name    string
+		now     string
+		want    Week
+		wantErr error
+	}{
+		{"notStarted", "2023-12-31", Week{}, ErrNotStarted},
+		{"firstWeek", "2024-01-03", Week{1, 1, "accumulation", false, []ResolvedWorkout{{1, []ResolvedExercise{
+			{1, "squat", 75, 105, 5},
+			{2, "bench", 70, 70, 8},
+		}}}}, nil},
+		{"weekSpecific", "2024-01-15", Week{1, 3, "accumulation", false, []ResolvedWorkout{{1, []ResolvedExercise{
+			{2, "bench", 70, 70, 8},
+			{1, "squat", 85, 119, 3},
+		}}}}, nil},
+		{"deload", "2024-01-22", Week{1, 4, "deload", false, []ResolvedWorkout{{1, []ResolvedExercise{
+			{1, "squat", 45, 63, 5},
+			{2, "bench", 42, 42, 8},
+		}}}}, nil},
+		{"finished", "2024-03-01", Week{1, 4, "deload", true, []ResolvedWorkout{{1, []ResolvedExercise{
+			{1, "squat", 45, 63, 5},
+			{2, "bench", 42, 42, 8},
+		}}}}, nil},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			now, _ := time.Parse(DateLayout, c.now)
+			programs.now = func() time.Time { return now.Add(10 * time.Hour) }
+
+			got, err := programs.Current("user", created.Index)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("want error %v but got %v", c.wantErr, err)
+			}
+
+			if c.wantErr == nil && !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("want %+v but got %+v", c.want, got)
+			}
+		})
+	}
+
+	if _, err := programs.Unenroll("user", created.Index); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := programs.Enrollment("user", created.Index); !errors.Is(err, ErrNotEnrolled) {
+		t.Fatalf("want error %v but got %v", ErrNotEnrolled, err)
+	}
+}
+
+func TestInstantiate(t *testing.T) {
+	programs, flush := mockProgramStore(t)
+	defer flush()
+
+	missing := Enrollment{StartDate: "2024-01-01", TrainingMaxes: map[string]float64{"squat": 140}}
+	if _, err := programs.Instantiate("user", "531", missing); !errors.Is(err, ErrInvalidFields) {
+		t.Fatalf("want error %v but got %v", ErrInvalidFields, err)
+	}
+
+	if _, err := programs.Instantiate("user", "unknown", missing); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want error %v but got %v", ErrNotFound, err)
+	}
+
+	maxes := map[string]float64{"squat": 140, "bench": 100, "deadlift": 180, "press": 60}
+	if _, err := programs.Instantiate("nobody", "531", Enrollment{StartDate: "2024-01-01", TrainingMaxes: maxes}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want error %v but got %v", ErrNotFound, err)
+	}
+
+	// an invalid enrollment is only detected after the workouts are
+	// created, these must be rolled back with the program
+	invalid := Enrollment{StartDate: "01-01-2024", TrainingMaxes: maxes}
+	if _, err := programs.Instantiate("user", "531", invalid); !errors.Is(err, ErrInvalidFields) {
+		t.Fatalf("want error %v but got %v", ErrInvalidFields, err)
+	}
+
+	if ws, err := workout.NewSQLWorkoutStore(programs.SqlDatastore).ByOwner("user", workout.Query{}); err != nil || len(ws) != 1 {
+		t.Fatalf("want the single mock workout after a failed instantiation but got %+v (%v)", ws, err)
+	}
+
+	got, err := programs.Instantiate("user", "531", Enrollment{StartDate: "2024-01-01", TrainingMaxes: maxes})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the mock already contains a workout
+	if len(got.Workouts) != 4 || got.Workouts[0].Index != 2 {
+		t.Fatalf("want 4 workouts starting at index 2 but got %+v", got.Workouts)
+	}
+
+	if len(got.Program.Prescriptions) != 16 {
+		t.Fatalf("want 16 prescriptions but got %d", len(got.Program.Prescriptions))
+	}
+
+	squat, err := exercise.NewSQLExerciseStore(programs.SqlDatastore, programs.rounder).ByID("user", 5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 85% of 140 rounded to the plates of the default equipment
+	if squat.Catalog != "barbell-back-squat" || squat.Weight != 120 {
+		t.Fatalf("want barbell-back-squat at 120 but got %s", squat)
+	}
+
+	now, _ := time.Parse(DateLayout, "2024-01-15")
+	programs.now = func() time.Time { return now }
+
+	week, err := programs.Current("user", got.Program.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := ResolvedExercise{Exercise: 1, Lift: "squat", Percent: 95, Weight: 132.5, Repetitions: 1}
+	if w := week.Workouts[3]; w.Workout != 5 || w.Exercises[0] != want {
+		t.Fatalf("want %+v in workout 5 but got %+v", want, w)
+	}
+}
+
+func mockProgramStore(t *testing.T) (*SQLProgramStore, func()) {
+	t.Helper()
+
+	config := storage.DatastoreConfig{
+		DatabaseURL:   "file://test.db?cache=shared&mode=memory",
+		MigrationPath: "migrations",
+		Overwrite:     false,
+	}
+
+	store, err := storage.NewSqlDatastore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := user.NewSQLUserStore(store).New("user", "test@gmail.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	library, err := catalog.Library()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := catalog.NewSQLCatalogStore(store).Seed(library); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := workout.NewSQLWorkoutStore(store).New("user", "lower"); err != nil {
+		t.Fatal(err)
+	}
+
+	equipments := equipment.NewSQLEquipmentStore(store)
+	exercises := exercise.NewSQLExerciseStore(store, equipments)
+	for _, name := range []string{"squat", "bench press"} {
+		if _, err := exercises.New("user", 1, exercise.Exercise{Name: name, Weight: 60, Repetitions: 5}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	flush := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal()
+		}
+	}
+
+	return NewSQLProgramStore(store, equipments), flush
+}
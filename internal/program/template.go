@@ -0,0 +1,169 @@
+package program
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/scrot/musclemem-api/internal/exercise"
+	"github.com/scrot/musclemem-api/internal/workout"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// Template is a built-in program that is instantiated into concrete
+// workouts, exercises and a program for a user
+type Template struct {
+	Slug        string            `json:"slug"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Phases      []Phase           `json:"phases"`
+	Workouts    []TemplateWorkout `json:"workouts"`
+}
+
+// TemplateWorkout is a workout of a template
+type TemplateWorkout struct {
+	Name      string             `json:"name"`
+	Exercises []TemplateExercise `json:"exercises"`
+}
+
+// TemplateExercise is an exercise of a template workout. When Lift is set,
+// the weight is Percent of the training max of the lift and the exercise
+// is prescribed by the program with Weeks overriding specific weeks.
+// Exercises with a progression rule progress after each session instead
+// and are not prescribed
+type TemplateExercise struct {
+	Name        string                `json:"name"`
+	Catalog     string                `json:"catalog,omitempty"`
+	Kind        exercise.Kind         `json:"kind,omitempty"`
+	Repetitions int                   `json:"repetitions"`
+	RestSeconds int                   `json:"rest_seconds,omitempty"`
+	Lift        string                `json:"lift,omitempty"`
+	Percent     float64               `json:"percent,omitempty"`
+	Weeks       []TemplateWeek        `json:"weeks,omitempty"`
+	Progression *exercise.Progression `json:"progression,omitempty"`
+}
+
+// TemplateWeek overrides the load of a template exercise in a specific week
+type TemplateWeek struct {
+	Week        int     `json:"week"`
+	Percent     float64 `json:"percent"`
+	Repetitions int     `json:"repetitions"`
+}
+
+// Instance is the result of instantiating a template
+type Instance struct {
+	Workouts   []workout.Workout `json:"workouts"`
+	Program    Program           `json:"program"`
+	Enrollment Enrollment        `json:"enrollment"`
+}
+
+// Templates returns the built-in program templates ordered by slug
+func Templates() ([]Template, error) {
+	entries, err := templates.ReadDir("templates")
+	if err != nil {
+		return []Template{}, fmt.Errorf("Templates: read: %w", err)
+	}
+
+	ts := []Template{}
+	for _, entry := range entries {
+		data, err := templates.ReadFile(path.Join("templates", entry.Name()))
+		if err != nil {
+			return []Template{}, fmt.Errorf("Templates: read %s: %w", entry.Name(), err)
+		}
+
+		var t Template
+		if err := json.Unmarshal(data, &t); err != nil {
+			return []Template{}, fmt.Errorf("Templates: decode %s: %w", entry.Name(), err)
+		}
+		ts = append(ts, t)
+	}
+
+	sort.Slice(ts, func(i, j int) bool { return ts[i].Slug < ts[j].Slug })
+
+	return ts, nil
+}
+
+// FindTemplate returns the built-in template with slug
+func FindTemplate(slug string) (Template, error) {
+	ts, err := Templates()
+	if err != nil {
+		return Template{}, fmt.Errorf("FindTemplate: %w", err)
+	}
+
+	for _, t := range ts {
+		if t.Slug == slug {
+			return t, nil
+		}
+	}
+
+	return Template{}, fmt.Errorf("FindTemplate: template %q: %w", slug, ErrNotFound)
+}
+
+// Lifts returns the unique lifts the template depends on
+func (t Template) Lifts() []string {
+	var (
+		lifts []string
+		seen  = make(map[string]bool)
+	)
+
+	for _, w := range t.Workouts {
+		for _, x := range w.Exercises {
+			if x.Lift != "" && !seen[x.Lift] {
+				seen[x.Lift] = true
+				lifts = append(lifts, x.Lift)
+			}
+		}
+	}
+
+	return lifts
+}
+
+// Exercise returns the exercise of the template with the starting weight
+// derived from the training maxes
+func (x TemplateExercise) Exercise(maxes map[string]float64) exercise.Exercise {
+	e := exercise.Exercise{
+		Name:        x.Name,
+		Catalog:     x.Catalog,
+		Kind:        x.Kind,
+		Repetitions: x.Repetitions,
+		RestSeconds: x.RestSeconds,
+		Progression: x.Progression,
+	}
+
+	if x.Lift != "" {
+		e.Weight = round(maxes[x.Lift] * x.Percent / 100)
+	}
+
+	return e
+}
+
+// Prescriptions returns the prescriptions of the exercise at the
+// workout and exercise index, exercises with a progression or
+// without a lift are not prescribed
+func (x TemplateExercise) Prescriptions(workout int, exercise int) []Prescription {
+	if x.Lift == "" || x.Progression != nil {
+		return []Prescription{}
+	}
+
+	ps := []Prescription{{0, workout, exercise, x.Lift, x.Percent, x.Repetitions}}
+	for _, w := range x.Weeks {
+		ps = append(ps, Prescription{w.Week, workout, exercise, x.Lift, w.Percent, w.Repetitions})
+	}
+
+	return ps
+}
+
+// Validate checks if the training maxes cover the lifts of the template
+func (t Template) Validate(maxes map[string]float64) error {
+	for _, lift := range t.Lifts() {
+		if maxes[lift] <= 0 {
+			return fmt.Errorf("%w: missing training max for %s", ErrInvalidFields, lift)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package program
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scrot/musclemem-api/internal/catalog"
+)
+
+func TestTemplates(t *testing.T) {
+	ts, err := Templates()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ts) < 4 {
+		t.Fatalf("want at least 4 templates but got %d", len(ts))
+	}
+
+	library, err := catalog.Library()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slugs := make(map[string]bool)
+	for _, e := range library {
+		slugs[e.Slug] = true
+	}
+
+	for _, tmpl := range ts {
+		t.Run(tmpl.Slug, func(t *testing.T) {
+			maxes := make(map[string]float64)
+			for _, lift := range tmpl.Lifts() {
+				maxes[lift] = 100
+			}
+
+			p := Program{Name: tmpl.Name, Phases: tmpl.Phases}
+			for i, w := range tmpl.Workouts {
+				for j, x := range w.Exercises {
+					if x.Catalog != "" && !slugs[x.Catalog] {
+						t.Fatalf("unknown catalog entry %q", x.Catalog)
+					}
+
+					e := x.Exercise(maxes)
+					if err := e.Validate(); err != nil {
+						t.Fatalf("invalid exercise %s: %v", x.Name, err)
+					}
+
+					if e.Progression != nil {
+						if err := e.Progression.Validate(e.Kind); err != nil {
+							t.Fatalf("invalid progression of %s: %v", x.Name, err)
+						}
+					}
+
+					p.Prescriptions = append(p.Prescriptions, x.Prescriptions(i+1, j+1)...)
+				}
+			}
+
+			if err := p.Validate(); err != nil {
+				t.Fatalf("invalid program: %v", err)
+			}
+		})
+	}
+
+	if _, err := FindTemplate("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want error %v but got %v", ErrNotFound, err)
+	}
+}
@@ -0,0 +1,48 @@
+package record
+
+import (
+	"database/sql"
+	"errors"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidFields = errors.New("contains invalid fields")
+)
+
+// RecordStore represents the personal records repository
+type RecordStore interface {
+	Retreiver
+	Detector
+	Rebuilder
+}
+
+// Retreiver implementations allow for records to be queried
+type Retreiver interface {
+	// ByOwner returns all records of owner ordered by exercise
+	ByOwner(owner string) ([]Record, error)
+
+	// ByExercise returns the records of owner for an exercise, exercise
+	// is a catalog reference or the name of the exercise
+	ByExercise(owner string, exercise string) ([]Record, error)
+
+	// BySessions returns the records set during the sessions that still
+	// stand by session ordered by exercise, sessions without records are
+	// left out
+	BySessions(sessions []SessionRef) (map[SessionRef][]Record, error)
+}
+
+// Detector implementations allow for records to be detected
+type Detector interface {
+	// Detect compares the sets logged during a session with the
+	// existing records, stores and returns the records that were beaten
+	Detect(owner string, session int) ([]Record, error)
+}
+
+// Rebuilder implementations allow for records to be recomputed
+type Rebuilder interface {
+	// Rebuild recomputes the records of the exercises that had a record set
+	// during session from the finished sessions within tx, it's used
+	// when the session is deleted so the records it beat stand again
+	Rebuild(tx *sql.Tx, owner string, session int) error
+}
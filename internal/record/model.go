@@ -0,0 +1,187 @@
+package record
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/scrot/musclemem-api/internal/strength"
+)
+
+// Type is the kind of personal record
+type Type string
+
+const (
+	TypeHeaviestWeight  Type = "heaviest_weight"
+	TypeMostRepetitions Type = "most_repetitions"
+	TypeBestE1RM        Type = "best_e1rm"
+	TypeBestVolume      Type = "best_volume"
+)
+
+// Types contains all record types
+var Types = []Type{TypeHeaviestWeight, TypeMostRepetitions, TypeBestE1RM, TypeBestVolume}
+
+// Record is the best performance of an exercise for a record type, Exercise
+// is the catalog reference or the lowercased name of the exercise. Value is
+// the weight, repetitions, estimated one-rep max or volume depending on the
+// type, most repetitions records are kept per Weight. Previous is the value
+// of the record that was beaten, if any
+type Record struct {
+	Owner       string  `json:"owner"`
+	Exercise    string  `json:"exercise"`
+	Name        string  `json:"name"`
+	Type        Type    `json:"type"`
+	Weight      float64 `json:"weight"`
+	Repetitions int     `json:"repetitions,omitempty"`
+	Value       float64 `json:"value"`
+	Previous    float64 `json:"previous,omitempty"`
+	Session     int     `json:"session"`
+	Date        string  `json:"date"`
+}
+
+// SessionRef identifies a session of an owner
+type SessionRef struct {
+	Owner   string
+	Session int
+}
+
+func (r Record) String() string {
+	return fmt.Sprintf("record %s/%s %s: %.2f", r.Owner, r.Exercise, r.Type, r.Value)
+}
+
+// key identifies the record within the records of an exercise
+type key struct {
+	Type   Type
+	Weight float64
+}
+
+func (r Record) key() key {
+	if r.Type == TypeMostRepetitions {
+		return key{r.Type, r.Weight}
+	}
+	return key{r.Type, 0}
+}
+
+// Lift is a logged set of a session exercise measured in repetitions,
+// Load is the weight moved per repetition which includes the body
+// weight for bodyweight exercises
+type Lift struct {
+	Name        string
+	Catalog     string
+	Weight      float64
+	Load        float64
+	Repetitions int
+}
+
+// exercise returns the key records of the lift are stored under
+func (l Lift) exercise() string {
+	if l.Catalog != "" {
+		return l.Catalog
+	}
+	return strings.ToLower(l.Name)
+}
+
+// candidates returns the best performances of a session per exercise
+// and record type, the lifts must be of the same session
+func candidates(owner string, session int, date string, lifts []Lift) []Record {
+	var (
+		order []string
+		best  = make(map[string]map[key]Record)
+	)
+
+	keep := func(r Record) {
+		if r.Value <= 0 {
+			return
+		}
+
+		rs, ok := best[r.Exercise]
+		if !ok {
+			rs = make(map[key]Record)
+			best[r.Exercise] = rs
+			order = append(order, r.Exercise)
+		}
+
+		if current, ok := rs[r.key()]; !ok || r.Value > current.Value {
+			rs[r.key()] = r
+		}
+	}
+
+	volume := make(map[string]float64)
+	for _, l := range lifts {
+		r := Record{
+			Owner:       owner,
+			Exercise:    l.exercise(),
+			Name:        l.Name,
+			Weight:      l.Weight,
+			Repetitions: l.Repetitions,
+			Session:     session,
+			Date:        date,
+		}
+
+		keep(with(r, TypeHeaviestWeight, l.Weight))
+		keep(with(r, TypeMostRepetitions, float64(l.Repetitions)))
+		keep(with(r, TypeBestE1RM, strength.OneRepMax(l.Weight, l.Repetitions)))
+
+		volume[r.Exercise] += l.Load * float64(l.Repetitions)
+		v := with(r, TypeBestVolume, volume[r.Exercise])
+		v.Weight, v.Repetitions = 0, 0
+		keep(v)
+	}
+
+	var rs []Record
+	for _, x := range order {
+		for _, r := range best[x] {
+			rs = append(rs, r)
+		}
+	}
+
+	position := make(map[string]int, len(order))
+	for i, x := range order {
+		position[x] = i
+	}
+
+	sort.Slice(rs, func(i, j int) bool {
+		a, b := rs[i], rs[j]
+		if a.Exercise != b.Exercise {
+			return position[a.Exercise] < position[b.Exercise]
+		}
+		if a.Type != b.Type {
+			return slices.Index(Types, a.Type) < slices.Index(Types, b.Type)
+		}
+		return a.Weight < b.Weight
+	})
+
+	return rs
+}
+
+// beaten returns the candidates that beat the existing records of the
+// exercise, setting the value of the record they beat as Previous
+func beaten(cs []Record, existing []Record) []Record {
+	current := make(map[string]map[key]Record)
+	for _, r := range existing {
+		if _, ok := current[r.Exercise]; !ok {
+			current[r.Exercise] = make(map[key]Record)
+		}
+		current[r.Exercise][r.key()] = r
+	}
+
+	var rs []Record
+	for _, c := range cs {
+		prev, ok := current[c.Exercise][c.key()]
+		if ok && c.Value <= prev.Value {
+			continue
+		}
+
+		c.Previous = prev.Value
+		rs = append(rs, c)
+	}
+
+	return rs
+}
+
+func with(r Record, t Type, value float64) Record {
+	r.Type = t
+	r.Value = value
+	return r
+}
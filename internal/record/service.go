@@ -0,0 +1,47 @@
+package record
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/scrot/musclemem-api/internal/api"
+)
+
+// NewFetchAllHandler returns the personal records of a user
+// requires {username} path variable, optional exercise query parameter
+// to only return the records of a single exercise
+func NewFetchAllHandler(l *slog.Logger, records Retreiver) http.Handler {
+	l = l.With("handler", "FetchAllHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			exercise = r.URL.Query().Get("exercise")
+		)
+
+		l := l.With("user", username, "exercise", exercise)
+
+		var (
+			rs  []Record
+			err error
+		)
+
+		if exercise == "" {
+			rs, err = records.ByOwner(username)
+		} else {
+			rs, err = records.ByExercise(username, exercise)
+		}
+
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched user records", "count", len(rs))
+
+		if err := api.WriteJSON(w, http.StatusOK, rs); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
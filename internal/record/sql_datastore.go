@@ -0,0 +1,397 @@
+package record
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/scrot/musclemem-api/internal/body"
+	"github.com/scrot/musclemem-api/internal/exercise"
+	"github.com/scrot/musclemem-api/internal/storage"
+)
+
+const recordColumns = `owner, exercise, name, record_type, weight, repetitions, value, session, performed_on`
+
+// upsertStmt stores a record replacing the record it beat, requires
+// the record and AtWeight, the weight the record is kept at
+const upsertStmt = `
+  INSERT INTO records (` + recordColumns + `, at_weight)
+  VALUES ({{ .Owner }}, {{ .Exercise }}, {{ .Name }}, {{ .Type }}, {{ .Weight }},
+    {{ .Repetitions }}, {{ .Value }}, {{ .Session }}, {{ .Date }}, {{ .AtWeight }})
+  ON CONFLICT (owner, exercise, record_type, at_weight) DO UPDATE
+  SET name = excluded.name, weight = excluded.weight, repetitions = excluded.repetitions,
+    value = excluded.value, session = excluded.session, performed_on = excluded.performed_on
+  `
+
+type SQLRecordStore struct {
+	*storage.SqlDatastore
+}
+
+func NewSQLRecordStore(db *storage.SqlDatastore) *SQLRecordStore {
+	return &SQLRecordStore{db}
+}
+
+func (rs *SQLRecordStore) ByOwner(owner string) ([]Record, error) {
+	const stmt = `
+  SELECT ` + recordColumns + `
+  FROM records
+  WHERE owner = {{ . }}
+  ORDER BY exercise, record_type, at_weight
+  `
+
+	if owner == "" {
+		return []Record{}, fmt.Errorf("ByOwner: %w", ErrInvalidFields)
+	}
+
+	records, err := rs.query(stmt, owner)
+	if err != nil {
+		return []Record{}, fmt.Errorf("ByOwner: %w", err)
+	}
+
+	return records, nil
+}
+
+func (rs *SQLRecordStore) ByExercise(owner string, exercise string) ([]Record, error) {
+	const stmt = `
+  SELECT ` + recordColumns + `
+  FROM records
+  WHERE owner = {{ .Owner }} AND (exercise = {{ .Exercise }} OR exercise = {{ .Name }})
+  ORDER BY record_type, at_weight
+  `
+
+	if owner == "" || exercise == "" {
+		return []Record{}, fmt.Errorf("ByExercise: %w", ErrInvalidFields)
+	}
+
+	data := struct {
+		Owner    string
+		Exercise string
+		Name     string
+	}{owner, exercise, strings.ToLower(exercise)}
+
+	records, err := rs.query(stmt, data)
+	if err != nil {
+		return []Record{}, fmt.Errorf("ByExercise: %w", err)
+	}
+
+	return records, nil
+}
+
+func (rs *SQLRecordStore) BySessions(sessions []SessionRef) (map[SessionRef][]Record, error) {
+	const stmt = `
+  SELECT ` + recordColumns + `
+  FROM records
+  WHERE {{ range $i, $s := . }}{{ if $i }} OR {{ end }}(owner = {{ $s.Owner }} AND session = {{ $s.Session }}){{ end }}
+  ORDER BY owner, session, exercise, record_type, at_weight
+  `
+
+	bySession := make(map[SessionRef][]Record)
+	if len(sessions) == 0 {
+		return bySession, nil
+	}
+
+	for _, s := range sessions {
+		if s.Owner == "" {
+			return nil, fmt.Errorf("BySessions: %w", ErrInvalidFields)
+		}
+	}
+
+	records, err := rs.query(stmt, sessions)
+	if err != nil {
+		return nil, fmt.Errorf("BySessions: %w", err)
+	}
+
+	for _, r := range records {
+		ref := SessionRef{r.Owner, r.Session}
+		bySession[ref] = append(bySession[ref], r)
+	}
+
+	return bySession, nil
+}
+
+func (rs *SQLRecordStore) Detect(owner string, session int) ([]Record, error) {
+	const (
+		liftsStmt = `
+    SELECT x.name, x.catalog, t.weight, %s, t.repetitions, s.performed_on
+    FROM session_sets t
+    JOIN session_exercises x ON x.owner = t.owner AND x.session = t.session AND x.exercise_index = t.exercise
+    JOIN sessions s ON s.owner = t.owner AND s.session_index = t.session
+    WHERE t.owner = {{ .Owner }} AND t.session = {{ .Session }}
+      AND t.repetitions > 0 AND t.completed AND NOT t.warmup AND x.kind IN ({{ .Weighted }}, {{ .Bodyweight }})
+    ORDER BY x.exercise_index, t.set_index
+    `
+	)
+
+	if owner == "" || session <= 0 {
+		return []Record{}, fmt.Errorf("Detect: %w", ErrInvalidFields)
+	}
+
+	data := struct {
+		Owner      string
+		Session    int
+		Weighted   exercise.Kind
+		Bodyweight exercise.Kind
+	}{owner, session, exercise.KindWeighted, exercise.KindBodyweight}
+
+	load := body.LoadExpr("x.kind", "t.weight", body.WeightExpr("s.owner", "s.performed_on"))
+
+	q, args, err := rs.CompileStatement(fmt.Sprintf(liftsStmt, load), data)
+	if err != nil {
+		return []Record{}, fmt.Errorf("Detect: compile: %w", err)
+	}
+
+	rows, err := rs.Query(q, args...)
+	if err != nil {
+		return []Record{}, fmt.Errorf("Detect: query: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		date  string
+		lifts []Lift
+	)
+
+	for rows.Next() {
+		var l Lift
+		if err := rows.Scan(&l.Name, &l.Catalog, &l.Weight, &l.Load, &l.Repetitions, &date); err != nil {
+			return []Record{}, fmt.Errorf("Detect: scan: %w", err)
+		}
+		lifts = append(lifts, l)
+	}
+
+	cs := candidates(owner, session, date, lifts)
+	if len(cs) == 0 {
+		return []Record{}, nil
+	}
+
+	existing, err := rs.ByOwner(owner)
+	if err != nil {
+		return []Record{}, fmt.Errorf("Detect: %w", err)
+	}
+
+	records := beaten(cs, existing)
+
+	tx, err := rs.Begin()
+	if err != nil {
+		return []Record{}, fmt.Errorf("Detect: begin transaction: %w", err)
+	}
+
+	for _, r := range records {
+		if err := rs.upsert(tx, r); err != nil {
+			tx.Rollback()
+			return []Record{}, fmt.Errorf("Detect: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return []Record{}, fmt.Errorf("Detect: commit transaction: %w", err)
+	}
+
+	if records == nil {
+		records = []Record{}
+	}
+
+	return records, nil
+}
+
+func (rs *SQLRecordStore) Rebuild(tx *sql.Tx, owner string, session int) error {
+	const (
+		exercisesStmt = `
+    SELECT DISTINCT exercise
+    FROM records
+    WHERE owner = {{ .Owner }} AND session = {{ .Session }}
+    `
+
+		clearStmt = `
+    DELETE FROM records
+    WHERE owner = {{ .Owner }} AND exercise = {{ .Exercise }}
+    `
+
+		liftsStmt = `
+    SELECT s.session_index, s.performed_on, x.name, x.catalog, t.weight, %s, t.repetitions
+    FROM session_sets t
+    JOIN session_exercises x ON x.owner = t.owner AND x.session = t.session AND x.exercise_index = t.exercise
+    JOIN sessions s ON s.owner = t.owner AND s.session_index = t.session
+    WHERE t.owner = {{ .Owner }} AND s.finished_at IS NOT NULL
+      AND (x.catalog = {{ .Exercise }} OR (x.catalog = '' AND LOWER(x.name) = {{ .Exercise }}))
+      AND t.repetitions > 0 AND t.completed AND NOT t.warmup AND x.kind IN ({{ .Weighted }}, {{ .Bodyweight }})
+    ORDER BY s.performed_on, s.session_index, x.exercise_index, t.set_index
+    `
+	)
+
+	if owner == "" || session <= 0 {
+		return fmt.Errorf("Rebuild: %w", ErrInvalidFields)
+	}
+
+	data := struct {
+		Owner      string
+		Session    int
+		Exercise   string
+		Weighted   exercise.Kind
+		Bodyweight exercise.Kind
+	}{Owner: owner, Session: session, Weighted: exercise.KindWeighted, Bodyweight: exercise.KindBodyweight}
+
+	q, args, err := rs.CompileStatement(exercisesStmt, data)
+	if err != nil {
+		return fmt.Errorf("Rebuild: compile exercises: %w", err)
+	}
+
+	rows, err := tx.Query(q, args...)
+	if err != nil {
+		return fmt.Errorf("Rebuild: query exercises: %w", err)
+	}
+	defer rows.Close()
+
+	var exercises []string
+	for rows.Next() {
+		var e string
+		if err := rows.Scan(&e); err != nil {
+			return fmt.Errorf("Rebuild: scan exercise: %w", err)
+		}
+		exercises = append(exercises, e)
+	}
+
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("Rebuild: close exercises: %w", err)
+	}
+
+	load := body.LoadExpr("x.kind", "t.weight", body.WeightExpr("s.owner", "s.performed_on"))
+
+	for _, e := range exercises {
+		data.Exercise = e
+
+		q, args, err := rs.CompileStatement(clearStmt, data)
+		if err != nil {
+			return fmt.Errorf("Rebuild: compile clear: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			return fmt.Errorf("Rebuild: clear %s: %w", e, err)
+		}
+
+		q, args, err = rs.CompileStatement(fmt.Sprintf(liftsStmt, load), data)
+		if err != nil {
+			return fmt.Errorf("Rebuild: compile lifts: %w", err)
+		}
+
+		rows, err := tx.Query(q, args...)
+		if err != nil {
+			return fmt.Errorf("Rebuild: query lifts: %w", err)
+		}
+
+		// the sessions are replayed in the order they were performed,
+		// keeping the records that still stand without the session
+		var (
+			standing = make(map[key]Record)
+			current  int
+			date     string
+			lifts    []Lift
+		)
+
+		replay := func() {
+			existing := make([]Record, 0, len(standing))
+			for _, r := range standing {
+				existing = append(existing, r)
+			}
+
+			for _, r := range beaten(candidates(owner, current, date, lifts), existing) {
+				standing[r.key()] = r
+			}
+		}
+
+		for rows.Next() {
+			var (
+				si int
+				on string
+				l  Lift
+			)
+
+			if err := rows.Scan(&si, &on, &l.Name, &l.Catalog, &l.Weight, &l.Load, &l.Repetitions); err != nil {
+				rows.Close()
+				return fmt.Errorf("Rebuild: scan lift: %w", err)
+			}
+
+			if si != current && len(lifts) > 0 {
+				replay()
+				lifts = nil
+			}
+
+			current, date = si, on
+			lifts = append(lifts, l)
+		}
+
+		if err := rows.Close(); err != nil {
+			return fmt.Errorf("Rebuild: close lifts: %w", err)
+		}
+
+		if len(lifts) > 0 {
+			replay()
+		}
+
+		for _, r := range standing {
+			if err := rs.upsert(tx, r); err != nil {
+				return fmt.Errorf("Rebuild: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// upsert stores the record within tx replacing the record it beat
+func (rs *SQLRecordStore) upsert(tx *sql.Tx, r Record) error {
+	data := struct {
+		Record
+		AtWeight float64
+	}{r, r.key().Weight}
+
+	q, args, err := rs.CompileStatement(upsertStmt, data)
+	if err != nil {
+		return fmt.Errorf("upsert: compile: %w", err)
+	}
+
+	if _, err := tx.Exec(q, args...); err != nil {
+		return fmt.Errorf("upsert %s: %w", r, err)
+	}
+
+	return nil
+}
+
+// query compiles and executes stmt returning the scanned records
+func (rs *SQLRecordStore) query(stmt string, data any) ([]Record, error) {
+	q, args, err := rs.CompileStatement(stmt, data)
+	if err != nil {
+		return nil, fmt.Errorf("compile: %w", err)
+	}
+
+	rows, err := rs.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	records := []Record{}
+	for rows.Next() {
+		var r Record
+		if err := rows.Scan(
+			&r.Owner,
+			&r.Exercise,
+			&r.Name,
+			&r.Type,
+			&r.Weight,
+			&r.Repetitions,
+			&r.Value,
+			&r.Session,
+			&r.Date,
+		); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		records = append(records, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,126 @@
+package record
+
+import (
+	"testing"
+
+	"github.com/scrot/musclemem-api/internal/session/sessiontest"
+	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/user"
+)
+
+func TestDetect(t *testing.T) {
+	records, store, flush := mockRecordStore(t)
+	defer flush()
+
+	// session 1: 100x5, 100x5; session 2: 105x3, 90x12 and
+	// sets that were not completed never set a record
+	logSession(t, store, 1, "2026-10-12", []sessiontest.Set{
+		{Weight: 100, Repetitions: 5, Completed: true},
+		{Weight: 100, Repetitions: 5, Completed: true},
+	})
+	logSession(t, store, 2, "2026-10-19", []sessiontest.Set{
+		{Weight: 105, Repetitions: 3, Completed: true},
+		{Weight: 90, Repetitions: 12, Completed: true},
+		{Weight: 200, Repetitions: 10},
+	})
+
+	first, err := records.Detect("user", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(first) != 4 {
+		t.Fatalf("want 4 records for the first session but got %v", first)
+	}
+
+	second, err := records.Detect("user", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs := []struct {
+		recordType Type
+		weight     float64
+		want       float64
+		previous   float64
+	}{
+		{TypeHeaviestWeight, 0, 105, 100},
+		{TypeMostRepetitions, 90, 12, 0},
+		{TypeMostRepetitions, 105, 3, 0},
+		{TypeBestE1RM, 0, 126, 116.67},
+		{TypeBestVolume, 0, 1395, 1000},
+	}
+
+	if len(second) != len(cs) {
+		t.Fatalf("want %d records for the second session but got %v", len(cs), second)
+	}
+
+	for i, c := range cs {
+		got := second[i]
+		if got.Type != c.recordType || got.Weight != c.weight && c.recordType == TypeMostRepetitions {
+			t.Errorf("want %s at %.1f but got %s", c.recordType, c.weight, got)
+		}
+
+		if got.Value != c.want || got.Previous != c.previous {
+			t.Errorf("%s: want %.2f beating %.2f but got %.2f beating %.2f", c.recordType, c.want, c.previous, got.Value, got.Previous)
+		}
+	}
+
+	// detecting again does not report the same records
+	again, err := records.Detect("user", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(again) != 0 {
+		t.Errorf("want no new records but got %v", again)
+	}
+
+	bench, err := records.ByExercise("user", "Bench Press")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bench) != 6 {
+		t.Errorf("want 6 bench press records but got %d", len(bench))
+	}
+}
+
+// logSession logs a session with a single bench press exercise and its sets
+func logSession(t *testing.T, store *storage.SqlDatastore, session int, date string, sets []sessiontest.Set) {
+	t.Helper()
+
+	sessiontest.Log(t, store, sessiontest.Session{
+		Owner:     "user",
+		Index:     session,
+		Date:      date,
+		Exercises: []sessiontest.Exercise{{Name: "bench press", Sets: sets}},
+	})
+}
+
+func mockRecordStore(t *testing.T) (RecordStore, *storage.SqlDatastore, func()) {
+	t.Helper()
+
+	config := storage.DatastoreConfig{
+		DatabaseURL:   "file://test.db?cache=shared&mode=memory",
+		MigrationPath: "migrations",
+		Overwrite:     false,
+	}
+
+	store, err := storage.NewSqlDatastore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := user.NewSQLUserStore(store).New("user", "test@gmail.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	flush := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal()
+		}
+	}
+
+	return NewSQLRecordStore(store), store, flush
+}
@@ -4,7 +4,22 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/scrot/musclemem-api/internal/body"
+	"github.com/scrot/musclemem-api/internal/catalog"
+	"github.com/scrot/musclemem-api/internal/challenge"
+	"github.com/scrot/musclemem-api/internal/coach"
+	"github.com/scrot/musclemem-api/internal/comment"
+	"github.com/scrot/musclemem-api/internal/equipment"
 	"github.com/scrot/musclemem-api/internal/exercise"
+	"github.com/scrot/musclemem-api/internal/middleware"
+	"github.com/scrot/musclemem-api/internal/program"
+	"github.com/scrot/musclemem-api/internal/record"
+	"github.com/scrot/musclemem-api/internal/schedule"
+	"github.com/scrot/musclemem-api/internal/session"
+	"github.com/scrot/musclemem-api/internal/share"
+	"github.com/scrot/musclemem-api/internal/social"
+	"github.com/scrot/musclemem-api/internal/stats"
+	"github.com/scrot/musclemem-api/internal/strength"
 	"github.com/scrot/musclemem-api/internal/user"
 	"github.com/scrot/musclemem-api/internal/workout"
 )
@@ -15,21 +30,127 @@ func RegisterEndpoints(
 	users user.UserStore,
 	workouts workout.WorkoutStore,
 	exercises exercise.ExerciseStore,
+	entries catalog.CatalogStore,
+	sessions session.SessionStore,
+	records record.RecordStore,
+	statistics stats.StatsStore,
+	schedules schedule.ScheduleStore,
+	programs program.ProgramStore,
+	inventory equipment.EquipmentStore,
+	bodies body.BodyStore,
+	shares share.ShareStore,
+	coaches coach.CoachStore,
+	comments comment.CommentStore,
+	socials social.SocialStore,
+	challenges challenge.ChallengeStore,
 ) {
+	// auth guards the resources of users, coaches can access
+	// the workouts of their athletes with the granted access
+	auth := middleware.Auth(logger, users, coaches)
+
 	mux.Handle("GET /ready", NewReadyHandler(logger))
 	mux.Handle("POST /users", user.NewCreateHandler(logger, users))
-	mux.Handle("GET /users/{username}/workouts", workout.NewFetchAllHandler(logger, workouts))
-	mux.Handle("POST /users/{username}/workouts", workout.NewCreateHandler(logger, workouts))
-	mux.Handle("DELETE /users/{username}/workouts/{workout}", workout.NewDeleteHandler(logger, workouts))
-	mux.Handle("PATCH /users/{username}/workouts/{workout}", workout.NewUpdateHandler(logger, workouts))
-	mux.Handle("GET /users/{username}/workouts/{workout}/exercises", exercise.NewFetchAllHandler(logger, exercises))
-	mux.Handle("POST /users/{username}/workouts/{workout}/exercises", exercise.NewCreateHandler(logger, exercises))
-	mux.Handle("GET /users/{username}/workouts/{workout}/exercises/{exercise}", exercise.NewFetchHandler(logger, exercises))
-	mux.Handle("PATCH /users/{username}/workouts/{workout}/exercises/{exercise}", exercise.NewUpdateHandler(logger, exercises))
-	mux.Handle("DELETE /users/{username}/workouts/{workout}/exercises/{exercise}", exercise.NewDeleteHandler(logger, exercises))
-	mux.Handle("PUT /users/{username}/workouts/{workout}/exercises/{exercise}/up", exercise.NewUpHandler(logger, exercises))
-	mux.Handle("PUT /users/{username}/workouts/{workout}/exercises/{exercise}/down", exercise.NewDownHandler(logger, exercises))
-	mux.Handle("POST /users/{username}/workouts/{workout}/exercises/{exercise}/swap", exercise.NewSwapHandler(logger, exercises))
+	mux.Handle("GET /users/{username}/workouts", auth(workout.NewFetchAllHandler(logger, workouts)))
+	mux.Handle("POST /users/{username}/workouts", auth(workout.NewCreateHandler(logger, workouts)))
+	mux.Handle("POST /users/{username}/workouts/import", auth(share.NewImportHandler(logger, shares)))
+	mux.Handle("DELETE /users/{username}/workouts/{workout}", auth(workout.NewDeleteHandler(logger, workouts)))
+	mux.Handle("PATCH /users/{username}/workouts/{workout}", auth(workout.NewUpdateHandler(logger, workouts)))
+	mux.Handle("PUT /users/{username}/workouts/{workout}/tags/{tag}", auth(workout.NewTagHandler(logger, workouts)))
+	mux.Handle("DELETE /users/{username}/workouts/{workout}/tags/{tag}", auth(workout.NewUntagHandler(logger, workouts)))
+	mux.Handle("GET /users/{username}/tags", auth(workout.NewTagsHandler(logger, workouts)))
+	mux.Handle("PATCH /users/{username}/tags/{tag}", auth(workout.NewRenameTagHandler(logger, workouts)))
+	mux.Handle("DELETE /users/{username}/tags/{tag}", auth(workout.NewDeleteTagHandler(logger, workouts)))
+	mux.Handle("GET /users/{username}/folders", auth(workout.NewFoldersHandler(logger, workouts)))
+	mux.Handle("GET /users/{username}/workouts/{workout}/shares", auth(share.NewFetchAllHandler(logger, shares)))
+	mux.Handle("POST /users/{username}/workouts/{workout}/shares", auth(share.NewCreateHandler(logger, shares)))
+	mux.Handle("DELETE /users/{username}/shares/{token}", auth(share.NewRevokeHandler(logger, shares)))
+	mux.Handle("GET /shared/{token}", share.NewSharedHandler(logger, shares))
+	mux.Handle("GET /users/{username}/workouts/{workout}/comments", auth(comment.NewThreadHandler(logger, comment.TargetWorkout, comments)))
+	mux.Handle("POST /users/{username}/workouts/{workout}/comments", auth(comment.NewCreateHandler(logger, comment.TargetWorkout, comments)))
+	mux.Handle("PATCH /users/{username}/workouts/{workout}/comments/{comment}", auth(comment.NewEditHandler(logger, comment.TargetWorkout, comments)))
+	mux.Handle("DELETE /users/{username}/workouts/{workout}/comments/{comment}", auth(comment.NewDeleteHandler(logger, comment.TargetWorkout, comments)))
+	mux.Handle("GET /users/{username}/workouts/{workout}/exercises", auth(exercise.NewFetchAllHandler(logger, exercises)))
+	mux.Handle("POST /users/{username}/workouts/{workout}/exercises", auth(exercise.NewCreateHandler(logger, exercises)))
+	mux.Handle("GET /users/{username}/workouts/{workout}/exercises/{exercise}", auth(exercise.NewFetchHandler(logger, exercises)))
+	mux.Handle("PATCH /users/{username}/workouts/{workout}/exercises/{exercise}", auth(exercise.NewUpdateHandler(logger, exercises)))
+	mux.Handle("DELETE /users/{username}/workouts/{workout}/exercises/{exercise}", auth(exercise.NewDeleteHandler(logger, exercises)))
+	mux.Handle("PUT /users/{username}/workouts/{workout}/exercises/{exercise}/up", auth(exercise.NewUpHandler(logger, exercises)))
+	mux.Handle("PUT /users/{username}/workouts/{workout}/exercises/{exercise}/down", auth(exercise.NewDownHandler(logger, exercises)))
+	mux.Handle("POST /users/{username}/workouts/{workout}/exercises/{exercise}/swap", auth(exercise.NewSwapHandler(logger, exercises)))
+	mux.Handle("GET /users/{username}/workouts/{workout}/exercises/{exercise}/progressions", auth(exercise.NewProgressionsHandler(logger, exercises)))
+	mux.Handle("GET /users/{username}/workouts/{workout}/exercises/{exercise}/warmup", auth(exercise.NewWarmupHandler(logger, exercises, inventory)))
+	mux.Handle("POST /users/{username}/workouts/{workout}/groups", auth(exercise.NewGroupHandler(logger, exercises)))
+	mux.Handle("DELETE /users/{username}/workouts/{workout}/groups/{group}", auth(exercise.NewUngroupHandler(logger, exercises)))
+	mux.Handle("GET /catalog/exercises", catalog.NewSearchHandler(logger, entries))
+	mux.Handle("GET /calc/1rm", strength.NewOneRepMaxHandler(logger))
+	mux.Handle("GET /programs/templates", program.NewTemplatesHandler(logger))
+	mux.Handle("GET /catalog/exercises/{slug}", catalog.NewFetchHandler(logger, entries))
+	mux.Handle("GET /users/{username}/catalog", auth(catalog.NewFetchAllHandler(logger, entries)))
+	mux.Handle("POST /users/{username}/catalog", auth(catalog.NewCreateHandler(logger, entries)))
+	mux.Handle("GET /users/{username}/catalog/search", auth(catalog.NewSearchHandler(logger, entries)))
+	mux.Handle("GET /users/{username}/catalog/{slug}", auth(catalog.NewFetchHandler(logger, entries)))
+	mux.Handle("DELETE /users/{username}/catalog/{slug}", auth(catalog.NewDeleteHandler(logger, entries)))
+	mux.Handle("GET /users/{username}/sessions", auth(session.NewFetchAllHandler(logger, sessions)))
+	mux.Handle("POST /users/{username}/sessions", auth(session.NewStartHandler(logger, sessions)))
+	mux.Handle("GET /users/{username}/sessions/{session}", auth(session.NewFetchHandler(logger, sessions)))
+	mux.Handle("DELETE /users/{username}/sessions/{session}", auth(session.NewDeleteHandler(logger, sessions)))
+	mux.Handle("POST /users/{username}/sessions/{session}/finish", auth(session.NewFinishHandler(logger, sessions, records, exercises)))
+	mux.Handle("POST /users/{username}/sessions/{session}/exercises/{exercise}/sets", auth(session.NewLogSetHandler(logger, sessions)))
+	mux.Handle("POST /users/{username}/sessions/{session}/exercises/{exercise}/warmup", auth(session.NewWarmupHandler(logger, sessions)))
+	mux.Handle("GET /users/{username}/sessions/{session}/comments", auth(comment.NewThreadHandler(logger, comment.TargetSession, comments)))
+	mux.Handle("POST /users/{username}/sessions/{session}/comments", auth(comment.NewCreateHandler(logger, comment.TargetSession, comments)))
+	mux.Handle("PATCH /users/{username}/sessions/{session}/comments/{comment}", auth(comment.NewEditHandler(logger, comment.TargetSession, comments)))
+	mux.Handle("DELETE /users/{username}/sessions/{session}/comments/{comment}", auth(comment.NewDeleteHandler(logger, comment.TargetSession, comments)))
+	mux.Handle("GET /users/{username}/history", auth(session.NewHistoryHandler(logger, sessions)))
+	mux.Handle("GET /users/{username}/records", auth(record.NewFetchAllHandler(logger, records)))
+	mux.Handle("GET /users/{username}/stats", auth(stats.NewFetchHandler(logger, statistics)))
+	mux.Handle("GET /users/{username}/calendar", auth(stats.NewCalendarHandler(logger, statistics)))
+	mux.Handle("GET /users/{username}/schedule", auth(schedule.NewFetchHandler(logger, schedules)))
+	mux.Handle("PUT /users/{username}/schedule", auth(schedule.NewSetHandler(logger, schedules)))
+	mux.Handle("DELETE /users/{username}/schedule", auth(schedule.NewDeleteHandler(logger, schedules)))
+	mux.Handle("GET /users/{username}/today", auth(schedule.NewTodayHandler(logger, schedules)))
+	mux.Handle("GET /users/{username}/equipment", auth(equipment.NewFetchHandler(logger, inventory)))
+	mux.Handle("PUT /users/{username}/equipment", auth(equipment.NewSetHandler(logger, inventory)))
+	mux.Handle("DELETE /users/{username}/equipment", auth(equipment.NewDeleteHandler(logger, inventory)))
+	mux.Handle("GET /users/{username}/equipment/plates", auth(equipment.NewPlatesHandler(logger, inventory)))
+	mux.Handle("GET /users/{username}/body", auth(body.NewFetchAllHandler(logger, bodies)))
+	mux.Handle("POST /users/{username}/body", auth(body.NewCreateHandler(logger, bodies)))
+	mux.Handle("GET /users/{username}/body/trend", auth(body.NewTrendHandler(logger, bodies)))
+	mux.Handle("GET /users/{username}/body/{date}", auth(body.NewFetchHandler(logger, bodies)))
+	mux.Handle("PUT /users/{username}/body/{date}", auth(body.NewReplaceHandler(logger, bodies)))
+	mux.Handle("DELETE /users/{username}/body/{date}", auth(body.NewDeleteHandler(logger, bodies)))
+	mux.Handle("GET /users/{username}/coaches", auth(coach.NewCoachesHandler(logger, coaches)))
+	mux.Handle("POST /users/{username}/coaches", auth(coach.NewInviteHandler(logger, coaches)))
+	mux.Handle("DELETE /users/{username}/coaches/{coach}", auth(coach.NewRevokeHandler(logger, coaches)))
+	mux.Handle("GET /users/{username}/invitations", auth(coach.NewInvitationsHandler(logger, coaches)))
+	mux.Handle("POST /users/{username}/invitations/{athlete}/accept", auth(coach.NewAcceptHandler(logger, coaches)))
+	mux.Handle("DELETE /users/{username}/invitations/{athlete}", auth(coach.NewDeclineHandler(logger, coaches)))
+	mux.Handle("GET /users/{username}/athletes", auth(coach.NewAthletesHandler(logger, coaches)))
+	mux.Handle("GET /users/{username}/privacy", auth(social.NewFetchPrivacyHandler(logger, socials)))
+	mux.Handle("PUT /users/{username}/privacy", auth(social.NewSetPrivacyHandler(logger, socials)))
+	mux.Handle("GET /users/{username}/following", auth(social.NewFollowingHandler(logger, socials)))
+	mux.Handle("POST /users/{username}/following", auth(social.NewFollowHandler(logger, socials)))
+	mux.Handle("DELETE /users/{username}/following/{followee}", auth(social.NewUnfollowHandler(logger, socials)))
+	mux.Handle("GET /users/{username}/followers", auth(social.NewFollowersHandler(logger, socials)))
+	mux.Handle("POST /users/{username}/followers/{follower}/accept", auth(social.NewAcceptHandler(logger, socials)))
+	mux.Handle("DELETE /users/{username}/followers/{follower}", auth(social.NewRemoveHandler(logger, socials)))
+	mux.Handle("GET /feed", auth(social.NewFeedHandler(logger, socials)))
+	mux.Handle("GET /challenges", auth(challenge.NewFetchAllHandler(logger, challenges)))
+	mux.Handle("POST /challenges", auth(challenge.NewCreateHandler(logger, challenges)))
+	mux.Handle("GET /challenges/{challenge}", auth(challenge.NewFetchHandler(logger, challenges)))
+	mux.Handle("DELETE /challenges/{challenge}", auth(challenge.NewDeleteHandler(logger, challenges)))
+	mux.Handle("POST /challenges/{challenge}/participants", auth(challenge.NewJoinHandler(logger, challenges)))
+	mux.Handle("DELETE /challenges/{challenge}/participants/{participant}", auth(challenge.NewLeaveHandler(logger, challenges)))
+	mux.Handle("GET /challenges/{challenge}/leaderboard", auth(challenge.NewLeaderboardHandler(logger, challenges)))
+	mux.Handle("GET /users/{username}/programs", auth(program.NewFetchAllHandler(logger, programs)))
+	mux.Handle("POST /users/{username}/programs", auth(program.NewCreateHandler(logger, programs)))
+	mux.Handle("POST /users/{username}/programs/instantiate", auth(program.NewInstantiateHandler(logger, programs)))
+	mux.Handle("GET /users/{username}/programs/{program}", auth(program.NewFetchHandler(logger, programs)))
+	mux.Handle("DELETE /users/{username}/programs/{program}", auth(program.NewDeleteHandler(logger, programs)))
+	mux.Handle("POST /users/{username}/programs/{program}/enroll", auth(program.NewEnrollHandler(logger, programs)))
+	mux.Handle("GET /users/{username}/programs/{program}/enrollment", auth(program.NewEnrollmentHandler(logger, programs)))
+	mux.Handle("DELETE /users/{username}/programs/{program}/enrollment", auth(program.NewUnenrollHandler(logger, programs)))
+	mux.Handle("GET /users/{username}/programs/{program}/current", auth(program.NewCurrentHandler(logger, programs)))
 }
 
 func NewReadyHandler(l *slog.Logger) http.Handler {
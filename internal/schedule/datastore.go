@@ -0,0 +1,39 @@
+package schedule
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidFields = errors.New("contains invalid fields")
+)
+
+// ScheduleStore represents the workout schedule repository
+type ScheduleStore interface {
+	Retreiver
+	Storer
+	Deleter
+}
+
+// Retreiver implementations allow for schedules to be queried
+type Retreiver interface {
+	// ByOwner returns the schedule of owner, it returns
+	// an ErrNotFound error if owner has no schedule
+	ByOwner(owner string) (Schedule, error)
+
+	// Today returns the workout due today in the time zone of the schedule
+	// based on the schedule and the most recent sessions of owner
+	Today(owner string) (Today, error)
+}
+
+// Storer implementations allow for schedules to be set
+type Storer interface {
+	// Set replaces the schedule of owner,
+	// the scheduled workouts must exist
+	Set(owner string, schedule Schedule) (Schedule, error)
+}
+
+// Deleter implementations allow for schedules to be deleted
+type Deleter interface {
+	// Delete deletes the schedule of owner
+	Delete(owner string) (Schedule, error)
+}
@@ -0,0 +1,230 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateLayout is the layout of the local dates of a schedule
+const DateLayout = time.DateOnly
+
+// Mode determines how workouts are scheduled
+type Mode string
+
+const (
+	// ModeWeekly assigns workouts to days of the week
+	ModeWeekly Mode = "weekly"
+
+	// ModeRotation cycles through the workouts in order, the next
+	// workout is due the day after the previous one was performed
+	ModeRotation Mode = "rotation"
+)
+
+// Schedule determines when the workouts of a user are due, TimeZone is
+// the IANA time zone the user trains in, used to determine the local date
+type Schedule struct {
+	Owner    string `json:"owner"`
+	Mode     Mode   `json:"mode"`
+	TimeZone string `json:"time_zone"`
+	Slots    []Slot `json:"slots"`
+}
+
+func (s Schedule) String() string {
+	return fmt.Sprintf("schedule %s: %s of %d workouts in %s", s.Owner, s.Mode, len(s.Slots), s.TimeZone)
+}
+
+// Slot schedules a workout, Day is the lowercase name of the day of the
+// week the workout is due for weekly schedules and empty for rotations
+type Slot struct {
+	Day     string `json:"day,omitempty"`
+	Workout int    `json:"workout"`
+}
+
+// Validate checks if the schedule is consistent with its mode
+// and defaults the time zone to UTC
+func (s *Schedule) Validate() error {
+	if s.TimeZone == "" {
+		s.TimeZone = "UTC"
+	}
+
+	if _, err := time.LoadLocation(s.TimeZone); err != nil {
+		return fmt.Errorf("%w: unknown time zone %q", ErrInvalidFields, s.TimeZone)
+	}
+
+	if len(s.Slots) == 0 {
+		return fmt.Errorf("%w: schedule requires at least one workout", ErrInvalidFields)
+	}
+
+	var (
+		days     = make(map[string]bool)
+		workouts = make(map[int]bool)
+	)
+
+	for _, slot := range s.Slots {
+		if slot.Workout <= 0 {
+			return fmt.Errorf("%w: invalid workout %d", ErrInvalidFields, slot.Workout)
+		}
+
+		switch s.Mode {
+		case ModeWeekly:
+			if _, ok := weekday(slot.Day); !ok {
+				return fmt.Errorf("%w: unknown day %q", ErrInvalidFields, slot.Day)
+			}
+			if days[slot.Day] {
+				return fmt.Errorf("%w: multiple workouts on %s", ErrInvalidFields, slot.Day)
+			}
+			days[slot.Day] = true
+		case ModeRotation:
+			if slot.Day != "" {
+				return fmt.Errorf("%w: %s schedule has no days", ErrInvalidFields, s.Mode)
+			}
+			if workouts[slot.Workout] {
+				return fmt.Errorf("%w: workout %d occurs multiple times in rotation", ErrInvalidFields, slot.Workout)
+			}
+			workouts[slot.Workout] = true
+		default:
+			return fmt.Errorf("%w: unknown mode %q", ErrInvalidFields, s.Mode)
+		}
+	}
+
+	return nil
+}
+
+// Status is the state of the day
+type Status string
+
+const (
+	StatusDue  Status = "due"
+	StatusDone Status = "done"
+	StatusRest Status = "rest"
+)
+
+// Today contains the workout due on the local date of the user, Workout is 0
+// on rest days. Next is the workout that is due after today
+type Today struct {
+	Date    string `json:"date"`
+	Status  Status `json:"status"`
+	Workout int    `json:"workout,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Next    *Due   `json:"next,omitempty"`
+}
+
+// Due is a workout due on a date
+type Due struct {
+	Date    string `json:"date"`
+	Workout int    `json:"workout"`
+	Name    string `json:"name,omitempty"`
+}
+
+// Performed is a session of a workout started at a time
+type Performed struct {
+	Workout   int
+	StartedAt time.Time
+}
+
+// Today returns the workout due on the local date of now, recent contains
+// the most recent sessions of the user, the most recent first
+func (s Schedule) Today(now time.Time, recent []Performed) (Today, error) {
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return Today{}, fmt.Errorf("%w: unknown time zone %q", ErrInvalidFields, s.TimeZone)
+	}
+
+	local := now.In(loc)
+	today := Today{Date: local.Format(DateLayout)}
+
+	performedToday := func(workout int) bool {
+		for _, p := range recent {
+			if p.Workout == workout && p.StartedAt.In(loc).Format(DateLayout) == today.Date {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch s.Mode {
+	case ModeWeekly:
+		for _, slot := range s.Slots {
+			if d, _ := weekday(slot.Day); d == local.Weekday() {
+				today.Workout = slot.Workout
+			}
+		}
+
+		today.Status = StatusRest
+		if today.Workout != 0 {
+			today.Status = StatusDue
+			if performedToday(today.Workout) {
+				today.Status = StatusDone
+			}
+		}
+
+		// the first scheduled day after today, at most a week ahead
+		for i := 1; i <= 7 && today.Next == nil; i++ {
+			day := local.AddDate(0, 0, i)
+			for _, slot := range s.Slots {
+				if d, _ := weekday(slot.Day); d == day.Weekday() {
+					today.Next = &Due{Date: day.Format(DateLayout), Workout: slot.Workout}
+				}
+			}
+		}
+
+	case ModeRotation:
+		// the slots of deleted workouts are removed with the workout
+		if len(s.Slots) == 0 {
+			today.Status = StatusRest
+			break
+		}
+
+		// the slot following the most recent workout of the rotation
+		next := 0
+		for _, p := range recent {
+			if i := s.slot(p.Workout); i >= 0 {
+				next = (i + 1) % len(s.Slots)
+
+				if p.StartedAt.In(loc).Format(DateLayout) == today.Date {
+					today.Status = StatusDone
+					today.Workout = p.Workout
+				}
+				break
+			}
+		}
+
+		if today.Status == StatusDone {
+			tomorrow := local.AddDate(0, 0, 1).Format(DateLayout)
+			today.Next = &Due{Date: tomorrow, Workout: s.Slots[next].Workout}
+		} else {
+			today.Status = StatusDue
+			today.Workout = s.Slots[next].Workout
+			if len(s.Slots) > 1 {
+				tomorrow := local.AddDate(0, 0, 1).Format(DateLayout)
+				today.Next = &Due{Date: tomorrow, Workout: s.Slots[(next+1)%len(s.Slots)].Workout}
+			}
+		}
+
+	default:
+		return Today{}, fmt.Errorf("%w: unknown mode %q", ErrInvalidFields, s.Mode)
+	}
+
+	return today, nil
+}
+
+// slot returns the index of the first slot of workout or -1
+func (s Schedule) slot(workout int) int {
+	for i, slot := range s.Slots {
+		if slot.Workout == workout {
+			return i
+		}
+	}
+	return -1
+}
+
+// weekday parses the lowercase name of a day of the week
+func weekday(day string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.ToLower(d.String()) == day {
+			return d, true
+		}
+	}
+	return 0, false
+}
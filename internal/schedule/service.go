@@ -0,0 +1,116 @@
+package schedule
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/scrot/musclemem-api/internal/api"
+)
+
+// NewFetchHandler returns the schedule of a user
+// requires {username} path variable
+func NewFetchHandler(l *slog.Logger, schedules Retreiver) http.Handler {
+	l = l.With("handler", "FetchHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		s, err := schedules.ByOwner(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("fetched %s", s))
+
+		if err := api.WriteJSON(w, http.StatusOK, s); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewSetHandler replaces the schedule of a user
+// requires {username} path variable
+// requires json payload {"mode": MODE, "time_zone": TZ, "slots": [{"day": DAY, "workout": INDEX}]}
+func NewSetHandler(l *slog.Logger, schedules Storer) http.Handler {
+	l = l.With("handler", "SetHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		s, err := api.ReadJSON[Schedule](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		set, err := schedules.Set(username, s)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s set", set))
+
+		if err := api.WriteJSON(w, http.StatusOK, set); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewDeleteHandler deletes the schedule of a user
+// requires {username} path variable
+func NewDeleteHandler(l *slog.Logger, schedules Deleter) http.Handler {
+	l = l.With("handler", "DeleteHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		deleted, err := schedules.Delete(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s deleted", deleted))
+
+		if err := api.WriteJSON(w, http.StatusOK, deleted); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewTodayHandler returns the workout due today for a user
+// requires {username} path variable
+func NewTodayHandler(l *slog.Logger, schedules Retreiver) http.Handler {
+	l = l.With("handler", "TodayHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		today, err := schedules.Today(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched workout due today", "status", today.Status, "workout", today.Workout)
+
+		if err := api.WriteJSON(w, http.StatusOK, today); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
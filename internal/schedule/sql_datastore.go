@@ -0,0 +1,301 @@
+package schedule
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/storage"
+)
+
+// recentSessions is the number of sessions considered to determine
+// which workout is due today
+const recentSessions = 20
+
+type SQLScheduleStore struct {
+	*storage.SqlDatastore
+
+	// now returns the current time, replaceable for testing
+	now func() time.Time
+}
+
+func NewSQLScheduleStore(db *storage.SqlDatastore) *SQLScheduleStore {
+	return &SQLScheduleStore{db, time.Now}
+}
+
+func (ss *SQLScheduleStore) ByOwner(owner string) (Schedule, error) {
+	const (
+		scheduleStmt = `
+    SELECT owner, mode, time_zone
+    FROM schedules
+    WHERE owner = {{ . }}
+    `
+
+		slotsStmt = `
+    SELECT weekday, workout
+    FROM schedule_slots
+    WHERE owner = {{ . }}
+    ORDER BY slot_index
+    `
+	)
+
+	if owner == "" {
+		return Schedule{}, fmt.Errorf("ByOwner: %w", ErrInvalidFields)
+	}
+
+	q, args, err := ss.CompileStatement(scheduleStmt, owner)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("ByOwner: compile: %w", err)
+	}
+
+	var s Schedule
+	if err := ss.QueryRow(q, args...).Scan(&s.Owner, &s.Mode, &s.TimeZone); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Schedule{}, fmt.Errorf("ByOwner: %s: %w", owner, ErrNotFound)
+		}
+		return Schedule{}, fmt.Errorf("ByOwner: query: %w", err)
+	}
+
+	q, args, err = ss.CompileStatement(slotsStmt, owner)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("ByOwner: compile slots: %w", err)
+	}
+
+	rows, err := ss.Query(q, args...)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("ByOwner: query slots: %w", err)
+	}
+	defer rows.Close()
+
+	s.Slots = []Slot{}
+	for rows.Next() {
+		var slot Slot
+		if err := rows.Scan(&slot.Day, &slot.Workout); err != nil {
+			return Schedule{}, fmt.Errorf("ByOwner: scan slot: %w", err)
+		}
+		s.Slots = append(s.Slots, slot)
+	}
+
+	return s, nil
+}
+
+func (ss *SQLScheduleStore) Today(owner string) (Today, error) {
+	const stmt = `
+  SELECT COALESCE(workout, 0), started_at
+  FROM sessions
+  WHERE owner = {{ .Owner }}
+  ORDER BY session_index DESC
+  LIMIT {{ .Limit }}
+  `
+
+	s, err := ss.ByOwner(owner)
+	if err != nil {
+		return Today{}, fmt.Errorf("Today: %w", err)
+	}
+
+	data := struct {
+		Owner string
+		Limit int
+	}{owner, recentSessions}
+
+	q, args, err := ss.CompileStatement(stmt, data)
+	if err != nil {
+		return Today{}, fmt.Errorf("Today: compile: %w", err)
+	}
+
+	rows, err := ss.Query(q, args...)
+	if err != nil {
+		return Today{}, fmt.Errorf("Today: query: %w", err)
+	}
+	defer rows.Close()
+
+	var recent []Performed
+	for rows.Next() {
+		var p Performed
+		if err := rows.Scan(&p.Workout, &p.StartedAt); err != nil {
+			return Today{}, fmt.Errorf("Today: scan: %w", err)
+		}
+		recent = append(recent, p)
+	}
+
+	today, err := s.Today(ss.now(), recent)
+	if err != nil {
+		return Today{}, fmt.Errorf("Today: %w", err)
+	}
+
+	if today.Workout != 0 {
+		if today.Name, err = ss.workoutName(owner, today.Workout); err != nil {
+			return Today{}, fmt.Errorf("Today: %w", err)
+		}
+	}
+
+	if today.Next != nil {
+		if today.Next.Name, err = ss.workoutName(owner, today.Next.Workout); err != nil {
+			return Today{}, fmt.Errorf("Today: %w", err)
+		}
+	}
+
+	return today, nil
+}
+
+func (ss *SQLScheduleStore) Set(owner string, schedule Schedule) (Schedule, error) {
+	const (
+		scheduleStmt = `
+    INSERT INTO schedules (owner, mode, time_zone)
+    VALUES ({{ .Owner }}, {{ .Mode }}, {{ .TimeZone }})
+    ON CONFLICT (owner) DO UPDATE
+    SET mode = excluded.mode, time_zone = excluded.time_zone
+    `
+
+		clearStmt = `
+    DELETE FROM schedule_slots
+    WHERE owner = {{ . }}
+    `
+
+		slotStmt = `
+    INSERT INTO schedule_slots (owner, slot_index, weekday, workout)
+    VALUES ({{ .Owner }}, {{ .Index }}, {{ .Day }}, {{ .Workout }})
+    `
+	)
+
+	if owner == "" {
+		return Schedule{}, fmt.Errorf("Set: %w", ErrInvalidFields)
+	}
+
+	schedule.Owner = owner
+	if err := schedule.Validate(); err != nil {
+		return Schedule{}, fmt.Errorf("Set: %w", err)
+	}
+
+	for _, slot := range schedule.Slots {
+		if _, err := ss.workoutName(owner, slot.Workout); err != nil {
+			return Schedule{}, fmt.Errorf("Set: %w", err)
+		}
+	}
+
+	tx, err := ss.Begin()
+	if err != nil {
+		return Schedule{}, fmt.Errorf("Set: begin transaction: %w", err)
+	}
+
+	q, args, err := ss.CompileStatement(scheduleStmt, schedule)
+	if err != nil {
+		tx.Rollback()
+		return Schedule{}, fmt.Errorf("Set: compile: %w", err)
+	}
+
+	if _, err := tx.Exec(q, args...); err != nil {
+		tx.Rollback()
+		return Schedule{}, fmt.Errorf("Set: execute: %w", err)
+	}
+
+	q, args, err = ss.CompileStatement(clearStmt, owner)
+	if err != nil {
+		tx.Rollback()
+		return Schedule{}, fmt.Errorf("Set: compile clear: %w", err)
+	}
+
+	if _, err := tx.Exec(q, args...); err != nil {
+		tx.Rollback()
+		return Schedule{}, fmt.Errorf("Set: clear slots: %w", err)
+	}
+
+	for i, slot := range schedule.Slots {
+		data := struct {
+			Owner string
+			Index int
+			Slot
+		}{owner, i + 1, slot}
+
+		q, args, err := ss.CompileStatement(slotStmt, data)
+		if err != nil {
+			tx.Rollback()
+			return Schedule{}, fmt.Errorf("Set: compile slot: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			tx.Rollback()
+			return Schedule{}, fmt.Errorf("Set: insert slot: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Schedule{}, fmt.Errorf("Set: commit transaction: %w", err)
+	}
+
+	return schedule, nil
+}
+
+func (ss *SQLScheduleStore) Delete(owner string) (Schedule, error) {
+	const (
+		slotsStmt = `
+    DELETE FROM schedule_slots
+    WHERE owner = {{ . }}
+    `
+
+		scheduleStmt = `
+    DELETE FROM schedules
+    WHERE owner = {{ . }}
+    `
+	)
+
+	s, err := ss.ByOwner(owner)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("Delete: %w", err)
+	}
+
+	tx, err := ss.Begin()
+	if err != nil {
+		return Schedule{}, fmt.Errorf("Delete: begin transaction: %w", err)
+	}
+
+	for _, stmt := range []string{slotsStmt, scheduleStmt} {
+		q, args, err := ss.CompileStatement(stmt, owner)
+		if err != nil {
+			tx.Rollback()
+			return Schedule{}, fmt.Errorf("Delete: compile: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			tx.Rollback()
+			return Schedule{}, fmt.Errorf("Delete: execute: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Schedule{}, fmt.Errorf("Delete: commit transaction: %w", err)
+	}
+
+	return s, nil
+}
+
+// workoutName returns the name of a workout of owner,
+// it returns an ErrNotFound error if the workout doesn't exist
+func (ss *SQLScheduleStore) workoutName(owner string, workout int) (string, error) {
+	const stmt = `
+  SELECT name
+  FROM workouts
+  WHERE owner = {{ .Owner }} AND workout_index = {{ .Workout }}
+  `
+
+	data := struct {
+		Owner   string
+		Workout int
+	}{owner, workout}
+
+	q, args, err := ss.CompileStatement(stmt, data)
+	if err != nil {
+		return "", fmt.Errorf("workoutName: compile: %w", err)
+	}
+
+	var name string
+	if err := ss.QueryRow(q, args...).Scan(&name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("workout %s/%d: %w", owner, workout, ErrNotFound)
+		}
+		return "", fmt.Errorf("workoutName: query: %w", err)
+	}
+
+	return name, nil
+}
@@ -0,0 +1,177 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/session/sessiontest"
+	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/user"
+	"github.com/scrot/musclemem-api/internal/workout"
+)
+
+func TestSetSchedule(t *testing.T) {
+	schedules, _, flush := mockScheduleStore(t)
+	defer flush()
+
+	cs := []struct {
+		name    string
+		input   Schedule
+		wantErr error
+	}{
+		{"weekly", Schedule{Mode: ModeWeekly, Slots: []Slot{{"monday", 1}, {"thursday", 2}}}, nil},
+		{"rotation", Schedule{Mode: ModeRotation, TimeZone: "Europe/Amsterdam", Slots: []Slot{{"", 1}, {"", 2}}}, nil},
+		{"unknownDay", Schedule{Mode: ModeWeekly, Slots: []Slot{{"someday", 1}}}, ErrInvalidFields},
+		{"duplicateDay", Schedule{Mode: ModeWeekly, Slots: []Slot{{"monday", 1}, {"monday", 2}}}, ErrInvalidFields},
+		{"rotationWithDay", Schedule{Mode: ModeRotation, Slots: []Slot{{"monday", 1}}}, ErrInvalidFields},
+		{"unknownTimeZone", Schedule{Mode: ModeRotation, TimeZone: "Mars/Olympus", Slots: []Slot{{"", 1}}}, ErrInvalidFields},
+		{"unknownWorkout", Schedule{Mode: ModeRotation, Slots: []Slot{{"", 9}}}, ErrNotFound},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := schedules.Set("user", c.input)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("want error %v but got %v", c.wantErr, err)
+			}
+		})
+	}
+
+	got, err := schedules.ByOwner("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Mode != ModeRotation || got.TimeZone != "Europe/Amsterdam" || len(got.Slots) != 2 {
+		t.Errorf("want the rotation schedule but got %s", got)
+	}
+
+	if _, err := schedules.Delete("user"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := schedules.ByOwner("user"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v but got %v", ErrNotFound, err)
+	}
+}
+
+func TestToday(t *testing.T) {
+	schedules, store, flush := mockScheduleStore(t)
+	defer flush()
+
+	// monday evening in UTC is tuesday morning in Auckland
+	monday := time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC)
+	schedules.(*SQLScheduleStore).now = func() time.Time { return monday }
+
+	weekly := []Slot{{"monday", 1}, {"wednesday", 2}}
+	rotation := []Slot{{"", 1}, {"", 2}, {"", 3}}
+
+	cs := []struct {
+		name     string
+		schedule Schedule
+		sessions []Performed
+		want     Today
+	}{
+		{
+			"weeklyDue",
+			Schedule{Mode: ModeWeekly, Slots: weekly},
+			nil,
+			Today{Date: "2026-10-19", Status: StatusDue, Workout: 1, Name: "push", Next: &Due{"2026-10-21", 2, "pull"}},
+		},
+		{
+			"weeklyRestInTimeZone",
+			Schedule{Mode: ModeWeekly, TimeZone: "Pacific/Auckland", Slots: weekly},
+			nil,
+			Today{Date: "2026-10-20", Status: StatusRest, Next: &Due{"2026-10-21", 2, "pull"}},
+		},
+		{
+			"weeklyDone",
+			Schedule{Mode: ModeWeekly, Slots: weekly},
+			[]Performed{{1, monday.Add(-time.Hour)}},
+			Today{Date: "2026-10-19", Status: StatusDone, Workout: 1, Name: "push", Next: &Due{"2026-10-21", 2, "pull"}},
+		},
+		{
+			"rotationAfterYesterday",
+			Schedule{Mode: ModeRotation, Slots: rotation},
+			[]Performed{{1, monday.AddDate(0, 0, -1)}},
+			Today{Date: "2026-10-19", Status: StatusDue, Workout: 2, Name: "pull", Next: &Due{"2026-10-20", 3, "legs"}},
+		},
+		{
+			"rotationDoneToday",
+			Schedule{Mode: ModeRotation, Slots: rotation},
+			[]Performed{{2, monday.Add(-time.Hour)}},
+			Today{Date: "2026-10-19", Status: StatusDone, Workout: 2, Name: "pull", Next: &Due{"2026-10-20", 3, "legs"}},
+		},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := store.Exec("DELETE FROM sessions"); err != nil {
+				t.Fatal(err)
+			}
+
+			for i, p := range c.sessions {
+				logSession(t, store, i+1, p)
+			}
+
+			if _, err := schedules.Set("user", c.schedule); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := schedules.Today("user")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.Date != c.want.Date || got.Status != c.want.Status || got.Workout != c.want.Workout || got.Name != c.want.Name {
+				t.Errorf("want %+v but got %+v", c.want, got)
+			}
+
+			if got.Next == nil || *got.Next != *c.want.Next {
+				t.Errorf("want next %+v but got %+v", c.want.Next, got.Next)
+			}
+		})
+	}
+}
+
+// logSession logs a session of a workout started at the given time
+func logSession(t *testing.T, store *storage.SqlDatastore, session int, p Performed) {
+	t.Helper()
+
+	sessiontest.Log(t, store, sessiontest.Session{Owner: "user", Index: session, Workout: p.Workout, StartedAt: p.StartedAt})
+}
+
+func mockScheduleStore(t *testing.T) (ScheduleStore, *storage.SqlDatastore, func()) {
+	t.Helper()
+
+	config := storage.DatastoreConfig{
+		DatabaseURL:   "file://test.db?cache=shared&mode=memory",
+		MigrationPath: "migrations",
+		Overwrite:     false,
+	}
+
+	store, err := storage.NewSqlDatastore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := user.NewSQLUserStore(store).New("user", "test@gmail.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	workouts := workout.NewSQLWorkoutStore(store)
+	for _, name := range []string{"push", "pull", "legs"} {
+		if _, err := workouts.New("user", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	flush := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal()
+		}
+	}
+
+	return NewSQLScheduleStore(store), store, flush
+}
@@ -9,7 +9,20 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/scrot/musclemem-api/internal/body"
+	"github.com/scrot/musclemem-api/internal/catalog"
+	"github.com/scrot/musclemem-api/internal/challenge"
+	"github.com/scrot/musclemem-api/internal/coach"
+	"github.com/scrot/musclemem-api/internal/comment"
+	"github.com/scrot/musclemem-api/internal/equipment"
 	"github.com/scrot/musclemem-api/internal/exercise"
+	"github.com/scrot/musclemem-api/internal/program"
+	"github.com/scrot/musclemem-api/internal/record"
+	"github.com/scrot/musclemem-api/internal/schedule"
+	"github.com/scrot/musclemem-api/internal/session"
+	"github.com/scrot/musclemem-api/internal/share"
+	"github.com/scrot/musclemem-api/internal/social"
+	"github.com/scrot/musclemem-api/internal/stats"
 	"github.com/scrot/musclemem-api/internal/user"
 	"github.com/scrot/musclemem-api/internal/workout"
 )
@@ -30,9 +43,22 @@ func NewServer(
 	users user.UserStore,
 	workouts workout.WorkoutStore,
 	exercises exercise.ExerciseStore,
+	entries catalog.CatalogStore,
+	sessions session.SessionStore,
+	records record.RecordStore,
+	statistics stats.StatsStore,
+	schedules schedule.ScheduleStore,
+	programs program.ProgramStore,
+	inventory equipment.EquipmentStore,
+	bodies body.BodyStore,
+	shares share.ShareStore,
+	coaches coach.CoachStore,
+	comments comment.CommentStore,
+	socials social.SocialStore,
+	challenges challenge.ChallengeStore,
 ) *Server {
 	mux := http.NewServeMux()
-	RegisterEndpoints(mux, logger, users, workouts, exercises)
+	RegisterEndpoints(mux, logger, users, workouts, exercises, entries, sessions, records, statistics, schedules, programs, inventory, bodies, shares, coaches, comments, socials, challenges)
 	return &Server{ServerConfig: config, logger: logger, mux: mux}
 }
 
@@ -0,0 +1,71 @@
+package session
+
+import (
+	"errors"
+
+	"github.com/scrot/musclemem-api/internal/strength"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidFields = errors.New("contains invalid fields")
+	ErrFinished      = errors.New("session already finished")
+)
+
+// SessionStore represents the training session repository
+type SessionStore interface {
+	Retreiver
+	Starter
+	Logger
+	Finisher
+	Deleter
+}
+
+// Retreiver implementations allow for sessions to be queried
+type Retreiver interface {
+	// ByID returns the session including its exercises and logged sets
+	ByID(owner string, session int) (Session, error)
+
+	// ByOwner returns all sessions of owner without exercises,
+	// the most recent session first
+	ByOwner(owner string) ([]Session, error)
+
+	// History returns a page of the sets logged for an exercise
+	// grouped by session, the most recent session first
+	History(owner string, query HistoryQuery) (History, error)
+}
+
+// Starter implementations allow for sessions to be started
+type Starter interface {
+	// Start starts a new session copying the name and
+	// exercises of the workout, the workout must exist.
+	// The session is dated in the time zone of the owner's schedule
+	Start(owner string, workout int, notes string) (Session, error)
+}
+
+// Logger implementations allow for sets to be logged
+type Logger interface {
+	// LogSet appends a set to a session exercise, the set must be valid
+	// for the kind of exercise and the session may not be finished
+	LogSet(owner string, session int, exercise int, set Set) (Set, error)
+
+	// LogWarmup appends the warm-up sets leading up to the target weight
+	// of a weighted session exercise as completed warm-up sets, rounded
+	// to the equipment of owner unless the config sets a bar or plates
+	LogWarmup(owner string, session int, exercise int, config strength.WarmupConfig) ([]Set, error)
+}
+
+// Finisher implementations allow for sessions to be finished
+type Finisher interface {
+	// Finish marks the session as finished, notes are appended
+	// to the session notes. It returns an ErrFinished error
+	// if the session was already finished
+	Finish(owner string, session int, notes string) (Session, error)
+}
+
+// Deleter implementations allow for sessions to be deleted
+type Deleter interface {
+	// Delete deletes the session including its exercises and sets,
+	// the records it set are recomputed from the remaining sessions
+	Delete(owner string, session int) (Session, error)
+}
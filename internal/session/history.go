@@ -0,0 +1,182 @@
+package session
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/body"
+	"github.com/scrot/musclemem-api/internal/exercise"
+)
+
+const (
+	DefaultHistoryLimit = 10
+	MaxHistoryLimit     = 100
+)
+
+// HistoryQuery selects the logged sets of an exercise, Exercise is either a
+// catalog reference or an exercise name. From and To are optional inclusive
+// dates and Cursor continues from a previous page
+type HistoryQuery struct {
+	Exercise  string
+	From      string
+	To        string
+	Cursor    string
+	Limit     int
+	Aggregate bool
+}
+
+// Validate checks the query and fills in the default limit
+func (q *HistoryQuery) Validate() error {
+	if q.Exercise == "" {
+		return fmt.Errorf("%w: missing exercise", ErrInvalidFields)
+	}
+
+	for _, d := range []string{q.From, q.To} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse(DateLayout, d); err != nil {
+			return fmt.Errorf("%w: date %q, expected %s", ErrInvalidFields, d, DateLayout)
+		}
+	}
+
+	if q.From != "" && q.To != "" && q.From > q.To {
+		return fmt.Errorf("%w: from %s after to %s", ErrInvalidFields, q.From, q.To)
+	}
+
+	if _, err := parseCursor(q.Cursor); err != nil {
+		return err
+	}
+
+	if q.Limit < 0 {
+		return fmt.Errorf("%w: negative limit", ErrInvalidFields)
+	}
+
+	if q.Limit == 0 {
+		q.Limit = DefaultHistoryLimit
+	}
+	q.Limit = min(q.Limit, MaxHistoryLimit)
+
+	return nil
+}
+
+// History is a page of sessions the exercise was performed in, the most
+// recent first. Cursor is set when there are more sessions to fetch
+type History struct {
+	Entries []HistoryEntry `json:"entries"`
+	Cursor  string         `json:"cursor,omitempty"`
+}
+
+// HistoryEntry contains the sets of the exercise logged during a session,
+// Best and Volume are only set when aggregation was requested
+type HistoryEntry struct {
+	Session int           `json:"session"`
+	Date    string        `json:"date"`
+	Name    string        `json:"name"`
+	Kind    exercise.Kind `json:"kind"`
+	Sets    []Set         `json:"sets"`
+	Best    *Set          `json:"best,omitempty"`
+	Volume  float64       `json:"volume,omitempty"`
+}
+
+// cursor returns the cursor of the history after the entry
+func (e HistoryEntry) cursor() cursor {
+	return cursor{e.Date, e.Session}
+}
+
+// aggregate sets the best completed set and the total volume of the entry,
+// bodyWeight is the body weight of the lifter on the date of the entry
+func (e *HistoryEntry) aggregate(bodyWeight float64) {
+	for _, s := range e.Sets {
+		if !s.Completed {
+			continue
+		}
+
+		if e.Best == nil || better(e.Kind, s, *e.Best) {
+			best := s
+			e.Best = &best
+		}
+	}
+	e.Volume = Volume(e.Kind, e.Sets, bodyWeight)
+}
+
+// cursor is the position in the history after which the next page starts,
+// sessions are ordered by the date performed, most recent first
+type cursor struct {
+	Date    string `json:"d"`
+	Session int    `json:"s"`
+}
+
+// String encodes the cursor as an opaque token
+func (c cursor) String() string {
+	bs, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(bs)
+}
+
+// parseCursor decodes a cursor token, an empty
+// token is the cursor of the start of the history
+func parseCursor(token string) (cursor, error) {
+	if token == "" {
+		return cursor{}, nil
+	}
+
+	bs, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor{}, fmt.Errorf("%w: cursor %q", ErrInvalidFields, token)
+	}
+
+	var c cursor
+	if err := json.Unmarshal(bs, &c); err != nil || c.Session <= 0 {
+		return cursor{}, fmt.Errorf("%w: cursor %q", ErrInvalidFields, token)
+	}
+
+	if _, err := time.Parse(DateLayout, c.Date); err != nil {
+		return cursor{}, fmt.Errorf("%w: cursor %q", ErrInvalidFields, token)
+	}
+
+	return c, nil
+}
+
+// start reports whether the cursor is the start of the history
+func (c cursor) start() bool {
+	return c.Session == 0
+}
+
+// better reports whether set a beats set b for an exercise of kind
+func better(kind exercise.Kind, a, b Set) bool {
+	switch kind {
+	case exercise.KindDuration:
+		return a.DurationSeconds > b.DurationSeconds
+	case exercise.KindDistance:
+		return a.DistanceMeters > b.DistanceMeters
+	case exercise.KindDistanceTime:
+		if a.DistanceMeters != b.DistanceMeters {
+			return a.DistanceMeters > b.DistanceMeters
+		}
+		return a.DurationSeconds < b.DurationSeconds
+	case exercise.KindAssisted:
+		// less assistance is better
+		if a.Weight != b.Weight {
+			return a.Weight < b.Weight
+		}
+		return a.Repetitions > b.Repetitions
+	default:
+		if a.Weight != b.Weight {
+			return a.Weight > b.Weight
+		}
+		return a.Repetitions > b.Repetitions
+	}
+}
+
+// Volume returns the total weight moved, the sum of the load times the
+// repetitions of each set of an exercise of kind, bodyWeight is the
+// body weight of the lifter used as load of bodyweight exercises
+func Volume(kind exercise.Kind, sets []Set, bodyWeight float64) float64 {
+	var v float64
+	for _, s := range sets {
+		v += body.Load(kind, s.Weight, bodyWeight) * float64(s.Repetitions)
+	}
+	return v
+}
@@ -0,0 +1,176 @@
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/exercise"
+	"github.com/scrot/musclemem-api/internal/strength"
+)
+
+// DateLayout is the layout of the calendar date a session was performed on
+const DateLayout = time.DateOnly
+
+// Session is a performed workout, it contains the exercises
+// of the workout it was started from and the sets that were logged.
+// Workout follows the workout when it is renumbered and is 0 once
+// the workout is deleted
+type Session struct {
+	Owner      string     `json:"owner"`
+	Index      int        `json:"index"`
+	Workout    int        `json:"workout,omitempty"`
+	Name       string     `json:"name"`
+	Date       string     `json:"date"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Notes      string     `json:"notes,omitempty"`
+	Exercises  []Exercise `json:"exercises,omitempty"`
+}
+
+func (s Session) String() string {
+	return fmt.Sprintf("session %s: %s on %s", s.Ref(), s.Name, s.Date)
+}
+
+// Ref returns the unique key that references the Session
+func (s Session) Ref() SessionRef {
+	return SessionRef{s.Owner, s.Index}
+}
+
+// Finished reports whether the session has been finished
+func (s Session) Finished() bool {
+	return s.FinishedAt != nil
+}
+
+// Performed returns the performance of the workout exercises during the
+// session leaving out exercises that are no longer part of the workout, an
+// exercise is completed when all its sets reached the target repetitions
+func (s Session) Performed() []exercise.Performed {
+	performed := []exercise.Performed{}
+	if s.Workout == 0 {
+		return performed
+	}
+
+	for _, x := range s.Exercises {
+		if x.Source == 0 {
+			continue
+		}
+
+		var sets, completed int
+		for _, set := range x.Sets {
+			if set.Warmup {
+				continue
+			}
+
+			sets++
+			if set.Completed && set.Repetitions >= x.Target.Repetitions {
+				completed++
+			}
+		}
+
+		performance := exercise.Performance{Sets: sets, Completed: sets > 0 && completed == sets}
+		performed = append(performed, exercise.Performed{Workout: s.Workout, Exercise: x.Source, Performance: performance})
+	}
+
+	return performed
+}
+
+// SessionRef represents the unique key that references a Session
+type SessionRef struct {
+	Username     string
+	SessionIndex int
+}
+
+func (sr SessionRef) String() string {
+	return fmt.Sprintf("%s/%d", sr.Username, sr.SessionIndex)
+}
+
+// Exercise is an exercise performed during a session, Source is the index
+// of the workout exercise it was copied from, 0 once that exercise is
+// deleted, and Target the planned load
+type Exercise struct {
+	Index   int           `json:"index"`
+	Source  int           `json:"source,omitempty"`
+	Name    string        `json:"name"`
+	Catalog string        `json:"catalog,omitempty"`
+	Kind    exercise.Kind `json:"kind"`
+	Target  Load          `json:"target"`
+	Sets    []Set         `json:"sets"`
+}
+
+// Warmup returns the completed warm-up sets leading up to the
+// target weight, only weighted exercises can be warmed up
+func (x Exercise) Warmup(c strength.WarmupConfig) ([]Set, error) {
+	if x.Kind != exercise.KindWeighted {
+		return []Set{}, fmt.Errorf("%w: warm-up requires a %s exercise", ErrInvalidFields, exercise.KindWeighted)
+	}
+
+	warmup, err := strength.Warmup(x.Target.Weight, c)
+	if err != nil {
+		return []Set{}, fmt.Errorf("%w: %w", ErrInvalidFields, err)
+	}
+
+	sets := []Set{}
+	for _, w := range warmup {
+		sets = append(sets, Set{Load: Load{Weight: w.Weight, Repetitions: w.Repetitions}, Completed: true, Warmup: true})
+	}
+
+	return sets, nil
+}
+
+// Load contains the measurements of a set, which
+// fields are used depends on the kind of the exercise
+type Load struct {
+	Weight          float64 `json:"weight"`
+	Repetitions     int     `json:"repetitions,omitempty"`
+	DurationSeconds int     `json:"duration_seconds,omitempty"`
+	DistanceMeters  float64 `json:"distance_meters,omitempty"`
+}
+
+// Set is a single logged set of an exercise with the actual load,
+// the rate of perceived exertion and whether it was completed as planned.
+// Warm-up sets are left out of history, records, statistics and progression
+type Set struct {
+	Index int `json:"index"`
+	Load
+	RPE       float64   `json:"rpe,omitempty"`
+	Completed bool      `json:"completed"`
+	Warmup    bool      `json:"warmup,omitempty"`
+	LoggedAt  time.Time `json:"logged_at"`
+	E1RM      float64   `json:"e1rm,omitempty"`
+}
+
+// annotate sets the estimated one-rep max of weighted sets
+func (s *Set) annotate(kind exercise.Kind) {
+	s.E1RM = 0
+	if kind == exercise.KindWeighted {
+		s.E1RM = strength.OneRepMax(s.Weight, s.Repetitions)
+	}
+}
+
+// Validate checks if the set is valid for an exercise of kind,
+// sets that weren't completed may have no repetitions
+func (s Set) Validate(kind exercise.Kind) error {
+	x := exercise.Exercise{
+		Name:            "set",
+		Kind:            kind,
+		Weight:          s.Weight,
+		Repetitions:     s.Repetitions,
+		DurationSeconds: s.DurationSeconds,
+		DistanceMeters:  s.DistanceMeters,
+	}
+
+	// a set that wasn't completed may be failed before the first repetition
+	if !s.Completed && s.Repetitions == 0 && kind.UsesRepetitions() {
+		x.Repetitions = 1
+	}
+
+	if err := x.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidFields, err)
+	}
+
+	if s.RPE != 0 && (s.RPE < 1 || s.RPE > 10) {
+		return fmt.Errorf("%w: rpe %.1f, expected 1 to 10", ErrInvalidFields, s.RPE)
+	}
+
+	return nil
+}
@@ -0,0 +1,364 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/scrot/musclemem-api/internal/api"
+	"github.com/scrot/musclemem-api/internal/exercise"
+	"github.com/scrot/musclemem-api/internal/record"
+	"github.com/scrot/musclemem-api/internal/strength"
+)
+
+// NewFetchAllHandler returns all sessions of a user, most recent first
+// requires {username} path variable
+func NewFetchAllHandler(l *slog.Logger, sessions Retreiver) http.Handler {
+	l = l.With("handler", "FetchAllHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		ss, err := sessions.ByOwner(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched user sessions", "count", len(ss))
+
+		if err := api.WriteJSON(w, http.StatusOK, ss); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewFetchHandler returns a session including exercises and logged sets
+// requires {username} and {session} path variables
+func NewFetchHandler(l *slog.Logger, sessions Retreiver) http.Handler {
+	l = l.With("handler", "FetchHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			session  = r.PathValue("session")
+		)
+
+		l := l.With("user", username, "session", session)
+
+		si, err := strconv.Atoi(session)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		s, err := sessions.ByID(username, si)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("fetched %s", s))
+
+		if err := api.WriteJSON(w, http.StatusOK, s); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewStartHandler starts a new session from a workout
+// requires {username} path variable
+// requires json payload {"workout": INDEX, "notes": NOTES}
+func NewStartHandler(l *slog.Logger, sessions Starter) http.Handler {
+	l = l.With("handler", "StartHandler")
+
+	type Request struct {
+		Workout int    `json:"workout"`
+		Notes   string `json:"notes"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		req, err := api.ReadJSON[Request](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		started, err := sessions.Start(username, req.Workout, req.Notes)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s started", started), "workout", req.Workout)
+
+		if err := api.WriteJSON(w, http.StatusOK, started); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewLogSetHandler logs a set for an exercise of an unfinished session
+// requires {username}, {session} and {exercise} path variables
+func NewLogSetHandler(l *slog.Logger, sessions Logger) http.Handler {
+	l = l.With("handler", "LogSetHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			session  = r.PathValue("session")
+			exercise = r.PathValue("exercise")
+		)
+
+		l := l.With("user", username, "session", session, "exercise", exercise)
+
+		si, err := strconv.Atoi(session)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		ei, err := strconv.Atoi(exercise)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		set, err := api.ReadJSON[Set](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		logged, err := sessions.LogSet(username, si, ei, set)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("set logged", "set", logged.Index)
+
+		if err := api.WriteJSON(w, http.StatusOK, logged); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewWarmupHandler logs the warm-up sets leading up to the target weight of
+// a weighted exercise of an unfinished session
+// requires {username}, {session} and {exercise} path variables
+// optional json payload {"scheme": SCHEME, "bar": WEIGHT, "plates": [WEIGHT], "step": WEIGHT}
+func NewWarmupHandler(l *slog.Logger, sessions Logger) http.Handler {
+	l = l.With("handler", "WarmupHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			session  = r.PathValue("session")
+			exercise = r.PathValue("exercise")
+		)
+
+		l := l.With("user", username, "session", session, "exercise", exercise)
+
+		si, err := strconv.Atoi(session)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		ei, err := strconv.Atoi(exercise)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		var config strength.WarmupConfig
+		if r.ContentLength != 0 {
+			if config, err = api.ReadJSON[strength.WarmupConfig](r); err != nil {
+				api.WriteInternalError(l, w, err, "")
+				return
+			}
+		}
+
+		logged, err := sessions.LogWarmup(username, si, ei, config)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("warm-up logged", "sets", len(logged))
+
+		if err := api.WriteJSON(w, http.StatusOK, logged); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewFinishHandler finishes a session, detects the personal records that
+// were beaten and progresses the planned load of the performed exercises.
+// Finishing a finished session detects and progresses again, so a retry
+// applies what failed before and reports only what wasn't applied yet
+// requires {username} and {session} path variables
+// optional json payload {"notes": NOTES}
+func NewFinishHandler(l *slog.Logger, sessions SessionStore, records record.Detector, exercises exercise.Progressor) http.Handler {
+	l = l.With("handler", "FinishHandler")
+
+	type Request struct {
+		Notes string `json:"notes"`
+	}
+
+	type Response struct {
+		Session
+		Records      []record.Record       `json:"records"`
+		Progressions []exercise.Adjustment `json:"progressions"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			session  = r.PathValue("session")
+		)
+
+		l := l.With("user", username, "session", session)
+
+		si, err := strconv.Atoi(session)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		var req Request
+		if r.ContentLength != 0 {
+			if req, err = api.ReadJSON[Request](r); err != nil {
+				api.WriteInternalError(l, w, err, "")
+				return
+			}
+		}
+
+		finished, err := sessions.Finish(username, si, req.Notes)
+		if errors.Is(err, ErrFinished) {
+			finished, err = sessions.ByID(username, si)
+		}
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		rs, err := records.Detect(username, si)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		as, err := exercises.Progress(username, si, finished.Performed())
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s finished", finished), "records", len(rs), "progressions", len(as))
+
+		if err := api.WriteJSON(w, http.StatusOK, Response{finished, rs, as}); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewDeleteHandler deletes a session including its logged sets
+// requires {username} and {session} path variables
+func NewDeleteHandler(l *slog.Logger, sessions Deleter) http.Handler {
+	l = l.With("handler", "DeleteHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			session  = r.PathValue("session")
+		)
+
+		l := l.With("user", username, "session", session)
+
+		si, err := strconv.Atoi(session)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		deleted, err := sessions.Delete(username, si)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("session deleted", "key", deleted.Ref())
+
+		if err := api.WriteJSON(w, http.StatusOK, deleted); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewHistoryHandler returns the sets logged for an exercise grouped by session
+// requires {username} path variable and exercise query parameter,
+// optional from, to, cursor, limit and aggregate query parameters
+func NewHistoryHandler(l *slog.Logger, sessions Retreiver) http.Handler {
+	l = l.With("handler", "HistoryHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			params   = r.URL.Query()
+		)
+
+		query := HistoryQuery{
+			Exercise: params.Get("exercise"),
+			From:     params.Get("from"),
+			To:       params.Get("to"),
+			Cursor:   params.Get("cursor"),
+		}
+
+		l := l.With("user", username, "exercise", query.Exercise)
+
+		var err error
+		if v := params.Get("limit"); v != "" {
+			if query.Limit, err = strconv.Atoi(v); err != nil {
+				api.WriteInternalError(l, w, err, "invalid limit")
+				return
+			}
+		}
+
+		if v := params.Get("aggregate"); v != "" {
+			if query.Aggregate, err = strconv.ParseBool(v); err != nil {
+				api.WriteInternalError(l, w, err, "invalid aggregate")
+				return
+			}
+		}
+
+		h, err := sessions.History(username, query)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched exercise history", "count", len(h.Entries))
+
+		if err := api.WriteJSON(w, http.StatusOK, h); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
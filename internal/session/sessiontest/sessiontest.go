@@ -0,0 +1,142 @@
+// Package sessiontest provides a fixture for tests that need performed
+// sessions on a given date. It writes the session tables directly so it can
+// be used by the packages the session package itself depends on
+package sessiontest
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/storage"
+)
+
+// Session is a performed session, Date defaults to the date of StartedAt
+// and StartedAt to noon of Date. Workout is the workout of the owner the
+// session was started from, 0 for a session without workout
+type Session struct {
+	Owner      string
+	Index      int
+	Workout    int
+	Name       string
+	Date       string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+	Exercises  []Exercise
+}
+
+// Exercise is an exercise performed during a session, Kind defaults to weighted
+type Exercise struct {
+	Name    string
+	Catalog string
+	Kind    string
+	Sets    []Set
+}
+
+// Set is a set logged for an exercise
+type Set struct {
+	Weight      float64
+	Repetitions int
+	RPE         float64
+	Completed   bool
+	Warmup      bool
+}
+
+// Log inserts the session with its exercises and sets
+func Log(t testing.TB, store *storage.SqlDatastore, s Session) {
+	t.Helper()
+
+	const (
+		sessionStmt = `
+    INSERT INTO sessions (owner, session_index, workout_owner, workout, name, performed_on, started_at, finished_at)
+    VALUES (
+      {{ .Owner }}, {{ .Index }}, {{ .WorkoutOwner }}, {{ .WorkoutIndex }}, {{ .Name }}, {{ .Date }},
+      {{ .StartedAt }}, {{ .FinishedAt }}
+    )
+    `
+
+		exerciseStmt = `
+    INSERT INTO session_exercises (owner, session, exercise_index, name, catalog, kind)
+    VALUES ({{ .Owner }}, {{ .Session }}, {{ .Index }}, {{ .Name }}, {{ .Catalog }}, {{ .Kind }})
+    `
+
+		setStmt = `
+    INSERT INTO session_sets (
+      owner, session, exercise, set_index, weight, repetitions, rpe, completed, warmup, logged_at
+    )
+    VALUES (
+      {{ .Owner }}, {{ .Session }}, {{ .Exercise }}, {{ .Index }}, {{ .Weight }}, {{ .Repetitions }},
+      {{ .RPE }}, {{ .Completed }}, {{ .Warmup }}, {{ .LoggedAt }}
+    )
+    `
+	)
+
+	exec := func(stmt string, data any) {
+		t.Helper()
+
+		q, args, err := store.CompileStatement(stmt, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := store.Exec(q, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if s.Name == "" {
+		s.Name = "workout"
+	}
+
+	if s.Date == "" {
+		s.Date = s.StartedAt.Format(time.DateOnly)
+	}
+
+	if s.StartedAt.IsZero() {
+		d, err := time.Parse(time.DateOnly, s.Date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.StartedAt = d.Add(12 * time.Hour)
+	}
+
+	var (
+		owner   sql.NullString
+		workout sql.NullInt64
+	)
+
+	if s.Workout > 0 {
+		owner = sql.NullString{String: s.Owner, Valid: true}
+		workout = sql.NullInt64{Int64: int64(s.Workout), Valid: true}
+	}
+
+	exec(sessionStmt, struct {
+		Session
+		WorkoutOwner sql.NullString
+		WorkoutIndex sql.NullInt64
+	}{s, owner, workout})
+
+	for i, x := range s.Exercises {
+		if x.Kind == "" {
+			x.Kind = "weighted"
+		}
+
+		exec(exerciseStmt, struct {
+			Exercise
+			Owner   string
+			Session int
+			Index   int
+		}{x, s.Owner, s.Index, i + 1})
+
+		for j, set := range x.Sets {
+			exec(setStmt, struct {
+				Set
+				Owner    string
+				Session  int
+				Exercise int
+				Index    int
+				LoggedAt time.Time
+			}{set, s.Owner, s.Index, i + 1, j + 1, s.StartedAt})
+		}
+	}
+}
@@ -0,0 +1,824 @@
+package session
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/body"
+	"github.com/scrot/musclemem-api/internal/equipment"
+	"github.com/scrot/musclemem-api/internal/exercise"
+	"github.com/scrot/musclemem-api/internal/record"
+	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/strength"
+)
+
+type SQLSessionStore struct {
+	*storage.SqlDatastore
+
+	// rounder rounds warmup weights to the equipment of the owner
+	rounder equipment.Rounder
+
+	// bodies returns the body weight used to aggregate bodyweight exercises
+	bodies body.Retreiver
+
+	// records recomputes the records beaten by a deleted session
+	records record.Rebuilder
+
+	// now returns the current time, replaceable for testing
+	now func() time.Time
+}
+
+func NewSQLSessionStore(db *storage.SqlDatastore, rounder equipment.Rounder, bodies body.Retreiver, records record.Rebuilder) *SQLSessionStore {
+	return &SQLSessionStore{db, rounder, bodies, records, time.Now}
+}
+
+// timestamp returns the current time in UTC with second precision
+func (ss *SQLSessionStore) timestamp() time.Time {
+	return ss.now().UTC().Truncate(time.Second)
+}
+
+// location returns the time zone of the schedule of owner, sessions are
+// performed on the local date of the owner and UTC without a schedule
+func (ss *SQLSessionStore) location(owner string) (*time.Location, error) {
+	const stmt = `
+  SELECT time_zone
+  FROM schedules
+  WHERE owner = {{ . }}
+  `
+
+	q, args, err := ss.CompileStatement(stmt, owner)
+	if err != nil {
+		return nil, fmt.Errorf("location: compile: %w", err)
+	}
+
+	var tz string
+	if err := ss.QueryRow(q, args...).Scan(&tz); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.UTC, nil
+		}
+		return nil, fmt.Errorf("location: query: %w", err)
+	}
+
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("location: time zone %q: %w", tz, err)
+	}
+
+	return loc, nil
+}
+
+func (ss *SQLSessionStore) ByID(owner string, session int) (Session, error) {
+	const (
+		sessionStmt = `
+    SELECT owner, session_index, COALESCE(workout, 0), name, performed_on, started_at, finished_at, notes
+    FROM sessions
+    WHERE owner = {{ .Owner }} AND session_index = {{ .Session }}
+    `
+
+		exercisesStmt = `
+    SELECT exercise_index, COALESCE(workout_exercise, 0), name, catalog, kind, target_weight,
+      target_repetitions, target_duration_seconds, target_distance_meters
+    FROM session_exercises
+    WHERE owner = {{ .Owner }} AND session = {{ .Session }}
+    ORDER BY exercise_index
+    `
+
+		setsStmt = `
+    SELECT exercise, set_index, weight, repetitions, duration_seconds,
+      distance_meters, rpe, completed, warmup, logged_at
+    FROM session_sets
+    WHERE owner = {{ .Owner }} AND session = {{ .Session }}
+    ORDER BY exercise, set_index
+    `
+	)
+
+	if owner == "" || session <= 0 {
+		return Session{}, fmt.Errorf("ByID: %w", ErrInvalidFields)
+	}
+
+	data := struct {
+		Owner   string
+		Session int
+	}{owner, session}
+
+	q, args, err := ss.CompileStatement(sessionStmt, data)
+	if err != nil {
+		return Session{}, fmt.Errorf("ByID: compile: %w", err)
+	}
+
+	s, err := scanSession(ss.QueryRow(q, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Session{}, fmt.Errorf("ByID: %s/%d: %w", owner, session, ErrNotFound)
+		}
+		return Session{}, fmt.Errorf("ByID: query: %w", err)
+	}
+
+	q, args, err = ss.CompileStatement(exercisesStmt, data)
+	if err != nil {
+		return Session{}, fmt.Errorf("ByID: compile exercises: %w", err)
+	}
+
+	rows, err := ss.Query(q, args...)
+	if err != nil {
+		return Session{}, fmt.Errorf("ByID: query exercises: %w", err)
+	}
+	defer rows.Close()
+
+	s.Exercises = []Exercise{}
+	for rows.Next() {
+		x := Exercise{Sets: []Set{}}
+		if err := rows.Scan(
+			&x.Index,
+			&x.Source,
+			&x.Name,
+			&x.Catalog,
+			&x.Kind,
+			&x.Target.Weight,
+			&x.Target.Repetitions,
+			&x.Target.DurationSeconds,
+			&x.Target.DistanceMeters,
+		); err != nil {
+			return Session{}, fmt.Errorf("ByID: scan exercise: %w", err)
+		}
+		s.Exercises = append(s.Exercises, x)
+	}
+
+	q, args, err = ss.CompileStatement(setsStmt, data)
+	if err != nil {
+		return Session{}, fmt.Errorf("ByID: compile sets: %w", err)
+	}
+
+	rows, err = ss.Query(q, args...)
+	if err != nil {
+		return Session{}, fmt.Errorf("ByID: query sets: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			x   int
+			set Set
+		)
+
+		if err := rows.Scan(
+			&x,
+			&set.Index,
+			&set.Weight,
+			&set.Repetitions,
+			&set.DurationSeconds,
+			&set.DistanceMeters,
+			&set.RPE,
+			&set.Completed,
+			&set.Warmup,
+			&set.LoggedAt,
+		); err != nil {
+			return Session{}, fmt.Errorf("ByID: scan set: %w", err)
+		}
+
+		for i := range s.Exercises {
+			if s.Exercises[i].Index == x {
+				set.annotate(s.Exercises[i].Kind)
+				s.Exercises[i].Sets = append(s.Exercises[i].Sets, set)
+			}
+		}
+	}
+
+	return s, nil
+}
+
+func (ss *SQLSessionStore) ByOwner(owner string) ([]Session, error) {
+	const stmt = `
+  SELECT owner, session_index, COALESCE(workout, 0), name, performed_on, started_at, finished_at, notes
+  FROM sessions
+  WHERE owner = {{ . }}
+  `
+
+	if owner == "" {
+		return []Session{}, fmt.Errorf("ByOwner: %w", ErrInvalidFields)
+	}
+
+	q, args, err := ss.CompileStatement(stmt, owner)
+	if err != nil {
+		return []Session{}, fmt.Errorf("ByOwner: compile: %w", err)
+	}
+
+	rows, err := ss.Query(q, args...)
+	if err != nil {
+		return []Session{}, fmt.Errorf("ByOwner: query: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []Session
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return []Session{}, fmt.Errorf("ByOwner: scan: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Index > sessions[j].Index })
+
+	return sessions, nil
+}
+
+func (ss *SQLSessionStore) History(owner string, query HistoryQuery) (History, error) {
+	const (
+		sessionsStmt = `
+    SELECT s.session_index, s.performed_on, s.name, x.kind
+    FROM sessions s
+    JOIN session_exercises x ON x.owner = s.owner AND x.session = s.session_index
+    WHERE s.owner = {{ .Owner }}
+      AND (x.catalog = {{ .Exercise }} OR LOWER(x.name) = LOWER({{ .Exercise }}))
+      {{ if .From }}AND s.performed_on >= {{ .From }}{{ end }}
+      {{ if .To }}AND s.performed_on <= {{ .To }}{{ end }}
+      {{ if .After }}
+      AND (s.performed_on < {{ .Date }}
+        OR (s.performed_on = {{ .Date }} AND s.session_index < {{ .Session }}))
+      {{ end }}
+    ORDER BY s.performed_on DESC, s.session_index DESC, x.exercise_index
+    `
+
+		setsStmt = `
+    SELECT t.session, t.set_index, t.weight, t.repetitions, t.duration_seconds,
+      t.distance_meters, t.rpe, t.completed, t.logged_at
+    FROM session_sets t
+    JOIN session_exercises x ON x.owner = t.owner AND x.session = t.session AND x.exercise_index = t.exercise
+    JOIN sessions s ON s.owner = t.owner AND s.session_index = t.session
+    WHERE t.owner = {{ .Owner }}
+      AND (x.catalog = {{ .Exercise }} OR LOWER(x.name) = LOWER({{ .Exercise }}))
+      AND (s.performed_on > {{ .Oldest.Date }}
+        OR (s.performed_on = {{ .Oldest.Date }} AND s.session_index >= {{ .Oldest.Session }}))
+      AND (s.performed_on < {{ .Newest.Date }}
+        OR (s.performed_on = {{ .Newest.Date }} AND s.session_index <= {{ .Newest.Session }}))
+      AND NOT t.warmup
+    ORDER BY s.performed_on DESC, t.session DESC, t.exercise, t.set_index
+    `
+	)
+
+	if owner == "" {
+		return History{}, fmt.Errorf("History: %w", ErrInvalidFields)
+	}
+
+	if err := query.Validate(); err != nil {
+		return History{}, fmt.Errorf("History: %w", err)
+	}
+
+	after, err := parseCursor(query.Cursor)
+	if err != nil {
+		return History{}, fmt.Errorf("History: %w", err)
+	}
+
+	data := struct {
+		Owner string
+		HistoryQuery
+		cursor
+		After  bool
+		Oldest cursor
+		Newest cursor
+	}{Owner: owner, HistoryQuery: query, cursor: after, After: !after.start()}
+
+	q, args, err := ss.CompileStatement(sessionsStmt, data)
+	if err != nil {
+		return History{}, fmt.Errorf("History: compile: %w", err)
+	}
+
+	rows, err := ss.Query(q, args...)
+	if err != nil {
+		return History{}, fmt.Errorf("History: query: %w", err)
+	}
+	defer rows.Close()
+
+	history := History{Entries: []HistoryEntry{}}
+	for rows.Next() {
+		var e HistoryEntry
+		if err := rows.Scan(&e.Session, &e.Date, &e.Name, &e.Kind); err != nil {
+			return History{}, fmt.Errorf("History: scan: %w", err)
+		}
+
+		// an exercise performed twice in a session is a single entry
+		if n := len(history.Entries); n > 0 && history.Entries[n-1].Session == e.Session {
+			continue
+		}
+
+		if len(history.Entries) == query.Limit {
+			history.Cursor = history.Entries[query.Limit-1].cursor().String()
+			break
+		}
+
+		e.Sets = []Set{}
+		history.Entries = append(history.Entries, e)
+	}
+
+	if err := rows.Close(); err != nil {
+		return History{}, fmt.Errorf("History: close: %w", err)
+	}
+
+	if len(history.Entries) == 0 {
+		return history, nil
+	}
+
+	// the sets are selected by the range of the page in the order of the history
+	data.Oldest = history.Entries[len(history.Entries)-1].cursor()
+	data.Newest = history.Entries[0].cursor()
+
+	q, args, err = ss.CompileStatement(setsStmt, data)
+	if err != nil {
+		return History{}, fmt.Errorf("History: compile sets: %w", err)
+	}
+
+	rows, err = ss.Query(q, args...)
+	if err != nil {
+		return History{}, fmt.Errorf("History: query sets: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make(map[int]*HistoryEntry, len(history.Entries))
+	for i := range history.Entries {
+		entries[history.Entries[i].Session] = &history.Entries[i]
+	}
+
+	for rows.Next() {
+		var (
+			session int
+			set     Set
+		)
+
+		if err := rows.Scan(
+			&session,
+			&set.Index,
+			&set.Weight,
+			&set.Repetitions,
+			&set.DurationSeconds,
+			&set.DistanceMeters,
+			&set.RPE,
+			&set.Completed,
+			&set.LoggedAt,
+		); err != nil {
+			return History{}, fmt.Errorf("History: scan set: %w", err)
+		}
+
+		if e, ok := entries[session]; ok {
+			set.annotate(e.Kind)
+			e.Sets = append(e.Sets, set)
+		}
+	}
+
+	if query.Aggregate {
+		for i := range history.Entries {
+			e := &history.Entries[i]
+
+			var bodyWeight float64
+			if e.Kind == exercise.KindBodyweight || e.Kind == exercise.KindAssisted {
+				if bodyWeight, err = ss.bodies.WeightOn(owner, e.Date); err != nil {
+					return History{}, fmt.Errorf("History: %w", err)
+				}
+			}
+
+			e.aggregate(bodyWeight)
+		}
+	}
+
+	return history, nil
+}
+
+func (ss *SQLSessionStore) Start(owner string, workout int, notes string) (Session, error) {
+	const (
+		workoutStmt = `
+    SELECT name
+    FROM workouts
+    WHERE owner = {{ .Owner }} AND workout_index = {{ .Workout }}
+    `
+
+		sessionStmt = `
+    INSERT INTO sessions (owner, session_index, workout_owner, workout, name, performed_on, started_at, notes)
+    VALUES ({{ .Owner }}, {{ .Index }}, {{ .Owner }}, {{ .Workout }}, {{ .Name }}, {{ .Date }}, {{ .StartedAt }}, {{ .Notes }})
+    `
+
+		exercisesStmt = `
+    INSERT INTO session_exercises (
+      owner, session, exercise_index, workout_owner, workout, workout_exercise, name, catalog, kind,
+      target_weight, target_repetitions, target_duration_seconds, target_distance_meters
+    )
+    SELECT owner, CAST({{ .Index }} AS INTEGER), exercise_index, owner, workout, exercise_index, name, catalog,
+      kind, weight, repetitions, duration_seconds, distance_meters
+    FROM exercises
+    WHERE owner = {{ .Owner }} AND workout = {{ .Workout }}
+    `
+	)
+
+	if owner == "" || workout <= 0 {
+		return Session{}, fmt.Errorf("Start: %w", ErrInvalidFields)
+	}
+
+	data := struct {
+		Owner   string
+		Workout int
+	}{owner, workout}
+
+	q, args, err := ss.CompileStatement(workoutStmt, data)
+	if err != nil {
+		return Session{}, fmt.Errorf("Start: compile workout: %w", err)
+	}
+
+	var name string
+	if err := ss.QueryRow(q, args...).Scan(&name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Session{}, fmt.Errorf("Start: workout %s/%d: %w", owner, workout, ErrNotFound)
+		}
+		return Session{}, fmt.Errorf("Start: query workout: %w", err)
+	}
+
+	loc, err := ss.location(owner)
+	if err != nil {
+		return Session{}, fmt.Errorf("Start: %w", err)
+	}
+
+	started := ss.timestamp()
+	s := Session{
+		Owner:     owner,
+		Workout:   workout,
+		Name:      name,
+		Date:      started.In(loc).Format(DateLayout),
+		StartedAt: started,
+		Notes:     notes,
+	}
+
+	tx, err := ss.Begin()
+	if err != nil {
+		return Session{}, fmt.Errorf("Start: new transaction: %w", err)
+	}
+
+	if s.Index, err = ss.nextIndex(tx, owner); err != nil {
+		tx.Rollback()
+		return Session{}, fmt.Errorf("Start: %w", err)
+	}
+
+	for _, stmt := range []string{sessionStmt, exercisesStmt} {
+		q, args, err := ss.CompileStatement(stmt, s)
+		if err != nil {
+			tx.Rollback()
+			return Session{}, fmt.Errorf("Start: compile: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			tx.Rollback()
+			return Session{}, fmt.Errorf("Start: execute: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Session{}, fmt.Errorf("Start: commit transaction: %w", err)
+	}
+
+	s, err = ss.ByID(owner, s.Index)
+	if err != nil {
+		return Session{}, fmt.Errorf("Start: fetch %s/%d: %w", owner, s.Index, err)
+	}
+
+	return s, nil
+}
+
+func (ss *SQLSessionStore) LogSet(owner string, session int, exercise int, set Set) (Set, error) {
+	if owner == "" || session <= 0 || exercise <= 0 {
+		return Set{}, fmt.Errorf("LogSet: %w", ErrInvalidFields)
+	}
+
+	s, x, err := ss.unfinished(owner, session, exercise)
+	if err != nil {
+		return Set{}, fmt.Errorf("LogSet: %w", err)
+	}
+
+	if err := set.Validate(x.Kind); err != nil {
+		return Set{}, fmt.Errorf("LogSet: %w", err)
+	}
+
+	tx, err := ss.Begin()
+	if err != nil {
+		return Set{}, fmt.Errorf("LogSet: new transaction: %w", err)
+	}
+
+	logged, err := ss.logSets(tx, s.Owner, s.Index, x, []Set{set})
+	if err != nil {
+		tx.Rollback()
+		return Set{}, fmt.Errorf("LogSet: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Set{}, fmt.Errorf("LogSet: commit transaction: %w", err)
+	}
+
+	return logged[0], nil
+}
+
+func (ss *SQLSessionStore) LogWarmup(owner string, session int, exercise int, config strength.WarmupConfig) ([]Set, error) {
+	if owner == "" || session <= 0 || exercise <= 0 {
+		return []Set{}, fmt.Errorf("LogWarmup: %w", ErrInvalidFields)
+	}
+
+	s, x, err := ss.unfinished(owner, session, exercise)
+	if err != nil {
+		return []Set{}, fmt.Errorf("LogWarmup: %w", err)
+	}
+
+	rounding, err := ss.rounder.Rounding(owner, x.Catalog)
+	if err != nil {
+		return []Set{}, fmt.Errorf("LogWarmup: %w", err)
+	}
+
+	warmup, err := x.Warmup(rounding.Warmup(config))
+	if err != nil {
+		return []Set{}, fmt.Errorf("LogWarmup: %w", err)
+	}
+
+	for i := range warmup {
+		if err := warmup[i].Validate(x.Kind); err != nil {
+			return []Set{}, fmt.Errorf("LogWarmup: %w", err)
+		}
+	}
+
+	tx, err := ss.Begin()
+	if err != nil {
+		return []Set{}, fmt.Errorf("LogWarmup: new transaction: %w", err)
+	}
+
+	sets, err := ss.logSets(tx, s.Owner, s.Index, x, warmup)
+	if err != nil {
+		tx.Rollback()
+		return []Set{}, fmt.Errorf("LogWarmup: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return []Set{}, fmt.Errorf("LogWarmup: commit transaction: %w", err)
+	}
+
+	return sets, nil
+}
+
+// unfinished returns the session and its exercise sets are logged for,
+// the session must not be finished yet
+func (ss *SQLSessionStore) unfinished(owner string, session int, exercise int) (Session, *Exercise, error) {
+	s, err := ss.ByID(owner, session)
+	if err != nil {
+		return Session{}, nil, err
+	}
+
+	if s.Finished() {
+		return Session{}, nil, fmt.Errorf("%s: %w", s.Ref(), ErrFinished)
+	}
+
+	for i := range s.Exercises {
+		if s.Exercises[i].Index == exercise {
+			return s, &s.Exercises[i], nil
+		}
+	}
+
+	return Session{}, nil, fmt.Errorf("exercise %s/%d: %w", s.Ref(), exercise, ErrNotFound)
+}
+
+// logSets appends the validated sets to the exercise of the session
+// and returns them annotated with their index and time logged
+func (ss *SQLSessionStore) logSets(tx *sql.Tx, owner string, session int, x *Exercise, sets []Set) ([]Set, error) {
+	const (
+		lastStmt = `
+    SELECT MAX(set_index)
+    FROM session_sets
+    WHERE owner = {{ .Owner }} AND session = {{ .Session }} AND exercise = {{ .Exercise }}
+    `
+
+		insertStmt = `
+    INSERT INTO session_sets (
+      owner, session, exercise, set_index, weight, repetitions,
+      duration_seconds, distance_meters, rpe, completed, warmup, logged_at
+    )
+    VALUES (
+      {{ .Owner }}, {{ .Session }}, {{ .Exercise }}, {{ .Set.Index }}, {{ .Set.Weight }}, {{ .Set.Repetitions }},
+      {{ .Set.DurationSeconds }}, {{ .Set.DistanceMeters }}, {{ .Set.RPE }}, {{ .Set.Completed }},
+      {{ .Set.Warmup }}, {{ .Set.LoggedAt }}
+    )
+    `
+	)
+
+	data := struct {
+		Owner    string
+		Session  int
+		Exercise int
+		Set      Set
+	}{owner, session, x.Index, Set{}}
+
+	q, args, err := ss.CompileStatement(lastStmt, data)
+	if err != nil {
+		return []Set{}, fmt.Errorf("logSets: compile last index: %w", err)
+	}
+
+	var last sql.NullInt32
+	if err := tx.QueryRow(q, args...).Scan(&last); err != nil {
+		return []Set{}, fmt.Errorf("logSets: query last index: %w", err)
+	}
+
+	logged := ss.timestamp()
+
+	out := make([]Set, 0, len(sets))
+	for i, set := range sets {
+		set.Index = int(last.Int32) + i + 1
+		set.LoggedAt = logged
+		data.Set = set
+
+		q, args, err := ss.CompileStatement(insertStmt, data)
+		if err != nil {
+			return []Set{}, fmt.Errorf("logSets: compile: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			return []Set{}, fmt.Errorf("logSets: execute set %d: %w", set.Index, err)
+		}
+
+		set.annotate(x.Kind)
+		out = append(out, set)
+	}
+
+	return out, nil
+}
+
+func (ss *SQLSessionStore) Finish(owner string, session int, notes string) (Session, error) {
+	const stmt = `
+  UPDATE sessions
+  SET finished_at = {{ .FinishedAt }}, notes = {{ .Notes }}
+  WHERE owner = {{ .Owner }} AND session_index = {{ .Index }}
+  `
+
+	if owner == "" || session <= 0 {
+		return Session{}, fmt.Errorf("Finish: %w", ErrInvalidFields)
+	}
+
+	s, err := ss.ByID(owner, session)
+	if err != nil {
+		return Session{}, fmt.Errorf("Finish: %w", err)
+	}
+
+	if s.Finished() {
+		return Session{}, fmt.Errorf("Finish: %s: %w", s.Ref(), ErrFinished)
+	}
+
+	finished := ss.timestamp()
+	s.FinishedAt = &finished
+
+	if notes != "" {
+		s.Notes = strings.TrimSpace(s.Notes + "\n" + notes)
+	}
+
+	q, args, err := ss.CompileStatement(stmt, s)
+	if err != nil {
+		return Session{}, fmt.Errorf("Finish: compile: %w", err)
+	}
+
+	if _, err := ss.Exec(q, args...); err != nil {
+		return Session{}, fmt.Errorf("Finish: execute: %w", err)
+	}
+
+	s, err = ss.ByID(owner, session)
+	if err != nil {
+		return Session{}, fmt.Errorf("Finish: fetch %s/%d: %w", owner, session, err)
+	}
+
+	return s, nil
+}
+
+func (ss *SQLSessionStore) Delete(owner string, session int) (Session, error) {
+	const (
+		setsStmt = `
+    DELETE FROM session_sets
+    WHERE owner = {{ .Owner }} AND session = {{ .Index }}
+    `
+
+		exercisesStmt = `
+    DELETE FROM session_exercises
+    WHERE owner = {{ .Owner }} AND session = {{ .Index }}
+    `
+
+		sessionStmt = `
+    DELETE FROM sessions
+    WHERE owner = {{ .Owner }} AND session_index = {{ .Index }}
+    `
+	)
+
+	if owner == "" || session <= 0 {
+		return Session{}, fmt.Errorf("Delete: %w", ErrInvalidFields)
+	}
+
+	s, err := ss.ByID(owner, session)
+	if err != nil {
+		return Session{}, fmt.Errorf("Delete: %w", err)
+	}
+
+	tx, err := ss.Begin()
+	if err != nil {
+		return Session{}, fmt.Errorf("Delete: new transaction: %w", err)
+	}
+
+	for _, stmt := range []string{setsStmt, exercisesStmt, sessionStmt} {
+		q, args, err := ss.CompileStatement(stmt, s)
+		if err != nil {
+			tx.Rollback()
+			return Session{}, fmt.Errorf("Delete: compile: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			tx.Rollback()
+			return Session{}, fmt.Errorf("Delete: execute: %w", err)
+		}
+	}
+
+	// records set during the session no longer stand without its sets,
+	// the records they beat are recomputed from the remaining sessions
+	if err := ss.records.Rebuild(tx, owner, session); err != nil {
+		tx.Rollback()
+		return Session{}, fmt.Errorf("Delete: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Session{}, fmt.Errorf("Delete: commit transaction: %w", err)
+	}
+
+	return s, nil
+}
+
+// nextIndex returns the next session index of a user, indices of
+// deleted sessions are never reused so references to them don't
+// end up at another session
+func (ss *SQLSessionStore) nextIndex(tx *sql.Tx, owner string) (int, error) {
+	const (
+		counterStmt = `
+    INSERT INTO session_counters (owner, last_index)
+    SELECT CAST({{ . }} AS TEXT), COALESCE(MAX(session_index), 0) + 1
+    FROM sessions
+    WHERE owner = {{ . }}
+    ON CONFLICT (owner) DO UPDATE
+    SET last_index = session_counters.last_index + 1
+    `
+
+		indexStmt = `
+    SELECT last_index
+    FROM session_counters
+    WHERE owner = {{ . }}
+    `
+	)
+
+	q, args, err := ss.CompileStatement(counterStmt, owner)
+	if err != nil {
+		return 0, fmt.Errorf("nextIndex: compile counter: %w", err)
+	}
+
+	if _, err := tx.Exec(q, args...); err != nil {
+		return 0, fmt.Errorf("nextIndex: increment counter: %w", err)
+	}
+
+	q, args, err = ss.CompileStatement(indexStmt, owner)
+	if err != nil {
+		return 0, fmt.Errorf("nextIndex: compile index: %w", err)
+	}
+
+	var index int
+	if err := tx.QueryRow(q, args...).Scan(&index); err != nil {
+		return 0, fmt.Errorf("nextIndex: query index: %w", err)
+	}
+
+	return index, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSession(s scanner) (Session, error) {
+	var (
+		session  Session
+		finished sql.NullTime
+	)
+
+	if err := s.Scan(
+		&session.Owner,
+		&session.Index,
+		&session.Workout,
+		&session.Name,
+		&session.Date,
+		&session.StartedAt,
+		&finished,
+		&session.Notes,
+	); err != nil {
+		return Session{}, err
+	}
+
+	if finished.Valid {
+		t := finished.Time.UTC()
+		session.FinishedAt = &t
+	}
+	session.StartedAt = session.StartedAt.UTC()
+
+	return session, nil
+}
@@ -0,0 +1,364 @@
+package session
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/body"
+	"github.com/scrot/musclemem-api/internal/equipment"
+	"github.com/scrot/musclemem-api/internal/exercise"
+	"github.com/scrot/musclemem-api/internal/record"
+	"github.com/scrot/musclemem-api/internal/schedule"
+	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/strength"
+	"github.com/scrot/musclemem-api/internal/user"
+	"github.com/scrot/musclemem-api/internal/workout"
+)
+
+func TestSessionLifecycle(t *testing.T) {
+	sessions, flush := mockSessionStore(t)
+	defer flush()
+
+	started, err := sessions.Start("user", 1, "feeling strong")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if started.Name != "workout" || started.Date != "2026-10-19" {
+		t.Errorf("want workout on 2026-10-19 but got %s", started)
+	}
+
+	if len(started.Exercises) != 1 || started.Exercises[0].Target.Weight != 60 {
+		t.Fatalf("want exercise with target weight 60 but got %v", started.Exercises)
+	}
+
+	cs := []struct {
+		name    string
+		input   Set
+		wantErr error
+	}{
+		{"validSet", Set{Load: Load{Weight: 60, Repetitions: 8}, RPE: 8, Completed: true}, nil},
+		{"missingRepetitions", Set{Load: Load{Weight: 60}, Completed: true}, ErrInvalidFields},
+		{"failedWithoutRepetitions", Set{Load: Load{Weight: 60}}, nil},
+		{"negativeRepetitions", Set{Load: Load{Weight: 60, Repetitions: -1}}, ErrInvalidFields},
+		{"invalidRPE", Set{Load: Load{Weight: 60, Repetitions: 8}, RPE: 11}, ErrInvalidFields},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := sessions.LogSet("user", started.Index, 1, c.input)
+			if !errors.Is(err, c.wantErr) {
+				t.Errorf("want error %v but got %v", c.wantErr, err)
+			}
+		})
+	}
+
+	finished, err := sessions.Finish("user", started.Index, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !finished.Finished() {
+		t.Errorf("want %s finished", finished)
+	}
+
+	if len(finished.Exercises[0].Sets) != 2 {
+		t.Errorf("want 2 logged sets but got %d", len(finished.Exercises[0].Sets))
+	}
+
+	want := []exercise.Performed{{Workout: 1, Exercise: 1, Performance: exercise.Performance{Sets: 2}}}
+	if got := finished.Performed(); !reflect.DeepEqual(got, want) {
+		t.Errorf("want performed %v but got %v", want, got)
+	}
+
+	if _, err := sessions.LogSet("user", started.Index, 1, cs[0].input); !errors.Is(err, ErrFinished) {
+		t.Errorf("want error %v but got %v", ErrFinished, err)
+	}
+
+	if _, err := sessions.Finish("user", started.Index, ""); !errors.Is(err, ErrFinished) {
+		t.Errorf("want error %v but got %v", ErrFinished, err)
+	}
+
+	const (
+		recordStmt = `
+    INSERT INTO records (owner, exercise, record_type, name, value, session, performed_on)
+    VALUES ('user', 'squat', 'heaviest_weight', 'squat', 100, {{ . }}, '2026-10-19')
+    `
+
+		recordsStmt = `SELECT COUNT(*) FROM records WHERE owner = 'user' AND session = {{ . }}`
+	)
+
+	q, args, err := sessions.CompileStatement(recordStmt, started.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sessions.Exec(q, args...); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sessions.Delete("user", started.Index); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sessions.ByID("user", started.Index); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v but got %v", ErrNotFound, err)
+	}
+
+	q, args, err = sessions.CompileStatement(recordsStmt, started.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var records int
+	if err := sessions.QueryRow(q, args...).Scan(&records); err != nil {
+		t.Fatal(err)
+	}
+
+	if records != 0 {
+		t.Errorf("want the records of the deleted session removed but got %d", records)
+	}
+
+	// indices of deleted sessions are not reused
+	next, err := sessions.Start("user", 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if next.Index != started.Index+1 {
+		t.Errorf("want session %d after deleting %d but got %d", started.Index+1, started.Index, next.Index)
+	}
+}
+
+func TestStartLocalDate(t *testing.T) {
+	sessions, flush := mockSessionStore(t)
+	defer flush()
+
+	local := schedule.Schedule{Mode: schedule.ModeRotation, TimeZone: "Pacific/Auckland", Slots: []schedule.Slot{{Workout: 1}}}
+	if _, err := schedule.NewSQLScheduleStore(sessions.SqlDatastore).Set("user", local); err != nil {
+		t.Fatal(err)
+	}
+
+	// 18:30 UTC is already the next morning in Auckland
+	started, err := sessions.Start("user", 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if started.Date != "2026-10-20" || started.StartedAt.Hour() != 18 {
+		t.Errorf("want session on 2026-10-20 started at 18:30 UTC but got %s at %s", started.Date, started.StartedAt)
+	}
+}
+
+func TestDeleteRebuildsRecords(t *testing.T) {
+	sessions, flush := mockSessionStore(t)
+	defer flush()
+
+	records := record.NewSQLRecordStore(sessions.SqlDatastore)
+
+	var latest Session
+	for _, weight := range []float64{60, 70} {
+		s, err := sessions.Start("user", 1, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		set := Set{Load: Load{Weight: weight, Repetitions: 8}, Completed: true}
+		if _, err := sessions.LogSet("user", s.Index, 1, set); err != nil {
+			t.Fatal(err)
+		}
+
+		if latest, err = sessions.Finish("user", s.Index, ""); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := records.Detect("user", s.Index); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := sessions.Delete("user", latest.Index); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := records.ByExercise("user", "bench press")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var heaviest *record.Record
+	for i, r := range got {
+		if r.Session == latest.Index {
+			t.Errorf("want no records of deleted session %d but got %s", latest.Index, r)
+		}
+		if r.Type == record.TypeHeaviestWeight {
+			heaviest = &got[i]
+		}
+	}
+
+	if heaviest == nil || heaviest.Value != 60 || heaviest.Session != latest.Index-1 {
+		t.Errorf("want heaviest weight 60 of session %d restored but got %v", latest.Index-1, heaviest)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	sessions, flush := mockSessionStore(t)
+	defer flush()
+
+	// sessions are logged afterwards, so the order of the
+	// dates differs from the order of the sessions
+	for i, date := range []string{"2026-10-19", "2026-10-17", "2026-10-18"} {
+		performed, _ := time.Parse(DateLayout, date)
+		sessions.now = func() time.Time { return performed.Add(18 * time.Hour) }
+
+		s, err := sessions.Start("user", 1, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		weight := 60 + 2.5*float64(i)
+		for _, reps := range []int{8, 6} {
+			set := Set{Load: Load{Weight: weight, Repetitions: reps}, Completed: true}
+			if _, err := sessions.LogSet("user", s.Index, 1, set); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// a heavier set that wasn't completed is no best set
+		failed := Set{Load: Load{Weight: weight + 10, Repetitions: 2}}
+		if _, err := sessions.LogSet("user", s.Index, 1, failed); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page, err := sessions.History("user", HistoryQuery{Exercise: "Bench Press", Limit: 2, Aggregate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(page.Entries) != 2 || page.Entries[0].Session != 1 || page.Entries[1].Session != 3 || page.Cursor == "" {
+		t.Fatalf("want sessions 1 and 3 with cursor but got %v", page)
+	}
+
+	latest := page.Entries[0]
+	if len(latest.Sets) != 3 || latest.Best == nil || latest.Best.Weight != 60 || latest.Best.Repetitions != 8 || latest.Volume != 60*14+70*2 {
+		t.Errorf("want 3 sets with best 60x8 and volume %.1f but got %v", 60.0*14+70*2, latest)
+	}
+
+	next, err := sessions.History("user", HistoryQuery{Exercise: "bench press", Cursor: page.Cursor})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(next.Entries) != 1 || next.Entries[0].Session != 2 || next.Cursor != "" {
+		t.Errorf("want last session 2 without cursor but got %v", next)
+	}
+
+	if next.Entries[0].Best != nil {
+		t.Errorf("want no aggregation but got best %v", next.Entries[0].Best)
+	}
+
+	none, err := sessions.History("user", HistoryQuery{Exercise: "bench press", From: "2026-10-20"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(none.Entries) != 0 {
+		t.Errorf("want no entries after 2026-10-20 but got %d", len(none.Entries))
+	}
+
+	if _, err := sessions.History("user", HistoryQuery{Exercise: "bench press", From: "19-10-2026"}); !errors.Is(err, ErrInvalidFields) {
+		t.Errorf("want error %v but got %v", ErrInvalidFields, err)
+	}
+
+	if _, err := sessions.History("user", HistoryQuery{Exercise: "bench press", Cursor: "3"}); !errors.Is(err, ErrInvalidFields) {
+		t.Errorf("want error %v for invalid cursor but got %v", ErrInvalidFields, err)
+	}
+}
+
+func TestLogWarmup(t *testing.T) {
+	sessions, flush := mockSessionStore(t)
+	defer flush()
+
+	s, err := sessions.Start("user", 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := sessions.LogWarmup("user", s.Index, 1, strength.WarmupConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []float64{22.5, 35, 47.5}
+	if len(got) != len(want) {
+		t.Fatalf("want %d warm-up sets but got %v", len(want), got)
+	}
+
+	for i, set := range got {
+		if set.Weight != want[i] || !set.Warmup || !set.Completed || set.Index != i+1 {
+			t.Errorf("want completed warm-up set %d at %.1f but got %+v", i+1, want[i], set)
+		}
+	}
+
+	working := Set{Load: Load{Weight: 60, Repetitions: 8}, Completed: true}
+	if _, err := sessions.LogSet("user", s.Index, 1, working); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := sessions.History("user", HistoryQuery{Exercise: "bench press", Aggregate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(h.Entries) != 1 || len(h.Entries[0].Sets) != 1 || h.Entries[0].Volume != 480 {
+		t.Errorf("want a single working set without warm-up sets but got %v", h)
+	}
+
+	if _, err := sessions.LogWarmup("user", s.Index, 1, strength.WarmupConfig{Scheme: "jog"}); !errors.Is(err, ErrInvalidFields) {
+		t.Errorf("want error %v but got %v", ErrInvalidFields, err)
+	}
+}
+
+func mockSessionStore(t *testing.T) (*SQLSessionStore, func()) {
+	t.Helper()
+
+	config := storage.DatastoreConfig{
+		DatabaseURL:   "file://test.db?cache=shared&mode=memory",
+		MigrationPath: "migrations",
+		Overwrite:     false,
+	}
+
+	store, err := storage.NewSqlDatastore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := user.NewSQLUserStore(store).New("user", "test@gmail.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := workout.NewSQLWorkoutStore(store).New("user", "workout"); err != nil {
+		t.Fatal(err)
+	}
+
+	bench := exercise.Exercise{Name: "bench press", Weight: 60, Repetitions: 8}
+	if _, err := exercise.NewSQLExerciseStore(store, equipment.NewSQLEquipmentStore(store)).New("user", 1, bench); err != nil {
+		t.Fatal(err)
+	}
+
+	flush := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal()
+		}
+	}
+
+	sessions := NewSQLSessionStore(store, equipment.NewSQLEquipmentStore(store), body.NewSQLBodyStore(store), record.NewSQLRecordStore(store))
+	sessions.now = func() time.Time {
+		return time.Date(2026, 10, 19, 18, 30, 0, 0, time.UTC)
+	}
+
+	return sessions, flush
+}
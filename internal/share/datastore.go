@@ -0,0 +1,54 @@
+package share
+
+import (
+	"errors"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/workout"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidFields = errors.New("contains invalid fields")
+)
+
+// ShareStore represents the workout share repository
+type ShareStore interface {
+	Retreiver
+	Storer
+	Revoker
+	Importer
+}
+
+// Retreiver implementations allow for shares to be queried
+type Retreiver interface {
+	// ByWorkout returns the shares of the workout of owner
+	// that are not expired, newest first
+	ByWorkout(owner string, workout int) ([]Share, error)
+
+	// Shared returns the workout the token links to, it returns an
+	// ErrNotFound error if the share is revoked, expired or unknown
+	Shared(token string) (Shared, error)
+}
+
+// Storer implementations allow for shares to be created
+type Storer interface {
+	// New creates a share with a new token for the workout of owner,
+	// the share never expires if expiresAt is nil
+	New(owner string, workout int, expiresAt *time.Time) (Share, error)
+}
+
+// Revoker implementations allow for shares to be revoked
+type Revoker interface {
+	// Revoke deletes the share of owner, its token no longer links to the workout
+	Revoke(owner string, token string) (Share, error)
+}
+
+// Importer implementations allow for workouts to be copied between users
+type Importer interface {
+	// Import copies the workout of the source including its exercises and
+	// groups to the workouts of owner, attributed to the author of the source.
+	// Owner is allowed to import its own workouts and workouts of athletes
+	// owner coaches by ref, other workouts only by the token of an active share
+	Import(owner string, source Source) (workout.Workout, error)
+}
@@ -0,0 +1,71 @@
+package share
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/exercise"
+	"github.com/scrot/musclemem-api/internal/workout"
+)
+
+// tokenBytes is the number of random bytes of a share token
+const tokenBytes = 24
+
+// Share is a read-only link to a workout of a user, anyone with the
+// token can view the workout until the share is revoked or expires
+type Share struct {
+	Token     string     `json:"token"`
+	Owner     string     `json:"owner"`
+	Workout   int        `json:"workout"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func (s Share) String() string {
+	return fmt.Sprintf("share of workout %s/%d", s.Owner, s.Workout)
+}
+
+// Expired reports whether the share is expired at t
+func (s Share) Expired(t time.Time) bool {
+	return s.ExpiresAt != nil && !t.Before(*s.ExpiresAt)
+}
+
+// Shared is the workout including its exercises a share links to
+type Shared struct {
+	Workout   workout.Workout     `json:"workout"`
+	Exercises []exercise.Exercise `json:"exercises"`
+	ExpiresAt *time.Time          `json:"expires_at,omitempty"`
+}
+
+// Source refers to the workout to import, either by the Token of
+// a share or by the Workout ref formatted as {username}/{workout-index}
+type Source struct {
+	Token   string `json:"token,omitempty"`
+	Workout string `json:"workout,omitempty"`
+}
+
+// Validate checks if the source refers to a workout in exactly one way
+func (s Source) Validate() error {
+	if (s.Token == "") == (s.Workout == "") {
+		return fmt.Errorf("%w: source requires either a token or a workout", ErrInvalidFields)
+	}
+
+	if s.Workout != "" {
+		if _, err := workout.ParseRef(s.Workout); err != nil {
+			return fmt.Errorf("%w: workout %q: %s", ErrInvalidFields, s.Workout, err)
+		}
+	}
+
+	return nil
+}
+
+// NewToken returns a random url safe token that is infeasible to guess
+func NewToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("NewToken: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
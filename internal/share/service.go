@@ -0,0 +1,167 @@
+package share
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/api"
+)
+
+// NewFetchAllHandler returns the active shares of a workout
+// requires {username} and {workout} path variables
+func NewFetchAllHandler(l *slog.Logger, shares Retreiver) http.Handler {
+	l = l.With("handler", "FetchAllHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username, "workout", r.PathValue("workout"))
+
+		wi, err := strconv.Atoi(r.PathValue("workout"))
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		ss, err := shares.ByWorkout(username, wi)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched shares", "count", len(ss))
+
+		if err := api.WriteJSON(w, http.StatusOK, ss); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewCreateHandler creates a read-only link to a workout
+// requires {username} and {workout} path variables
+// optional json payload {"expires_at": RFC3339}, without expiry the link never expires
+func NewCreateHandler(l *slog.Logger, shares Storer) http.Handler {
+	l = l.With("handler", "CreateHandler")
+
+	type Request struct {
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username, "workout", r.PathValue("workout"))
+
+		wi, err := strconv.Atoi(r.PathValue("workout"))
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		// the payload is optional
+		var req Request
+		if r.ContentLength != 0 {
+			if req, err = api.ReadJSON[Request](r); err != nil {
+				api.WriteInternalError(l, w, err, "")
+				return
+			}
+		}
+
+		s, err := shares.New(username, wi, req.ExpiresAt)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s created", s))
+
+		if err := api.WriteJSON(w, http.StatusOK, s); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewRevokeHandler revokes a share of a workout
+// requires {username} and {token} path variables
+func NewRevokeHandler(l *slog.Logger, shares Revoker) http.Handler {
+	l = l.With("handler", "RevokeHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		s, err := shares.Revoke(username, r.PathValue("token"))
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s revoked", s))
+
+		if err := api.WriteJSON(w, http.StatusOK, s); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewSharedHandler returns the workout and exercises a share links to,
+// it is public and does not require the viewer to have an account
+// requires {token} path variable
+func NewSharedHandler(l *slog.Logger, shares Retreiver) http.Handler {
+	l = l.With("handler", "SharedHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, err := shares.Shared(r.PathValue("token"))
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("viewed shared %s", s.Workout))
+
+		if err := api.WriteJSON(w, http.StatusOK, s); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewImportHandler copies a shared workout or a workout of the user itself
+// to the workouts of the user, attributed to the original author
+// requires {username} path variable
+// requires json payload {"token": TOKEN} or {"workout": "{username}/{workout-index}"}
+func NewImportHandler(l *slog.Logger, shares Importer) http.Handler {
+	l = l.With("handler", "ImportHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		source, err := api.ReadJSON[Source](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		imported, err := shares.Import(username, source)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("imported %s", imported))
+
+		if err := api.WriteJSON(w, http.StatusOK, imported); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
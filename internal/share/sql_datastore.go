@@ -0,0 +1,469 @@
+package share
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/catalog"
+	"github.com/scrot/musclemem-api/internal/coach"
+	"github.com/scrot/musclemem-api/internal/exercise"
+	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/workout"
+)
+
+const shareColumns = `token, owner, workout, created_at, expires_at`
+
+type SQLShareStore struct {
+	*storage.SqlDatastore
+
+	// workouts returns the shared and imported workouts
+	workouts workout.Retreiver
+
+	// exercises returns the exercises of shared workouts
+	exercises exercise.Retreiver
+
+	// coaches returns the access to workouts of others imported by ref
+	coaches coach.Authorizer
+
+	// now returns the current time, replaceable for testing
+	now func() time.Time
+}
+
+func NewSQLShareStore(db *storage.SqlDatastore, workouts workout.Retreiver, exercises exercise.Retreiver, coaches coach.Authorizer) *SQLShareStore {
+	return &SQLShareStore{db, workouts, exercises, coaches, time.Now}
+}
+
+func (ss *SQLShareStore) timestamp() time.Time {
+	return ss.now().UTC().Truncate(time.Second)
+}
+
+func (ss *SQLShareStore) ByWorkout(owner string, workout int) ([]Share, error) {
+	const stmt = `
+  SELECT ` + shareColumns + `
+  FROM workout_shares
+  WHERE owner = {{ .Owner }} AND workout = {{ .Workout }}
+  ORDER BY created_at DESC
+  `
+
+	if owner == "" || workout <= 0 {
+		return []Share{}, fmt.Errorf("ByWorkout: %w", ErrInvalidFields)
+	}
+
+	data := struct {
+		Owner   string
+		Workout int
+	}{owner, workout}
+
+	q, args, err := ss.CompileStatement(stmt, data)
+	if err != nil {
+		return []Share{}, fmt.Errorf("ByWorkout: compile: %w", err)
+	}
+
+	rows, err := ss.Query(q, args...)
+	if err != nil {
+		return []Share{}, fmt.Errorf("ByWorkout: query: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		now    = ss.timestamp()
+		shares = []Share{}
+	)
+
+	for rows.Next() {
+		s, err := scanShare(rows)
+		if err != nil {
+			return []Share{}, fmt.Errorf("ByWorkout: scan: %w", err)
+		}
+
+		if !s.Expired(now) {
+			shares = append(shares, s)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return []Share{}, fmt.Errorf("ByWorkout: rows: %w", err)
+	}
+
+	return shares, nil
+}
+
+func (ss *SQLShareStore) Shared(token string) (Shared, error) {
+	if token == "" {
+		return Shared{}, fmt.Errorf("Shared: %w", ErrInvalidFields)
+	}
+
+	s, err := ss.byToken(token)
+	if err != nil {
+		return Shared{}, fmt.Errorf("Shared: %w", err)
+	}
+
+	if s.Expired(ss.timestamp()) {
+		return Shared{}, fmt.Errorf("Shared: share expired at %s: %w", s.ExpiresAt, ErrNotFound)
+	}
+
+	w, err := ss.workouts.ByID(s.Owner, s.Workout)
+	if err != nil {
+		if errors.Is(err, workout.ErrNotFound) {
+			return Shared{}, fmt.Errorf("Shared: workout %s/%d: %w", s.Owner, s.Workout, ErrNotFound)
+		}
+		return Shared{}, fmt.Errorf("Shared: %w", err)
+	}
+
+	xs, err := ss.exercises.ByWorkout(s.Owner, s.Workout)
+	if err != nil {
+		return Shared{}, fmt.Errorf("Shared: %w", err)
+	}
+
+	return Shared{Workout: w, Exercises: xs, ExpiresAt: s.ExpiresAt}, nil
+}
+
+func (ss *SQLShareStore) New(owner string, workout int, expiresAt *time.Time) (Share, error) {
+	const (
+		workoutStmt = `
+    SELECT COUNT(*)
+    FROM workouts
+    WHERE owner = {{ .Owner }} AND workout_index = {{ .Workout }}
+    `
+
+		shareStmt = `
+    INSERT INTO workout_shares (` + shareColumns + `)
+    VALUES ({{ .Token }}, {{ .Owner }}, {{ .Workout }}, {{ .CreatedAt }}, {{ .ExpiresAt }})
+    `
+	)
+
+	if owner == "" || workout <= 0 {
+		return Share{}, fmt.Errorf("New: %w", ErrInvalidFields)
+	}
+
+	s := Share{Owner: owner, Workout: workout, CreatedAt: ss.timestamp()}
+
+	if expiresAt != nil {
+		expires := expiresAt.UTC().Truncate(time.Second)
+		if !expires.After(s.CreatedAt) {
+			return Share{}, fmt.Errorf("New: %w: expiry %s is in the past", ErrInvalidFields, expires.Format(time.RFC3339))
+		}
+		s.ExpiresAt = &expires
+	}
+
+	q, args, err := ss.CompileStatement(workoutStmt, s)
+	if err != nil {
+		return Share{}, fmt.Errorf("New: compile workout: %w", err)
+	}
+
+	var count int
+	if err := ss.QueryRow(q, args...).Scan(&count); err != nil {
+		return Share{}, fmt.Errorf("New: query workout: %w", err)
+	}
+
+	if count == 0 {
+		return Share{}, fmt.Errorf("New: workout %s/%d: %w", owner, workout, ErrNotFound)
+	}
+
+	if s.Token, err = NewToken(); err != nil {
+		return Share{}, fmt.Errorf("New: %w", err)
+	}
+
+	q, args, err = ss.CompileStatement(shareStmt, s)
+	if err != nil {
+		return Share{}, fmt.Errorf("New: compile: %w", err)
+	}
+
+	if _, err := ss.Exec(q, args...); err != nil {
+		return Share{}, fmt.Errorf("New: execute: %w", err)
+	}
+
+	return s, nil
+}
+
+func (ss *SQLShareStore) Revoke(owner string, token string) (Share, error) {
+	const stmt = `
+  DELETE FROM workout_shares
+  WHERE token = {{ . }}
+  `
+
+	if owner == "" || token == "" {
+		return Share{}, fmt.Errorf("Revoke: %w", ErrInvalidFields)
+	}
+
+	s, err := ss.byToken(token)
+	if err != nil {
+		return Share{}, fmt.Errorf("Revoke: %w", err)
+	}
+
+	// tokens of other users are as unknown as non-existing tokens
+	if s.Owner != owner {
+		return Share{}, fmt.Errorf("Revoke: share of %s: %w", owner, ErrNotFound)
+	}
+
+	q, args, err := ss.CompileStatement(stmt, token)
+	if err != nil {
+		return Share{}, fmt.Errorf("Revoke: compile: %w", err)
+	}
+
+	if _, err := ss.Exec(q, args...); err != nil {
+		return Share{}, fmt.Errorf("Revoke: execute: %w", err)
+	}
+
+	return s, nil
+}
+
+func (ss *SQLShareStore) Import(owner string, source Source) (workout.Workout, error) {
+	const (
+		userStmt = `
+    SELECT COUNT(*)
+    FROM users
+    WHERE username = {{ . }}
+    `
+
+		indexStmt = `
+    SELECT COALESCE(MAX(workout_index), 0)
+    FROM workouts
+    WHERE owner = {{ . }}
+    `
+
+		workoutStmt = `
+    INSERT INTO workouts (owner, workout_index, name, author)
+    VALUES ({{ .Owner }}, {{ .Index }}, {{ .Name }}, {{ .Author }})
+    `
+
+		groupsStmt = `
+    INSERT INTO exercise_groups (owner, workout, group_index, kind, rounds)
+    SELECT CAST({{ .Owner }} AS TEXT), CAST({{ .Index }} AS INTEGER), group_index, kind, rounds
+    FROM exercise_groups
+    WHERE owner = {{ .From.Owner }} AND workout = {{ .From.Index }}
+    `
+
+		// the progression failures of the importer start at zero
+		exerciseStmt = `
+    INSERT INTO exercises (
+      owner, workout, exercise_index, name, catalog, kind, weight, repetitions, duration_seconds,
+      distance_meters, rest_seconds, tempo, target_rpe, target_rir, notes, exercise_group, progression,
+      progression_increment, progression_min_repetitions, progression_max_repetitions,
+      progression_deload_after, progression_deload_percent, progression_failures
+    )
+    VALUES (
+      {{ .Owner }}, {{ .Workout }}, {{ .Index }}, {{ .Name }}, {{ .Catalog }}, {{ .Kind }}, {{ .Weight }},
+      {{ .Repetitions }}, {{ .DurationSeconds }}, {{ .DistanceMeters }}, {{ .RestSeconds }}, {{ .Tempo }},
+      {{ .TargetRPE }}, {{ .TargetRIR }}, {{ .Notes }}, {{ .GroupIndex }}, {{ .P.Rule }}, {{ .P.Increment }},
+      {{ .P.MinRepetitions }}, {{ .P.MaxRepetitions }}, {{ .P.DeloadAfter }}, {{ .P.DeloadPercent }}, 0
+    )
+    `
+	)
+
+	if owner == "" {
+		return workout.Workout{}, fmt.Errorf("Import: %w", ErrInvalidFields)
+	}
+
+	from, err := ss.source(owner, source)
+	if err != nil {
+		return workout.Workout{}, fmt.Errorf("Import: %w", err)
+	}
+
+	xs, err := ss.exercises.ByWorkout(from.Owner, from.Index)
+	if err != nil {
+		return workout.Workout{}, fmt.Errorf("Import: %w", err)
+	}
+
+	for i, x := range xs {
+		// custom catalog entries of others can't be referenced by owner
+		if ref, err := catalog.ParseRef(x.Catalog); err == nil && ref.Owner != "" && ref.Owner != owner {
+			xs[i].Catalog = ""
+		}
+
+		if err := xs[i].Validate(); err != nil {
+			return workout.Workout{}, fmt.Errorf("Import: exercise %s: %w: %w", x.Ref(), ErrInvalidFields, err)
+		}
+	}
+
+	var count int
+	q, args, err := ss.CompileStatement(userStmt, owner)
+	if err != nil {
+		return workout.Workout{}, fmt.Errorf("Import: compile user: %w", err)
+	}
+
+	if err := ss.QueryRow(q, args...).Scan(&count); err != nil {
+		return workout.Workout{}, fmt.Errorf("Import: query user: %w", err)
+	}
+
+	if count == 0 {
+		return workout.Workout{}, fmt.Errorf("Import: user %s: %w", owner, ErrNotFound)
+	}
+
+	// the author of an imported workout remains the original author
+	imported := workout.Workout{Owner: owner, Name: from.Name, Author: from.Author}
+	if imported.Author == "" && from.Owner != owner {
+		imported.Author = from.Owner
+	}
+
+	tx, err := ss.Begin()
+	if err != nil {
+		return workout.Workout{}, fmt.Errorf("Import: begin transaction: %w", err)
+	}
+
+	q, args, err = ss.CompileStatement(indexStmt, owner)
+	if err != nil {
+		tx.Rollback()
+		return workout.Workout{}, fmt.Errorf("Import: compile index: %w", err)
+	}
+
+	if err := tx.QueryRow(q, args...).Scan(&imported.Index); err != nil {
+		tx.Rollback()
+		return workout.Workout{}, fmt.Errorf("Import: query index: %w", err)
+	}
+	imported.Index++
+
+	data := struct {
+		workout.Workout
+		From workout.Workout
+	}{imported, from}
+
+	for _, stmt := range []string{workoutStmt, groupsStmt} {
+		q, args, err := ss.CompileStatement(stmt, data)
+		if err != nil {
+			tx.Rollback()
+			return workout.Workout{}, fmt.Errorf("Import: compile: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			tx.Rollback()
+			return workout.Workout{}, fmt.Errorf("Import: execute: %w", err)
+		}
+	}
+
+	for _, x := range xs {
+		x.Owner = owner
+		x.Workout = imported.Index
+
+		var p exercise.Progression
+		if x.Progression != nil {
+			p = *x.Progression
+		}
+
+		var group int
+		if x.Group != nil {
+			group = x.Group.Index
+		}
+
+		data := struct {
+			exercise.Exercise
+			P          exercise.Progression
+			GroupIndex int
+		}{x, p, group}
+
+		q, args, err := ss.CompileStatement(exerciseStmt, data)
+		if err != nil {
+			tx.Rollback()
+			return workout.Workout{}, fmt.Errorf("Import: compile exercise: %w", err)
+		}
+
+		if _, err := tx.Exec(q, args...); err != nil {
+			tx.Rollback()
+			return workout.Workout{}, fmt.Errorf("Import: execute exercise %s: %w", x.Ref(), err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return workout.Workout{}, fmt.Errorf("Import: commit transaction: %w", err)
+	}
+
+	return imported, nil
+}
+
+// source returns the workout the source refers to, it returns an ErrNotFound
+// error if the workout doesn't exist or owner isn't allowed to see it
+func (ss *SQLShareStore) source(owner string, source Source) (workout.Workout, error) {
+	if err := source.Validate(); err != nil {
+		return workout.Workout{}, err
+	}
+
+	var ref workout.WorkoutRef
+	if source.Token != "" {
+		s, err := ss.byToken(source.Token)
+		if err != nil {
+			return workout.Workout{}, err
+		}
+
+		if s.Expired(ss.timestamp()) {
+			return workout.Workout{}, fmt.Errorf("share expired at %s: %w", s.ExpiresAt, ErrNotFound)
+		}
+
+		ref = workout.WorkoutRef{Username: s.Owner, WorkoutIndex: s.Workout}
+	} else {
+		ref, _ = workout.ParseRef(source.Workout)
+
+		// workouts of others can only be imported by ref when coached,
+		// shared workouts require the token so links can't be guessed
+		if ref.Username != owner {
+			access, err := ss.coaches.Access(ref.Username, owner)
+			if err != nil {
+				return workout.Workout{}, err
+			}
+
+			if !access.Allows(http.MethodGet) {
+				return workout.Workout{}, fmt.Errorf("workout %s: %w", ref, ErrNotFound)
+			}
+		}
+	}
+
+	w, err := ss.workouts.ByID(ref.Username, ref.WorkoutIndex)
+	if err != nil {
+		if errors.Is(err, workout.ErrNotFound) || errors.Is(err, workout.ErrInvalidFields) {
+			return workout.Workout{}, fmt.Errorf("workout %s: %w", ref, ErrNotFound)
+		}
+		return workout.Workout{}, err
+	}
+
+	return w, nil
+}
+
+// byToken returns the share of token including expired shares,
+// it returns an ErrNotFound error if the token is unknown
+func (ss *SQLShareStore) byToken(token string) (Share, error) {
+	const stmt = `
+  SELECT ` + shareColumns + `
+  FROM workout_shares
+  WHERE token = {{ . }}
+  `
+
+	q, args, err := ss.CompileStatement(stmt, token)
+	if err != nil {
+		return Share{}, fmt.Errorf("compile: %w", err)
+	}
+
+	s, err := scanShare(ss.QueryRow(q, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Share{}, fmt.Errorf("share: %w", ErrNotFound)
+		}
+		return Share{}, fmt.Errorf("query: %w", err)
+	}
+
+	return s, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanShare(s scanner) (Share, error) {
+	var (
+		share   Share
+		expires sql.NullTime
+	)
+
+	if err := s.Scan(&share.Token, &share.Owner, &share.Workout, &share.CreatedAt, &expires); err != nil {
+		return Share{}, err
+	}
+
+	share.CreatedAt = share.CreatedAt.UTC()
+	if expires.Valid {
+		t := expires.Time.UTC()
+		share.ExpiresAt = &t
+	}
+
+	return share, nil
+}
@@ -0,0 +1,242 @@
+package share
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/catalog"
+	"github.com/scrot/musclemem-api/internal/coach"
+	"github.com/scrot/musclemem-api/internal/equipment"
+	"github.com/scrot/musclemem-api/internal/exercise"
+	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/user"
+	"github.com/scrot/musclemem-api/internal/workout"
+)
+
+func TestShare(t *testing.T) {
+	shares, flush := mockShareStore(t)
+	defer flush()
+
+	s, err := shares.New("user", 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.Token) < 32 || s.ExpiresAt != nil {
+		t.Errorf("want a long token without expiry but got %+v", s)
+	}
+
+	shared, err := shares.Shared(s.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if shared.Workout.Name != "lower" || len(shared.Exercises) != 2 || shared.Exercises[0].Name != "squat" {
+		t.Errorf("want workout lower with 2 exercises but got %+v", shared)
+	}
+
+	if _, err := shares.New("user", 2, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v sharing unknown workout but got %v", ErrNotFound, err)
+	}
+
+	if _, err := shares.Revoke("other", s.Token); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v revoking share of another user but got %v", ErrNotFound, err)
+	}
+
+	if _, err := shares.Revoke("user", s.Token); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := shares.Shared(s.Token); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v viewing revoked share but got %v", ErrNotFound, err)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	shares, flush := mockShareStore(t)
+	defer flush()
+
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	shares.now = func() time.Time { return now }
+
+	past := now.Add(-time.Hour)
+	if _, err := shares.New("user", 1, &past); !errors.Is(err, ErrInvalidFields) {
+		t.Errorf("want error %v for expiry in the past but got %v", ErrInvalidFields, err)
+	}
+
+	expires := now.Add(24 * time.Hour)
+	s, err := shares.New("user", 1, &expires)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := shares.Shared(s.Token); err != nil {
+		t.Errorf("want share viewable before expiry but got %v", err)
+	}
+
+	now = expires
+	if _, err := shares.Shared(s.Token); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v viewing expired share but got %v", ErrNotFound, err)
+	}
+
+	active, err := shares.ByWorkout("user", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(active) != 0 {
+		t.Errorf("want no active shares but got %v", active)
+	}
+}
+
+func TestImport(t *testing.T) {
+	shares, flush := mockShareStore(t)
+	defer flush()
+
+	if _, err := user.NewSQLUserStore(shares.SqlDatastore).New("friend", "friend@gmail.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := shares.Import("friend", Source{Workout: "user/1"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v importing a workout that is not shared but got %v", ErrNotFound, err)
+	}
+
+	s, err := shares.New("user", 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := shares.Import("friend", Source{Token: s.Token})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if imported.Owner != "friend" || imported.Index != 1 || imported.Name != "lower" || imported.Author != "user" {
+		t.Errorf("want workout lower of friend by user but got %+v", imported)
+	}
+
+	xs, err := shares.exercises.ByWorkout("friend", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(xs) != 2 || xs[1].Name != "deadlift" || xs[1].Weight != 60 {
+		t.Errorf("want the exercises of the workout copied but got %v", xs)
+	}
+
+	// own workouts can be imported by ref, copies keep the original author
+	again, err := shares.Import("friend", Source{Workout: "friend/1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if again.Index != 2 || again.Author != "user" {
+		t.Errorf("want second copy by user but got %+v", again)
+	}
+
+	if _, err := shares.Import("friend", Source{Workout: "user/1"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v importing a shared workout by ref but got %v", ErrNotFound, err)
+	}
+
+	if _, err := shares.Import("friend", Source{Token: s.Token, Workout: "user/1"}); !errors.Is(err, ErrInvalidFields) {
+		t.Errorf("want error %v for ambiguous source but got %v", ErrInvalidFields, err)
+	}
+}
+
+func TestImportCustomCatalog(t *testing.T) {
+	shares, flush := mockShareStore(t)
+	defer flush()
+
+	if _, err := user.NewSQLUserStore(shares.SqlDatastore).New("friend", "friend@gmail.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := catalog.Entry{Name: "Belt Squat", Primary: []catalog.Muscle{catalog.MuscleQuads}, Equipment: catalog.EquipmentMachine, Pattern: catalog.PatternSquat}
+	custom, err := catalog.NewSQLCatalogStore(shares.SqlDatastore).New("user", entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := workout.NewSQLWorkoutStore(shares.SqlDatastore).New("user", "legs"); err != nil {
+		t.Fatal(err)
+	}
+
+	exercises := exercise.NewSQLExerciseStore(shares.SqlDatastore, equipment.NewSQLEquipmentStore(shares.SqlDatastore))
+	if _, err := exercises.New("user", 2, exercise.Exercise{Catalog: custom.Ref().String(), Weight: 80, Repetitions: 8}); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := shares.New("user", 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the custom entry of user is private, friend keeps the name only
+	imported, err := shares.Import("friend", Source{Token: s.Token})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	xs, err := shares.exercises.ByWorkout("friend", imported.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(xs) != 1 || xs[0].Catalog != "" || xs[0].Name != "Belt Squat" {
+		t.Errorf("want Belt Squat without catalog entry but got %v", xs)
+	}
+
+	copied, err := shares.Import("user", Source{Workout: "user/2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	xs, err = shares.exercises.ByWorkout("user", copied.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(xs) != 1 || xs[0].Catalog != custom.Ref().String() {
+		t.Errorf("want own copy to keep catalog entry %s but got %v", custom.Ref(), xs)
+	}
+}
+
+func mockShareStore(t *testing.T) (*SQLShareStore, func()) {
+	t.Helper()
+
+	config := storage.DatastoreConfig{
+		DatabaseURL:   "file://test.db?cache=shared&mode=memory",
+		MigrationPath: "migrations",
+		Overwrite:     false,
+	}
+
+	store, err := storage.NewSqlDatastore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := user.NewSQLUserStore(store).New("user", "test@gmail.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := workout.NewSQLWorkoutStore(store).New("user", "lower"); err != nil {
+		t.Fatal(err)
+	}
+
+	workouts := workout.NewSQLWorkoutStore(store)
+	exercises := exercise.NewSQLExerciseStore(store, equipment.NewSQLEquipmentStore(store))
+	for _, name := range []string{"squat", "deadlift"} {
+		if _, err := exercises.New("user", 1, exercise.Exercise{Name: name, Weight: 60, Repetitions: 5}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	flush := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal()
+		}
+	}
+
+	return NewSQLShareStore(store, workouts, exercises, coach.NewSQLCoachStore(store)), flush
+}
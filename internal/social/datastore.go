@@ -0,0 +1,57 @@
+package social
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidFields = errors.New("contains invalid fields")
+	ErrForbidden     = errors.New("account is private")
+)
+
+// SocialStore represents the follow and feed repository
+type SocialStore interface {
+	Retreiver
+	Follower
+	Privacier
+	Feeder
+}
+
+// Retreiver implementations allow for follows to be queried
+type Retreiver interface {
+	// Following returns the follows of follower including
+	// follows that are not accepted yet
+	Following(follower string) ([]Follow, error)
+
+	// Followers returns the follows of followee including
+	// follows that are not accepted yet
+	Followers(followee string) ([]Follow, error)
+}
+
+// Follower implementations allow for users to be followed
+type Follower interface {
+	// Follow follows followee, the follow is accepted immediately if the followee
+	// is public and it returns an ErrForbidden error if the followee is private
+	Follow(follower string, followee string) (Follow, error)
+
+	// Accept accepts the follow of follower
+	Accept(followee string, follower string) (Follow, error)
+
+	// Unfollow ends the follow, either can end it including pending follows
+	Unfollow(follower string, followee string) (Follow, error)
+}
+
+// Privacier implementations allow for the privacy of accounts to be managed
+type Privacier interface {
+	// Privacy returns the privacy of the account of username
+	Privacy(username string) (Privacy, error)
+
+	// SetPrivacy changes the privacy of the account of username
+	SetPrivacy(username string, privacy Privacy) (Privacy, error)
+}
+
+// Feeder implementations allow for the activity of followed users to be queried
+type Feeder interface {
+	// Feed returns a page of the activities of the users username
+	// follows, only including users that are visible to username
+	Feed(username string, query FeedQuery) (Feed, error)
+}
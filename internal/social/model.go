@@ -0,0 +1,159 @@
+package social
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/record"
+)
+
+const (
+	DefaultFeedLimit = 20
+	MaxFeedLimit     = 50
+)
+
+// Privacy is the visibility of the activity of an account to other users
+type Privacy string
+
+const (
+	// PrivacyPublic accounts can be followed by anyone
+	PrivacyPublic Privacy = "public"
+
+	// PrivacyFollowers accounts approve their followers
+	PrivacyFollowers Privacy = "followers"
+
+	// PrivacyPrivate accounts can't be followed and
+	// their activity is hidden from existing followers
+	PrivacyPrivate Privacy = "private"
+)
+
+// Validate checks if the privacy is a known setting
+func (p Privacy) Validate() error {
+	switch p {
+	case PrivacyPublic, PrivacyFollowers, PrivacyPrivate:
+		return nil
+	default:
+		return fmt.Errorf("%w: privacy %q, expected %s, %s or %s", ErrInvalidFields, p, PrivacyPublic, PrivacyFollowers, PrivacyPrivate)
+	}
+}
+
+// Follow is Follower following the activity of Followee, following an
+// account with followers privacy only takes effect after the followee
+// accepts it
+type Follow struct {
+	Follower   string     `json:"follower"`
+	Followee   string     `json:"followee"`
+	CreatedAt  time.Time  `json:"created_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+}
+
+func (f Follow) String() string {
+	return fmt.Sprintf("follow of %s by %s", f.Followee, f.Follower)
+}
+
+// Accepted reports whether the followee accepted the follow
+func (f Follow) Accepted() bool {
+	return f.AcceptedAt != nil
+}
+
+// Validate checks if the follow is between two different users
+func (f Follow) Validate() error {
+	if f.Follower == "" || f.Followee == "" {
+		return fmt.Errorf("%w: follow requires a follower and followee", ErrInvalidFields)
+	}
+
+	if f.Follower == f.Followee {
+		return fmt.Errorf("%w: %s can't follow itself", ErrInvalidFields, f.Follower)
+	}
+
+	return nil
+}
+
+// Activity is a finished session of a followed user including the
+// personal records set in the session that still stand
+type Activity struct {
+	Owner      string          `json:"owner"`
+	Session    int             `json:"session"`
+	Name       string          `json:"name"`
+	Date       string          `json:"date"`
+	FinishedAt time.Time       `json:"finished_at"`
+	Records    []record.Record `json:"records"`
+}
+
+// FeedQuery selects a page of the feed, Cursor continues from a previous page
+type FeedQuery struct {
+	Cursor string
+	Limit  int
+}
+
+// Validate checks the query and fills in the default limit
+func (q *FeedQuery) Validate() error {
+	if _, err := parseCursor(q.Cursor); err != nil {
+		return err
+	}
+
+	if q.Limit < 0 {
+		return fmt.Errorf("%w: negative limit", ErrInvalidFields)
+	}
+
+	if q.Limit == 0 {
+		q.Limit = DefaultFeedLimit
+	}
+	q.Limit = min(q.Limit, MaxFeedLimit)
+
+	return nil
+}
+
+// Feed is a page of activities of followed users, the most recent
+// first. Cursor is set when there are more activities to fetch
+type Feed struct {
+	Activities []Activity `json:"activities"`
+	Cursor     string     `json:"cursor,omitempty"`
+}
+
+// cursor is the position in the feed after which the next page starts,
+// activities are ordered by finish time, most recent first
+type cursor struct {
+	FinishedAt time.Time `json:"t"`
+	Owner      string    `json:"o"`
+	Session    int       `json:"s"`
+}
+
+// cursor returns the cursor of the feed after the activity
+func (a Activity) cursor() cursor {
+	return cursor{a.FinishedAt, a.Owner, a.Session}
+}
+
+// String encodes the cursor as an opaque token
+func (c cursor) String() string {
+	bs, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(bs)
+}
+
+// parseCursor decodes a cursor token, an empty
+// token is the cursor of the start of the feed
+func parseCursor(token string) (cursor, error) {
+	if token == "" {
+		return cursor{}, nil
+	}
+
+	bs, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor{}, fmt.Errorf("%w: cursor %q", ErrInvalidFields, token)
+	}
+
+	var c cursor
+	if err := json.Unmarshal(bs, &c); err != nil || c.Owner == "" {
+		return cursor{}, fmt.Errorf("%w: cursor %q", ErrInvalidFields, token)
+	}
+
+	c.FinishedAt = c.FinishedAt.UTC()
+	return c, nil
+}
+
+// start reports whether the cursor is the start of the feed
+func (c cursor) start() bool {
+	return c.Owner == ""
+}
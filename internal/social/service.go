@@ -0,0 +1,291 @@
+package social
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/scrot/musclemem-api/internal/api"
+)
+
+// NewFeedHandler returns the recent finished sessions and personal records
+// of the users the caller follows, most recent first
+// optional cursor and limit query parameters
+func NewFeedHandler(l *slog.Logger, feeds Feeder) http.Handler {
+	l = l.With("handler", "FeedHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = api.Caller(r)
+			params   = r.URL.Query()
+		)
+
+		l := l.With("user", username)
+
+		query := FeedQuery{Cursor: params.Get("cursor")}
+
+		if v := params.Get("limit"); v != "" {
+			var err error
+			if query.Limit, err = strconv.Atoi(v); err != nil {
+				api.WriteInternalError(l, w, err, "invalid limit")
+				return
+			}
+		}
+
+		f, err := feeds.Feed(username, query)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched feed", "count", len(f.Activities))
+
+		if err := api.WriteJSON(w, http.StatusOK, f); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewFollowingHandler returns the users a user follows
+// including follows that are not accepted yet
+// requires {username} path variable
+func NewFollowingHandler(l *slog.Logger, follows Retreiver) http.Handler {
+	l = l.With("handler", "FollowingHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		fs, err := follows.Following(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched following", "count", len(fs))
+
+		if err := api.WriteJSON(w, http.StatusOK, fs); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewFollowHandler follows a user, following a user with followers
+// privacy requires the user to accept and private users can't be followed
+// requires {username} path variable
+// requires json payload {"username": USERNAME}
+func NewFollowHandler(l *slog.Logger, follows Follower) http.Handler {
+	l = l.With("handler", "FollowHandler")
+
+	type Request struct {
+		Username string `json:"username"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		req, err := api.ReadJSON[Request](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		f, err := follows.Follow(username, req.Username)
+		if err != nil {
+			if errors.Is(err, ErrForbidden) {
+				l.Debug(err.Error())
+				api.WriteForbiddenError(w)
+				return
+			}
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s created", f))
+
+		if err := api.WriteJSON(w, http.StatusOK, f); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewUnfollowHandler stops following a user
+// requires {username} and {followee} path variables
+func NewUnfollowHandler(l *slog.Logger, follows Follower) http.Handler {
+	l = l.With("handler", "UnfollowHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			followee = r.PathValue("followee")
+		)
+
+		l := l.With("user", username, "followee", followee)
+
+		f, err := follows.Unfollow(username, followee)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s deleted", f))
+
+		if err := api.WriteJSON(w, http.StatusOK, f); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewFollowersHandler returns the followers of a user
+// including follows that are not accepted yet
+// requires {username} path variable
+func NewFollowersHandler(l *slog.Logger, follows Retreiver) http.Handler {
+	l = l.With("handler", "FollowersHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		fs, err := follows.Followers(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched followers", "count", len(fs))
+
+		if err := api.WriteJSON(w, http.StatusOK, fs); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewAcceptHandler accepts the follow of a follower
+// requires {username} and {follower} path variables
+func NewAcceptHandler(l *slog.Logger, follows Follower) http.Handler {
+	l = l.With("handler", "AcceptHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			follower = r.PathValue("follower")
+		)
+
+		l := l.With("user", username, "follower", follower)
+
+		f, err := follows.Accept(username, follower)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s accepted", f))
+
+		if err := api.WriteJSON(w, http.StatusOK, f); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewRemoveHandler removes a follower or declines its pending follow
+// requires {username} and {follower} path variables
+func NewRemoveHandler(l *slog.Logger, follows Follower) http.Handler {
+	l = l.With("handler", "RemoveHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			follower = r.PathValue("follower")
+		)
+
+		l := l.With("user", username, "follower", follower)
+
+		f, err := follows.Unfollow(follower, username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug(fmt.Sprintf("%s removed", f))
+
+		if err := api.WriteJSON(w, http.StatusOK, f); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewFetchPrivacyHandler returns the privacy of the account of a user
+// requires {username} path variable
+func NewFetchPrivacyHandler(l *slog.Logger, privacies Privacier) http.Handler {
+	l = l.With("handler", "FetchPrivacyHandler")
+
+	type Response struct {
+		Privacy Privacy `json:"privacy"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		p, err := privacies.Privacy(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		if err := api.WriteJSON(w, http.StatusOK, Response{p}); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewSetPrivacyHandler changes the privacy of the account of a user
+// requires {username} path variable
+// requires json payload {"privacy": "public"|"followers"|"private"}
+func NewSetPrivacyHandler(l *slog.Logger, privacies Privacier) http.Handler {
+	l = l.With("handler", "SetPrivacyHandler")
+
+	type Request struct {
+		Privacy Privacy `json:"privacy"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		req, err := api.ReadJSON[Request](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		p, err := privacies.SetPrivacy(username, req.Privacy)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("privacy changed", "privacy", p)
+
+		if err := api.WriteJSON(w, http.StatusOK, Request{p}); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
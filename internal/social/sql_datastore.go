@@ -0,0 +1,386 @@
+package social
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/record"
+	"github.com/scrot/musclemem-api/internal/storage"
+)
+
+const followColumns = `follower, followee, created_at, accepted_at`
+
+type SQLSocialStore struct {
+	*storage.SqlDatastore
+
+	// records returns the records set during the sessions in the feed
+	records record.Retreiver
+
+	// now returns the current time, replaceable for testing
+	now func() time.Time
+}
+
+func NewSQLSocialStore(db *storage.SqlDatastore, records record.Retreiver) *SQLSocialStore {
+	return &SQLSocialStore{db, records, time.Now}
+}
+
+func (ss *SQLSocialStore) timestamp() time.Time {
+	return ss.now().UTC().Truncate(time.Second)
+}
+
+func (ss *SQLSocialStore) Following(follower string) ([]Follow, error) {
+	const stmt = `
+  SELECT ` + followColumns + `
+  FROM follows
+  WHERE follower = {{ . }}
+  ORDER BY followee
+  `
+
+	if follower == "" {
+		return []Follow{}, fmt.Errorf("Following: %w", ErrInvalidFields)
+	}
+
+	follows, err := ss.query(stmt, follower)
+	if err != nil {
+		return []Follow{}, fmt.Errorf("Following: %w", err)
+	}
+
+	return follows, nil
+}
+
+func (ss *SQLSocialStore) Followers(followee string) ([]Follow, error) {
+	const stmt = `
+  SELECT ` + followColumns + `
+  FROM follows
+  WHERE followee = {{ . }}
+  ORDER BY follower
+  `
+
+	if followee == "" {
+		return []Follow{}, fmt.Errorf("Followers: %w", ErrInvalidFields)
+	}
+
+	follows, err := ss.query(stmt, followee)
+	if err != nil {
+		return []Follow{}, fmt.Errorf("Followers: %w", err)
+	}
+
+	return follows, nil
+}
+
+func (ss *SQLSocialStore) Follow(follower string, followee string) (Follow, error) {
+	const stmt = `
+  INSERT INTO follows (` + followColumns + `)
+  VALUES ({{ .Follower }}, {{ .Followee }}, {{ .CreatedAt }}, {{ .AcceptedAt }})
+  `
+
+	f := Follow{Follower: follower, Followee: followee, CreatedAt: ss.timestamp()}
+	if err := f.Validate(); err != nil {
+		return Follow{}, fmt.Errorf("Follow: %w", err)
+	}
+
+	if existing, err := ss.follow(follower, followee); err == nil {
+		return existing, nil
+	} else if !errors.Is(err, ErrNotFound) {
+		return Follow{}, fmt.Errorf("Follow: %w", err)
+	}
+
+	privacy, err := ss.Privacy(followee)
+	if err != nil {
+		return Follow{}, fmt.Errorf("Follow: %w", err)
+	}
+
+	switch privacy {
+	case PrivacyPrivate:
+		return Follow{}, fmt.Errorf("Follow: %s: %w", followee, ErrForbidden)
+	case PrivacyPublic:
+		f.AcceptedAt = &f.CreatedAt
+	}
+
+	q, args, err := ss.CompileStatement(stmt, f)
+	if err != nil {
+		return Follow{}, fmt.Errorf("Follow: compile: %w", err)
+	}
+
+	if _, err := ss.Exec(q, args...); err != nil {
+		return Follow{}, fmt.Errorf("Follow: execute: %w", err)
+	}
+
+	return f, nil
+}
+
+func (ss *SQLSocialStore) Accept(followee string, follower string) (Follow, error) {
+	const stmt = `
+  UPDATE follows
+  SET accepted_at = {{ .AcceptedAt }}
+  WHERE follower = {{ .Follower }} AND followee = {{ .Followee }}
+  `
+
+	f, err := ss.follow(follower, followee)
+	if err != nil {
+		return Follow{}, fmt.Errorf("Accept: %w", err)
+	}
+
+	if f.Accepted() {
+		return f, nil
+	}
+
+	accepted := ss.timestamp()
+	f.AcceptedAt = &accepted
+
+	q, args, err := ss.CompileStatement(stmt, f)
+	if err != nil {
+		return Follow{}, fmt.Errorf("Accept: compile: %w", err)
+	}
+
+	if _, err := ss.Exec(q, args...); err != nil {
+		return Follow{}, fmt.Errorf("Accept: execute: %w", err)
+	}
+
+	return f, nil
+}
+
+func (ss *SQLSocialStore) Unfollow(follower string, followee string) (Follow, error) {
+	const stmt = `
+  DELETE FROM follows
+  WHERE follower = {{ .Follower }} AND followee = {{ .Followee }}
+  `
+
+	f, err := ss.follow(follower, followee)
+	if err != nil {
+		return Follow{}, fmt.Errorf("Unfollow: %w", err)
+	}
+
+	q, args, err := ss.CompileStatement(stmt, f)
+	if err != nil {
+		return Follow{}, fmt.Errorf("Unfollow: compile: %w", err)
+	}
+
+	if _, err := ss.Exec(q, args...); err != nil {
+		return Follow{}, fmt.Errorf("Unfollow: execute: %w", err)
+	}
+
+	return f, nil
+}
+
+func (ss *SQLSocialStore) Privacy(username string) (Privacy, error) {
+	const stmt = `
+  SELECT privacy
+  FROM users
+  WHERE username = {{ . }}
+  `
+
+	if username == "" {
+		return "", fmt.Errorf("Privacy: %w", ErrInvalidFields)
+	}
+
+	q, args, err := ss.CompileStatement(stmt, username)
+	if err != nil {
+		return "", fmt.Errorf("Privacy: compile: %w", err)
+	}
+
+	var privacy Privacy
+	if err := ss.QueryRow(q, args...).Scan(&privacy); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("Privacy: user %s: %w", username, ErrNotFound)
+		}
+		return "", fmt.Errorf("Privacy: query: %w", err)
+	}
+
+	return privacy, nil
+}
+
+func (ss *SQLSocialStore) SetPrivacy(username string, privacy Privacy) (Privacy, error) {
+	const stmt = `
+  UPDATE users
+  SET privacy = {{ .Privacy }}
+  WHERE username = {{ .Username }}
+  `
+
+	if err := privacy.Validate(); err != nil {
+		return "", fmt.Errorf("SetPrivacy: %w", err)
+	}
+
+	if _, err := ss.Privacy(username); err != nil {
+		return "", fmt.Errorf("SetPrivacy: %w", err)
+	}
+
+	data := struct {
+		Username string
+		Privacy  Privacy
+	}{username, privacy}
+
+	q, args, err := ss.CompileStatement(stmt, data)
+	if err != nil {
+		return "", fmt.Errorf("SetPrivacy: compile: %w", err)
+	}
+
+	if _, err := ss.Exec(q, args...); err != nil {
+		return "", fmt.Errorf("SetPrivacy: execute: %w", err)
+	}
+
+	return privacy, nil
+}
+
+func (ss *SQLSocialStore) Feed(username string, query FeedQuery) (Feed, error) {
+	// the feed is assembled when read from the sessions of the followed users
+	// that are visible to the follower, ordered by the time they finished
+	const stmt = `
+  SELECT s.owner, s.session_index, s.name, s.performed_on, s.finished_at
+  FROM follows f
+  JOIN users u ON u.username = f.followee
+  JOIN sessions s ON s.owner = f.followee
+  WHERE f.follower = {{ .Username }} AND s.finished_at IS NOT NULL
+    AND (u.privacy = 'public' OR (u.privacy = 'followers' AND f.accepted_at IS NOT NULL))
+    {{ if .After }}
+    AND (s.finished_at < {{ .FinishedAt }}
+      OR (s.finished_at = {{ .FinishedAt }} AND s.owner > {{ .Owner }})
+      OR (s.finished_at = {{ .FinishedAt }} AND s.owner = {{ .Owner }} AND s.session_index < {{ .Session }}))
+    {{ end }}
+  ORDER BY s.finished_at DESC, s.owner, s.session_index DESC
+  LIMIT {{ .Limit }}
+  `
+
+	if username == "" {
+		return Feed{}, fmt.Errorf("Feed: %w", ErrInvalidFields)
+	}
+
+	if err := query.Validate(); err != nil {
+		return Feed{}, fmt.Errorf("Feed: %w", err)
+	}
+
+	after, err := parseCursor(query.Cursor)
+	if err != nil {
+		return Feed{}, fmt.Errorf("Feed: %w", err)
+	}
+
+	data := struct {
+		cursor
+		Username string
+		After    bool
+		Limit    int
+	}{after, username, !after.start(), query.Limit + 1}
+
+	q, args, err := ss.CompileStatement(stmt, data)
+	if err != nil {
+		return Feed{}, fmt.Errorf("Feed: compile: %w", err)
+	}
+
+	rows, err := ss.Query(q, args...)
+	if err != nil {
+		return Feed{}, fmt.Errorf("Feed: query: %w", err)
+	}
+	defer rows.Close()
+
+	activities := []Activity{}
+	for rows.Next() {
+		var a Activity
+		if err := rows.Scan(&a.Owner, &a.Session, &a.Name, &a.Date, &a.FinishedAt); err != nil {
+			return Feed{}, fmt.Errorf("Feed: scan: %w", err)
+		}
+
+		a.FinishedAt = a.FinishedAt.UTC()
+		activities = append(activities, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return Feed{}, fmt.Errorf("Feed: rows: %w", err)
+	}
+
+	// the extra activity only signals there is a next page
+	feed := Feed{Activities: activities}
+	if len(activities) > query.Limit {
+		feed.Activities = activities[:query.Limit]
+		feed.Cursor = feed.Activities[query.Limit-1].cursor().String()
+	}
+
+	refs := make([]record.SessionRef, len(feed.Activities))
+	for i, a := range feed.Activities {
+		refs[i] = record.SessionRef{Owner: a.Owner, Session: a.Session}
+	}
+
+	records, err := ss.records.BySessions(refs)
+	if err != nil {
+		return Feed{}, fmt.Errorf("Feed: %w", err)
+	}
+
+	for i, ref := range refs {
+		feed.Activities[i].Records = records[ref]
+		if feed.Activities[i].Records == nil {
+			feed.Activities[i].Records = []record.Record{}
+		}
+	}
+
+	return feed, nil
+}
+
+// follow returns the follow of followee by follower,
+// it returns an ErrNotFound error if there is none
+func (ss *SQLSocialStore) follow(follower string, followee string) (Follow, error) {
+	const stmt = `
+  SELECT ` + followColumns + `
+  FROM follows
+  WHERE follower = {{ .Follower }} AND followee = {{ .Followee }}
+  `
+
+	if follower == "" || followee == "" {
+		return Follow{}, ErrInvalidFields
+	}
+
+	data := struct {
+		Follower string
+		Followee string
+	}{follower, followee}
+
+	follows, err := ss.query(stmt, data)
+	if err != nil {
+		return Follow{}, err
+	}
+
+	if len(follows) == 0 {
+		return Follow{}, fmt.Errorf("follow of %s by %s: %w", followee, follower, ErrNotFound)
+	}
+
+	return follows[0], nil
+}
+
+// query compiles and executes stmt returning the scanned follows
+func (ss *SQLSocialStore) query(stmt string, data any) ([]Follow, error) {
+	q, args, err := ss.CompileStatement(stmt, data)
+	if err != nil {
+		return nil, fmt.Errorf("compile: %w", err)
+	}
+
+	rows, err := ss.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	follows := []Follow{}
+	for rows.Next() {
+		var (
+			f        Follow
+			accepted sql.NullTime
+		)
+
+		if err := rows.Scan(&f.Follower, &f.Followee, &f.CreatedAt, &accepted); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		f.CreatedAt = f.CreatedAt.UTC()
+		if accepted.Valid {
+			t := accepted.Time.UTC()
+			f.AcceptedAt = &t
+		}
+		follows = append(follows, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return follows, nil
+}
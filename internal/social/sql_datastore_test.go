@@ -0,0 +1,206 @@
+package social
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/record"
+	"github.com/scrot/musclemem-api/internal/session/sessiontest"
+	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/user"
+)
+
+func TestFollow(t *testing.T) {
+	socials, flush := mockSocialStore(t)
+	defer flush()
+
+	if _, err := socials.Follow("user", "user"); !errors.Is(err, ErrInvalidFields) {
+		t.Errorf("want error %v following yourself but got %v", ErrInvalidFields, err)
+	}
+
+	if _, err := socials.Follow("user", "unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v following unknown user but got %v", ErrNotFound, err)
+	}
+
+	f, err := socials.Follow("user", "public")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !f.Accepted() {
+		t.Errorf("want follow of public user accepted but got %+v", f)
+	}
+
+	f, err = socials.Follow("user", "friends")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Accepted() {
+		t.Errorf("want follow of user with followers privacy pending but got %+v", f)
+	}
+
+	if _, err := socials.Accept("friends", "user"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := socials.Follow("user", "private"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("want error %v following private user but got %v", ErrForbidden, err)
+	}
+
+	following, err := socials.Following("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(following) != 2 || !following[0].Accepted() || !following[1].Accepted() {
+		t.Errorf("want 2 accepted follows but got %+v", following)
+	}
+
+	if _, err := socials.Unfollow("user", "public"); err != nil {
+		t.Fatal(err)
+	}
+
+	if followers, _ := socials.Followers("public"); len(followers) != 0 {
+		t.Errorf("want no followers after unfollowing but got %+v", followers)
+	}
+}
+
+func TestFeed(t *testing.T) {
+	socials, flush := mockSocialStore(t)
+	defer flush()
+
+	const recordStmt = `
+  INSERT INTO records (owner, exercise, record_type, name, weight, value, session, performed_on)
+  VALUES ('public', 'squat', 'heaviest_weight', 'Squat', 100, 100, 2, '2026-10-02')
+  `
+
+	// sessions of public and friends finish at the same time on the first day
+	for _, s := range []sessiontest.Session{
+		{Owner: "public", Index: 1, StartedAt: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)},
+		{Owner: "friends", Index: 1, StartedAt: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)},
+		{Owner: "public", Index: 2, StartedAt: time.Date(2026, 10, 2, 9, 0, 0, 0, time.UTC)},
+		{Owner: "friends", Index: 2, StartedAt: time.Date(2026, 10, 3, 9, 0, 0, 0, time.UTC)},
+		{Owner: "private", Index: 1, StartedAt: time.Date(2026, 10, 4, 9, 0, 0, 0, time.UTC)},
+	} {
+		s.FinishedAt = &s.StartedAt
+		sessiontest.Log(t, socials.SqlDatastore, s)
+	}
+
+	if _, err := socials.Exec(recordStmt); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := socials.SetPrivacy("private", PrivacyPublic); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, followee := range []string{"public", "friends", "private"} {
+		if _, err := socials.Follow("user", followee); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// turning private hides the activity from existing followers
+	if _, err := socials.SetPrivacy("private", PrivacyPrivate); err != nil {
+		t.Fatal(err)
+	}
+
+	feed, err := socials.Feed("user", FeedQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(feed.Activities) != 2 || feed.Cursor != "" {
+		t.Fatalf("want only sessions of public user before accepting but got %+v", feed)
+	}
+
+	if len(feed.Activities[0].Records) != 1 || feed.Activities[0].Records[0].Value != 100 {
+		t.Errorf("want record set during session 2 but got %+v", feed.Activities[0].Records)
+	}
+
+	if rs := feed.Activities[1].Records; rs == nil || len(rs) != 0 {
+		t.Errorf("want no records set during session 1 but got %+v", rs)
+	}
+
+	if _, err := socials.Accept("friends", "user"); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		query = FeedQuery{Limit: 2}
+		got   []Activity
+	)
+
+	for range 3 {
+		feed, err := socials.Feed("user", query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got = append(got, feed.Activities...)
+		if feed.Cursor == "" {
+			break
+		}
+		query.Cursor = feed.Cursor
+	}
+
+	want := []struct {
+		owner   string
+		session int
+	}{{"friends", 2}, {"public", 2}, {"friends", 1}, {"public", 1}}
+
+	if len(got) != len(want) {
+		t.Fatalf("want %d activities but got %+v", len(want), got)
+	}
+
+	for i, w := range want {
+		if got[i].Owner != w.owner || got[i].Session != w.session {
+			t.Errorf("want activity %d session %d of %s but got %+v", i, w.session, w.owner, got[i])
+		}
+	}
+
+	if _, err := socials.Feed("user", FeedQuery{Cursor: "invalid"}); !errors.Is(err, ErrInvalidFields) {
+		t.Errorf("want error %v on invalid cursor but got %v", ErrInvalidFields, err)
+	}
+}
+
+func mockSocialStore(t *testing.T) (*SQLSocialStore, func()) {
+	t.Helper()
+
+	config := storage.DatastoreConfig{
+		DatabaseURL:   "file://test.db?cache=shared&mode=memory",
+		MigrationPath: "migrations",
+		Overwrite:     false,
+	}
+
+	store, err := storage.NewSqlDatastore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users := user.NewSQLUserStore(store)
+	for _, username := range []string{"user", "public", "friends", "private"} {
+		if _, err := users.New(username, username+"@gmail.com", "secret"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	flush := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal()
+		}
+	}
+
+	socials := NewSQLSocialStore(store, record.NewSQLRecordStore(store))
+	socials.now = func() time.Time { return time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC) }
+
+	for username, privacy := range map[string]Privacy{"friends": PrivacyFollowers, "private": PrivacyPrivate} {
+		if _, err := socials.SetPrivacy(username, privacy); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return socials, flush
+}
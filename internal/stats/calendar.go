@@ -0,0 +1,128 @@
+package stats
+
+import (
+	"math"
+	"time"
+)
+
+// MonthLayout is the layout of the month of a calendar
+const MonthLayout = "2006-01"
+
+// Calendar contains the sessions performed each day of a month,
+// the weekly streaks and the adherence to the weekly schedule
+type Calendar struct {
+	Month     string     `json:"month"`
+	Days      []Day      `json:"days"`
+	Streaks   Streaks    `json:"streaks"`
+	Adherence *Adherence `json:"adherence,omitempty"`
+}
+
+// Day contains the sessions performed on a date
+type Day struct {
+	Date     string           `json:"date"`
+	Sessions []SessionSummary `json:"sessions"`
+}
+
+// SessionSummary summarizes a session for the calendar
+type SessionSummary struct {
+	Index    int     `json:"index"`
+	Name     string  `json:"name"`
+	Finished bool    `json:"finished"`
+	Sets     int     `json:"sets"`
+	Tonnage  float64 `json:"tonnage"`
+}
+
+// Streaks contains the number of consecutive weeks the sessions planned by
+// the weekly schedule were performed, the current streak includes the
+// current week once they are
+type Streaks struct {
+	Current int `json:"current"`
+	Longest int `json:"longest"`
+}
+
+// Adherence is the percentage of planned sessions that were performed in
+// the weeks starting in the month up until the current week, Target is the
+// number of sessions the weekly schedule plans each week. Sessions beyond
+// the target don't make up for missed sessions
+type Adherence struct {
+	Target    int     `json:"target"`
+	Planned   int     `json:"planned"`
+	Performed int     `json:"performed"`
+	Percent   float64 `json:"percent"`
+}
+
+// weekStart returns the monday of the week of t
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+// sessionsPerWeek counts the sessions performed each week keyed by monday
+func sessionsPerWeek(dates []time.Time) map[time.Time]int {
+	weeks := make(map[time.Time]int)
+	for _, d := range dates {
+		weeks[weekStart(d)]++
+	}
+	return weeks
+}
+
+// streaks returns the weekly streaks given the sessions per week, a week
+// counts when target sessions were performed, at least one without a
+// weekly schedule
+func streaks(weeks map[time.Time]int, target int, today time.Time) Streaks {
+	target = max(target, 1)
+
+	var (
+		s       Streaks
+		current = weekStart(today)
+		first   = current
+	)
+
+	for w := range weeks {
+		if w.Before(first) {
+			first = w
+		}
+	}
+
+	run := 0
+	for w := first; !w.After(current); w = w.AddDate(0, 0, 7) {
+		switch {
+		case weeks[w] >= target:
+			run++
+		case w.Equal(current):
+			// the current week is still in progress
+		default:
+			run = 0
+		}
+		s.Longest = max(s.Longest, run)
+	}
+	s.Current = run
+
+	return s
+}
+
+// adherence returns the adherence to the target for the weeks starting in
+// the month of first up until the current week, nil without a weekly schedule
+func adherence(weeks map[time.Time]int, target int, first time.Time, today time.Time) *Adherence {
+	if target <= 0 {
+		return nil
+	}
+
+	a := Adherence{Target: target}
+
+	w := weekStart(first)
+	if w.Before(first) {
+		w = w.AddDate(0, 0, 7)
+	}
+
+	for ; w.Month() == first.Month() && !w.After(weekStart(today)); w = w.AddDate(0, 0, 7) {
+		a.Planned += target
+		a.Performed += min(weeks[w], target)
+	}
+
+	if a.Planned > 0 {
+		a.Percent = math.Round(float64(a.Performed)/float64(a.Planned)*1000) / 10
+	}
+
+	return &a
+}
@@ -0,0 +1,22 @@
+package stats
+
+import "errors"
+
+var ErrInvalidFields = errors.New("contains invalid fields")
+
+// StatsStore represents the training statistics repository
+type StatsStore interface {
+	Retreiver
+}
+
+// Retreiver implementations allow for statistics to be queried
+type Retreiver interface {
+	// Stats aggregates the sets logged by owner into buckets,
+	// the oldest bucket first
+	Stats(owner string, query Query) (Stats, error)
+
+	// Calendar returns the sessions performed each day of month, formatted as
+	// MonthLayout, the weekly streaks and the adherence to the weekly schedule
+	// of owner. An empty month returns the current month
+	Calendar(owner string, month string) (Calendar, error)
+}
@@ -0,0 +1,89 @@
+package stats
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/storage"
+)
+
+// DateLayout is the layout of the dates statistics are bucketed by
+const DateLayout = time.DateOnly
+
+// HardSetRPE is the minimum rate of perceived exertion of a hard set,
+// completed sets without RPE are counted as hard sets as well
+const HardSetRPE = 7
+
+// Bucket is the period the statistics are aggregated over
+type Bucket = storage.Unit
+
+const (
+	BucketDay   = storage.UnitDay
+	BucketWeek  = storage.UnitWeek
+	BucketMonth = storage.UnitMonth
+)
+
+// Grouping determines how the statistics within a bucket are grouped
+type Grouping string
+
+const (
+	GroupNone     Grouping = ""
+	GroupExercise Grouping = "exercise"
+	GroupMuscle   Grouping = "muscle"
+)
+
+// Query selects the period, bucket and grouping of the statistics,
+// From and To are optional inclusive dates
+type Query struct {
+	Bucket  Bucket   `json:"bucket"`
+	GroupBy Grouping `json:"group_by,omitempty"`
+	From    string   `json:"from,omitempty"`
+	To      string   `json:"to,omitempty"`
+}
+
+// Validate checks the query and defaults the bucket to weeks
+func (q *Query) Validate() error {
+	switch q.Bucket {
+	case "":
+		q.Bucket = BucketWeek
+	case BucketDay, BucketWeek, BucketMonth:
+	default:
+		return fmt.Errorf("%w: unknown bucket %q", ErrInvalidFields, q.Bucket)
+	}
+
+	switch q.GroupBy {
+	case GroupNone, GroupExercise, GroupMuscle:
+	default:
+		return fmt.Errorf("%w: unknown grouping %q", ErrInvalidFields, q.GroupBy)
+	}
+
+	for _, d := range []string{q.From, q.To} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse(DateLayout, d); err != nil {
+			return fmt.Errorf("%w: date %q, expected %s", ErrInvalidFields, d, DateLayout)
+		}
+	}
+
+	return nil
+}
+
+// Stats are the training statistics of a user
+type Stats struct {
+	Query
+	Points []Point `json:"points"`
+}
+
+// Point contains the metrics of a bucket, Date is the first day of the
+// bucket and Group the exercise or primary muscle when grouped. Sessions
+// only counts sessions with logged sets and AverageRPE only sets with an RPE
+type Point struct {
+	Date       string  `json:"date"`
+	Group      string  `json:"group,omitempty"`
+	Sessions   int     `json:"sessions"`
+	Sets       int     `json:"sets"`
+	HardSets   int     `json:"hard_sets"`
+	Tonnage    float64 `json:"tonnage"`
+	AverageRPE float64 `json:"average_rpe,omitempty"`
+}
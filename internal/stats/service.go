@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/scrot/musclemem-api/internal/api"
+)
+
+// NewFetchHandler returns the training statistics of a user
+// requires {username} path variable, optional bucket (day, week, month),
+// group_by (exercise, muscle), from and to query parameters
+func NewFetchHandler(l *slog.Logger, stats Retreiver) http.Handler {
+	l = l.With("handler", "FetchHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			params   = r.URL.Query()
+		)
+
+		query := Query{
+			Bucket:  Bucket(params.Get("bucket")),
+			GroupBy: Grouping(params.Get("group_by")),
+			From:    params.Get("from"),
+			To:      params.Get("to"),
+		}
+
+		l := l.With("user", username, "bucket", query.Bucket, "group_by", query.GroupBy)
+
+		s, err := stats.Stats(username, query)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched user stats", "points", len(s.Points))
+
+		if err := api.WriteJSON(w, http.StatusOK, s); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+// NewCalendarHandler returns the calendar of a month including the weekly
+// streaks and adherence to the weekly schedule
+// requires {username} path variable, optional month query parameter
+// formatted as YYYY-MM, defaults to the current month
+func NewCalendarHandler(l *slog.Logger, stats Retreiver) http.Handler {
+	l = l.With("handler", "CalendarHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			month    = r.URL.Query().Get("month")
+		)
+
+		l := l.With("user", username, "month", month)
+
+		c, err := stats.Calendar(username, month)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("fetched user calendar", "streak", c.Streaks.Current)
+
+		if err := api.WriteJSON(w, http.StatusOK, c); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
@@ -0,0 +1,252 @@
+package stats
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/body"
+	"github.com/scrot/musclemem-api/internal/schedule"
+	"github.com/scrot/musclemem-api/internal/storage"
+)
+
+type SQLStatsStore struct {
+	*storage.SqlDatastore
+
+	// now returns the current time, replaceable for testing
+	now func() time.Time
+}
+
+func NewSQLStatsStore(db *storage.SqlDatastore) *SQLStatsStore {
+	return &SQLStatsStore{db, time.Now}
+}
+
+// load is the load moved per repetition of set t of session exercise x,
+// including the body weight of the owner of session s for bodyweight exercises
+var load = body.LoadExpr("x.kind", "t.weight", body.WeightExpr("s.owner", "s.performed_on"))
+
+// groupings contains the expression each grouping groups by
+var groupings = map[Grouping]string{
+	GroupNone:     `''`,
+	GroupExercise: `CASE WHEN x.catalog <> '' THEN x.catalog ELSE LOWER(x.name) END`,
+	GroupMuscle:   `COALESCE(m.muscle, '')`,
+}
+
+func (ss *SQLStatsStore) Stats(owner string, query Query) (Stats, error) {
+	const stmt = `
+  SELECT %s AS bucket, %s AS label,
+    COUNT(DISTINCT s.session_index),
+    COUNT(*),
+    SUM(CASE WHEN t.completed AND (t.rpe = 0 OR t.rpe >= {{ .HardSetRPE }}) THEN 1 ELSE 0 END),
+    COALESCE(SUM((%s) * t.repetitions), 0),
+    AVG(CASE WHEN t.rpe > 0 THEN t.rpe END)
+  FROM sessions s
+  JOIN session_exercises x ON x.owner = s.owner AND x.session = s.session_index
+  JOIN session_sets t
+    ON t.owner = x.owner AND t.session = x.session AND t.exercise = x.exercise_index AND NOT t.warmup
+  {{ if .Muscles }}
+  LEFT JOIN catalog_muscles m
+    ON m.role = 'primary' AND ((m.owner = '' AND m.slug = x.catalog) OR m.owner || '/' || m.slug = x.catalog)
+  {{ end }}
+  WHERE s.owner = {{ .Owner }}
+    {{ if .From }}AND s.performed_on >= {{ .From }}{{ end }}
+    {{ if .To }}AND s.performed_on <= {{ .To }}{{ end }}
+  GROUP BY bucket, label
+  ORDER BY bucket, label
+  `
+
+	if owner == "" {
+		return Stats{}, fmt.Errorf("Stats: %w", ErrInvalidFields)
+	}
+
+	if err := query.Validate(); err != nil {
+		return Stats{}, fmt.Errorf("Stats: %w", err)
+	}
+
+	bucket, err := ss.Dialect().TruncateDate("s.performed_on", query.Bucket)
+	if err != nil {
+		return Stats{}, fmt.Errorf("Stats: %w", err)
+	}
+
+	data := struct {
+		Owner string
+		Query
+		HardSetRPE int
+		Muscles    bool
+	}{owner, query, HardSetRPE, query.GroupBy == GroupMuscle}
+
+	q, args, err := ss.CompileStatement(fmt.Sprintf(stmt, bucket, groupings[query.GroupBy], load), data)
+	if err != nil {
+		return Stats{}, fmt.Errorf("Stats: compile: %w", err)
+	}
+
+	rows, err := ss.Query(q, args...)
+	if err != nil {
+		return Stats{}, fmt.Errorf("Stats: query: %w", err)
+	}
+	defer rows.Close()
+
+	stats := Stats{Query: query, Points: []Point{}}
+	for rows.Next() {
+		var (
+			p   Point
+			rpe sql.NullFloat64
+		)
+
+		if err := rows.Scan(&p.Date, &p.Group, &p.Sessions, &p.Sets, &p.HardSets, &p.Tonnage, &rpe); err != nil {
+			return Stats{}, fmt.Errorf("Stats: scan: %w", err)
+		}
+
+		p.AverageRPE = math.Round(rpe.Float64*10) / 10
+		stats.Points = append(stats.Points, p)
+	}
+
+	return stats, nil
+}
+
+func (ss *SQLStatsStore) Calendar(owner string, month string) (Calendar, error) {
+	const (
+		sessionsStmt = `
+    SELECT s.performed_on, s.session_index, s.name, s.finished_at IS NOT NULL,
+      COUNT(t.set_index), COALESCE(SUM((%s) * t.repetitions), 0)
+    FROM sessions s
+    LEFT JOIN session_sets t ON t.owner = s.owner AND t.session = s.session_index AND NOT t.warmup
+    LEFT JOIN session_exercises x ON x.owner = t.owner AND x.session = t.session AND x.exercise_index = t.exercise
+    WHERE s.owner = {{ .Owner }} AND s.performed_on >= {{ .From }} AND s.performed_on <= {{ .To }}
+    GROUP BY s.performed_on, s.session_index, s.name, s.finished_at
+    ORDER BY s.performed_on, s.session_index
+    `
+
+		datesStmt = `
+    SELECT performed_on
+    FROM sessions
+    WHERE owner = {{ .Owner }}
+    `
+	)
+
+	if owner == "" {
+		return Calendar{}, fmt.Errorf("Calendar: %w", ErrInvalidFields)
+	}
+
+	today := ss.now().UTC()
+	if month == "" {
+		month = today.Format(MonthLayout)
+	}
+
+	first, err := time.Parse(MonthLayout, month)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("Calendar: %w: month %q, expected %s", ErrInvalidFields, month, MonthLayout)
+	}
+	last := first.AddDate(0, 1, -1)
+
+	data := struct {
+		Owner string
+		From  string
+		To    string
+	}{owner, first.Format(DateLayout), last.Format(DateLayout)}
+
+	q, args, err := ss.CompileStatement(fmt.Sprintf(sessionsStmt, load), data)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("Calendar: compile: %w", err)
+	}
+
+	rows, err := ss.Query(q, args...)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("Calendar: query: %w", err)
+	}
+	defer rows.Close()
+
+	c := Calendar{Month: month, Days: make([]Day, 0, last.Day())}
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		c.Days = append(c.Days, Day{Date: d.Format(DateLayout), Sessions: []SessionSummary{}})
+	}
+
+	for rows.Next() {
+		var (
+			date string
+			s    SessionSummary
+		)
+
+		if err := rows.Scan(&date, &s.Index, &s.Name, &s.Finished, &s.Sets, &s.Tonnage); err != nil {
+			return Calendar{}, fmt.Errorf("Calendar: scan: %w", err)
+		}
+
+		d, err := time.Parse(DateLayout, date)
+		if err != nil {
+			return Calendar{}, fmt.Errorf("Calendar: parse date: %w", err)
+		}
+
+		day := &c.Days[d.Day()-1]
+		day.Sessions = append(day.Sessions, s)
+	}
+
+	if err := rows.Close(); err != nil {
+		return Calendar{}, fmt.Errorf("Calendar: close: %w", err)
+	}
+
+	q, args, err = ss.CompileStatement(datesStmt, data)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("Calendar: compile dates: %w", err)
+	}
+
+	rows, err = ss.Query(q, args...)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("Calendar: query dates: %w", err)
+	}
+	defer rows.Close()
+
+	var dates []time.Time
+	for rows.Next() {
+		var date string
+		if err := rows.Scan(&date); err != nil {
+			return Calendar{}, fmt.Errorf("Calendar: scan date: %w", err)
+		}
+
+		d, err := time.Parse(DateLayout, date)
+		if err != nil {
+			return Calendar{}, fmt.Errorf("Calendar: parse date: %w", err)
+		}
+		dates = append(dates, d)
+	}
+
+	target, err := ss.planned(owner)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("Calendar: %w", err)
+	}
+
+	weeks := sessionsPerWeek(dates)
+	c.Streaks = streaks(weeks, target, today)
+	c.Adherence = adherence(weeks, target, first, today)
+
+	return c, nil
+}
+
+// planned returns the number of sessions the weekly schedule of owner
+// plans each week, 0 without a schedule or a rotation that isn't tied
+// to days of the week
+func (ss *SQLStatsStore) planned(owner string) (int, error) {
+	const stmt = `
+  SELECT COUNT(t.slot_index)
+  FROM schedules s
+  JOIN schedule_slots t ON t.owner = s.owner
+  WHERE s.owner = {{ .Owner }} AND s.mode = {{ .Mode }}
+  `
+
+	data := struct {
+		Owner string
+		Mode  schedule.Mode
+	}{owner, schedule.ModeWeekly}
+
+	q, args, err := ss.CompileStatement(stmt, data)
+	if err != nil {
+		return 0, fmt.Errorf("planned: compile: %w", err)
+	}
+
+	var count int
+	if err := ss.QueryRow(q, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("planned: query: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,185 @@
+package stats
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scrot/musclemem-api/internal/catalog"
+	"github.com/scrot/musclemem-api/internal/schedule"
+	"github.com/scrot/musclemem-api/internal/session/sessiontest"
+	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/user"
+	"github.com/scrot/musclemem-api/internal/workout"
+)
+
+func TestStats(t *testing.T) {
+	stats, store, flush := mockStatsStore(t)
+	defer flush()
+
+	// a set is {weight, repetitions, rpe}
+	logSession(t, store, 1, "2026-10-12", "barbell-bench-press", [][3]float64{{100, 5, 8}, {100, 5, 6}})
+	logSession(t, store, 2, "2026-10-14", "barbell-back-squat", [][3]float64{{120, 5, 0}})
+	logSession(t, store, 3, "2026-10-19", "barbell-bench-press", [][3]float64{{102.5, 5, 9}})
+
+	cs := []struct {
+		name  string
+		query Query
+		want  []Point
+	}{
+		{
+			"weekly",
+			Query{Bucket: BucketWeek},
+			[]Point{
+				{Date: "2026-10-12", Sessions: 2, Sets: 3, HardSets: 2, Tonnage: 1600, AverageRPE: 7},
+				{Date: "2026-10-19", Sessions: 1, Sets: 1, HardSets: 1, Tonnage: 512.5, AverageRPE: 9},
+			},
+		},
+		{
+			"monthlyByExercise",
+			Query{Bucket: BucketMonth, GroupBy: GroupExercise},
+			[]Point{
+				{Date: "2026-10-01", Group: "barbell-back-squat", Sessions: 1, Sets: 1, HardSets: 1, Tonnage: 600},
+				{Date: "2026-10-01", Group: "barbell-bench-press", Sessions: 2, Sets: 3, HardSets: 2, Tonnage: 1512.5, AverageRPE: 7.7},
+			},
+		},
+		{
+			"dailyByMuscleFrom",
+			Query{Bucket: BucketDay, GroupBy: GroupMuscle, From: "2026-10-14"},
+			[]Point{
+				{Date: "2026-10-14", Group: "glutes", Sessions: 1, Sets: 1, HardSets: 1, Tonnage: 600},
+				{Date: "2026-10-14", Group: "quads", Sessions: 1, Sets: 1, HardSets: 1, Tonnage: 600},
+				{Date: "2026-10-19", Group: "chest", Sessions: 1, Sets: 1, HardSets: 1, Tonnage: 512.5, AverageRPE: 9},
+			},
+		},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := stats.Stats("user", c.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(got.Points) != len(c.want) {
+				t.Fatalf("want %d points but got %v", len(c.want), got.Points)
+			}
+
+			for i, want := range c.want {
+				if got.Points[i] != want {
+					t.Errorf("want %+v but got %+v", want, got.Points[i])
+				}
+			}
+		})
+	}
+
+	if _, err := stats.Stats("user", Query{Bucket: "year"}); !errors.Is(err, ErrInvalidFields) {
+		t.Errorf("want error %v but got %v", ErrInvalidFields, err)
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	stats, store, flush := mockStatsStore(t)
+	defer flush()
+
+	stats.(*SQLStatsStore).now = func() time.Time {
+		return time.Date(2026, 10, 21, 12, 0, 0, 0, time.UTC)
+	}
+
+	// the week of october 12 misses the two scheduled sessions
+	dates := []string{"2026-09-28", "2026-09-30", "2026-10-05", "2026-10-07", "2026-10-12", "2026-10-19", "2026-10-20"}
+	for i, d := range dates {
+		logSession(t, store, i+1, d, "barbell-bench-press", [][3]float64{{100, 5, 8}})
+	}
+
+	unscheduled, err := stats.Calendar("user", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if unscheduled.Adherence != nil || unscheduled.Streaks.Current != 4 {
+		t.Errorf("want a 4 week streak without adherence but got %+v", unscheduled)
+	}
+
+	workouts := workout.NewSQLWorkoutStore(store)
+	for _, name := range []string{"push", "pull"} {
+		if _, err := workouts.New("user", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	weekly := schedule.Schedule{Mode: schedule.ModeWeekly, Slots: []schedule.Slot{{Day: "monday", Workout: 1}, {Day: "wednesday", Workout: 2}}}
+	if _, err := schedule.NewSQLScheduleStore(store).Set("user", weekly); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := stats.Calendar("user", "2026-10")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(c.Days) != 31 || len(c.Days[4].Sessions) != 1 || c.Days[4].Sessions[0].Tonnage != 500 {
+		t.Errorf("want 31 days with a session on october 5 but got %v", c.Days)
+	}
+
+	if c.Streaks != (Streaks{Current: 1, Longest: 2}) {
+		t.Errorf("want current streak 1 and longest 2 but got %+v", c.Streaks)
+	}
+
+	want := Adherence{Target: 2, Planned: 6, Performed: 5, Percent: 83.3}
+	if c.Adherence == nil || *c.Adherence != want {
+		t.Errorf("want adherence %+v but got %+v", want, c.Adherence)
+	}
+
+	if _, err := stats.Calendar("user", "october"); !errors.Is(err, ErrInvalidFields) {
+		t.Errorf("want error %v but got %v", ErrInvalidFields, err)
+	}
+}
+
+// logSession logs a session with a single catalog exercise and its completed sets
+func logSession(t *testing.T, store *storage.SqlDatastore, session int, date string, catalog string, sets [][3]float64) {
+	t.Helper()
+
+	x := sessiontest.Exercise{Name: catalog, Catalog: catalog}
+	for _, s := range sets {
+		x.Sets = append(x.Sets, sessiontest.Set{Weight: s[0], Repetitions: int(s[1]), RPE: s[2], Completed: true})
+	}
+
+	sessiontest.Log(t, store, sessiontest.Session{Owner: "user", Index: session, Date: date, Exercises: []sessiontest.Exercise{x}})
+}
+
+func mockStatsStore(t *testing.T) (StatsStore, *storage.SqlDatastore, func()) {
+	t.Helper()
+
+	config := storage.DatastoreConfig{
+		DatabaseURL:   "file://test.db?cache=shared&mode=memory",
+		MigrationPath: "migrations",
+		Overwrite:     false,
+	}
+
+	store, err := storage.NewSqlDatastore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := user.NewSQLUserStore(store).New("user", "test@gmail.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	library, err := catalog.Library()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := catalog.NewSQLCatalogStore(store).Seed(library); err != nil {
+		t.Fatal(err)
+	}
+
+	flush := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal()
+		}
+	}
+
+	return NewSQLStatsStore(store), store, flush
+}
@@ -0,0 +1,47 @@
+package storage
+
+import "fmt"
+
+// Dialect is the SQL dialect of a database, statements are written in the
+// common subset of the dialects, Dialect provides the expressions that differ
+type Dialect string
+
+const (
+	DialectSQLite   Dialect = "sqlite"
+	DialectPostgres Dialect = "postgres"
+)
+
+// Unit is the period dates are truncated to
+type Unit string
+
+const (
+	UnitDay   Unit = "day"
+	UnitWeek  Unit = "week"
+	UnitMonth Unit = "month"
+)
+
+// TruncateDate returns an expression truncating column, a text column
+// containing dates formatted as YYYY-MM-DD, to the first day of the unit
+// formatted as YYYY-MM-DD. Weeks start on monday. column is included as is
+// and must never contain user input
+func (d Dialect) TruncateDate(column string, unit Unit) (string, error) {
+	switch unit {
+	case UnitDay:
+		return column, nil
+	case UnitWeek, UnitMonth:
+	default:
+		return "", fmt.Errorf("unknown unit %q", unit)
+	}
+
+	switch d {
+	case DialectSQLite:
+		if unit == UnitWeek {
+			return fmt.Sprintf("date(%s, 'weekday 0', '-6 days')", column), nil
+		}
+		return fmt.Sprintf("strftime('%%Y-%%m-01', %s)", column), nil
+	case DialectPostgres:
+		return fmt.Sprintf("CAST(CAST(date_trunc('%s', CAST(%s AS TIMESTAMP)) AS DATE) AS TEXT)", unit, column), nil
+	default:
+		return "", fmt.Errorf("unknown dialect %q", d)
+	}
+}
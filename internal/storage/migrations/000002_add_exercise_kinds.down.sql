@@ -0,0 +1,3 @@
+ALTER TABLE exercises DROP COLUMN distance_meters;
+ALTER TABLE exercises DROP COLUMN duration_seconds;
+ALTER TABLE exercises DROP COLUMN kind;
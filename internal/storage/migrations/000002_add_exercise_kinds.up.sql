@@ -0,0 +1,3 @@
+ALTER TABLE exercises ADD COLUMN kind TEXT NOT NULL DEFAULT 'weighted';
+ALTER TABLE exercises ADD COLUMN duration_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE exercises ADD COLUMN distance_meters REAL NOT NULL DEFAULT 0;
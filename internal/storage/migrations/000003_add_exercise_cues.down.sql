@@ -0,0 +1,5 @@
+ALTER TABLE exercises DROP COLUMN notes;
+ALTER TABLE exercises DROP COLUMN target_rir;
+ALTER TABLE exercises DROP COLUMN target_rpe;
+ALTER TABLE exercises DROP COLUMN tempo;
+ALTER TABLE exercises DROP COLUMN rest_seconds;
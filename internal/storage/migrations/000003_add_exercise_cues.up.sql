@@ -0,0 +1,5 @@
+ALTER TABLE exercises ADD COLUMN rest_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE exercises ADD COLUMN tempo TEXT NOT NULL DEFAULT '';
+ALTER TABLE exercises ADD COLUMN target_rpe REAL NOT NULL DEFAULT 0;
+ALTER TABLE exercises ADD COLUMN target_rir INTEGER;
+ALTER TABLE exercises ADD COLUMN notes TEXT NOT NULL DEFAULT '';
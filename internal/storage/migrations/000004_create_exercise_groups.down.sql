@@ -0,0 +1,2 @@
+ALTER TABLE exercises DROP COLUMN exercise_group;
+DROP TABLE IF EXISTS exercise_groups;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS exercise_groups (
+  owner TEXT NOT NULL,
+  workout INTEGER NOT NULL,
+  group_index INTEGER NOT NULL,
+  kind TEXT NOT NULL,
+  rounds INTEGER NOT NULL DEFAULT 1,
+  PRIMARY KEY (owner, workout, group_index),
+  FOREIGN KEY (owner, workout)
+    REFERENCES workouts (owner, workout_index)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+ALTER TABLE exercises ADD COLUMN exercise_group INTEGER NOT NULL DEFAULT 0;
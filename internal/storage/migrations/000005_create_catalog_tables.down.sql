@@ -0,0 +1,4 @@
+ALTER TABLE exercises DROP COLUMN catalog;
+DROP TABLE IF EXISTS catalog_aliases;
+DROP TABLE IF EXISTS catalog_muscles;
+DROP TABLE IF EXISTS catalog_exercises;
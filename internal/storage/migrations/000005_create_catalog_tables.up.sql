@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS catalog_exercises (
+  owner TEXT NOT NULL,
+  slug TEXT NOT NULL,
+  name TEXT NOT NULL,
+  equipment TEXT NOT NULL,
+  pattern TEXT NOT NULL,
+  search TEXT NOT NULL DEFAULT '',
+  PRIMARY KEY (owner, slug)
+);
+
+CREATE TABLE IF NOT EXISTS catalog_muscles (
+  owner TEXT NOT NULL,
+  slug TEXT NOT NULL,
+  muscle TEXT NOT NULL,
+  role TEXT NOT NULL,
+  PRIMARY KEY (owner, slug, muscle),
+  FOREIGN KEY (owner, slug)
+    REFERENCES catalog_exercises (owner, slug)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS catalog_aliases (
+  owner TEXT NOT NULL,
+  slug TEXT NOT NULL,
+  alias TEXT NOT NULL,
+  PRIMARY KEY (owner, slug, alias),
+  FOREIGN KEY (owner, slug)
+    REFERENCES catalog_exercises (owner, slug)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+ALTER TABLE exercises ADD COLUMN catalog TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS session_sets;
+DROP TABLE IF EXISTS session_exercises;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS session_counters;
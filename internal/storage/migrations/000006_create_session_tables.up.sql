@@ -0,0 +1,74 @@
+CREATE TABLE IF NOT EXISTS session_counters (
+  owner TEXT NOT NULL,
+  last_index INTEGER NOT NULL,
+  PRIMARY KEY (owner),
+  FOREIGN KEY (owner)
+    REFERENCES users (username)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS sessions (
+  owner TEXT NOT NULL,
+  session_index INTEGER NOT NULL,
+  workout_owner TEXT,
+  workout INTEGER,
+  name TEXT NOT NULL,
+  performed_on TEXT NOT NULL,
+  started_at TIMESTAMP NOT NULL,
+  finished_at TIMESTAMP,
+  notes TEXT NOT NULL DEFAULT '',
+  PRIMARY KEY (owner, session_index),
+  FOREIGN KEY (owner)
+    REFERENCES users (username)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (workout_owner, workout)
+    REFERENCES workouts (owner, workout_index)
+    ON UPDATE CASCADE
+    ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS session_exercises (
+  owner TEXT NOT NULL,
+  session INTEGER NOT NULL,
+  exercise_index INTEGER NOT NULL,
+  workout_owner TEXT,
+  workout INTEGER,
+  workout_exercise INTEGER,
+  name TEXT NOT NULL,
+  catalog TEXT NOT NULL DEFAULT '',
+  kind TEXT NOT NULL,
+  target_weight REAL NOT NULL DEFAULT 0,
+  target_repetitions INTEGER NOT NULL DEFAULT 0,
+  target_duration_seconds INTEGER NOT NULL DEFAULT 0,
+  target_distance_meters REAL NOT NULL DEFAULT 0,
+  PRIMARY KEY (owner, session, exercise_index),
+  FOREIGN KEY (owner, session)
+    REFERENCES sessions (owner, session_index)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (workout_owner, workout, workout_exercise)
+    REFERENCES exercises (owner, workout, exercise_index)
+    ON UPDATE CASCADE
+    ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS session_sets (
+  owner TEXT NOT NULL,
+  session INTEGER NOT NULL,
+  exercise INTEGER NOT NULL,
+  set_index INTEGER NOT NULL,
+  weight REAL NOT NULL DEFAULT 0,
+  repetitions INTEGER NOT NULL DEFAULT 0,
+  duration_seconds INTEGER NOT NULL DEFAULT 0,
+  distance_meters REAL NOT NULL DEFAULT 0,
+  rpe REAL NOT NULL DEFAULT 0,
+  completed BOOLEAN NOT NULL DEFAULT FALSE,
+  logged_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (owner, session, exercise, set_index),
+  FOREIGN KEY (owner, session, exercise)
+    REFERENCES session_exercises (owner, session, exercise_index)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
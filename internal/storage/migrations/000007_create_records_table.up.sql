@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS records (
+  owner TEXT NOT NULL,
+  exercise TEXT NOT NULL,
+  record_type TEXT NOT NULL,
+  at_weight REAL NOT NULL DEFAULT 0,
+  name TEXT NOT NULL,
+  weight REAL NOT NULL DEFAULT 0,
+  repetitions INTEGER NOT NULL DEFAULT 0,
+  value REAL NOT NULL,
+  session INTEGER NOT NULL,
+  performed_on TEXT NOT NULL,
+  PRIMARY KEY (owner, exercise, record_type, at_weight),
+  FOREIGN KEY (owner)
+    REFERENCES users (username)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS progressions;
+ALTER TABLE exercises DROP COLUMN progression_failures;
+ALTER TABLE exercises DROP COLUMN progression_deload_percent;
+ALTER TABLE exercises DROP COLUMN progression_deload_after;
+ALTER TABLE exercises DROP COLUMN progression_max_repetitions;
+ALTER TABLE exercises DROP COLUMN progression_min_repetitions;
+ALTER TABLE exercises DROP COLUMN progression_increment;
+ALTER TABLE exercises DROP COLUMN progression;
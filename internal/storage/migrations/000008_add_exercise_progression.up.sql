@@ -0,0 +1,27 @@
+ALTER TABLE exercises ADD COLUMN progression TEXT NOT NULL DEFAULT '';
+ALTER TABLE exercises ADD COLUMN progression_increment REAL NOT NULL DEFAULT 0;
+ALTER TABLE exercises ADD COLUMN progression_min_repetitions INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE exercises ADD COLUMN progression_max_repetitions INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE exercises ADD COLUMN progression_deload_after INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE exercises ADD COLUMN progression_deload_percent REAL NOT NULL DEFAULT 0;
+ALTER TABLE exercises ADD COLUMN progression_failures INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS progressions (
+  owner TEXT NOT NULL,
+  workout INTEGER NOT NULL,
+  exercise INTEGER NOT NULL,
+  session INTEGER NOT NULL,
+  rule TEXT NOT NULL,
+  outcome TEXT NOT NULL,
+  reason TEXT NOT NULL,
+  from_weight REAL NOT NULL DEFAULT 0,
+  to_weight REAL NOT NULL DEFAULT 0,
+  from_repetitions INTEGER NOT NULL DEFAULT 0,
+  to_repetitions INTEGER NOT NULL DEFAULT 0,
+  applied_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (owner, workout, exercise, session),
+  FOREIGN KEY (owner, workout, exercise)
+    REFERENCES exercises (owner, workout, exercise_index)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS schedules (
+  owner TEXT NOT NULL,
+  mode TEXT NOT NULL,
+  time_zone TEXT NOT NULL DEFAULT 'UTC',
+  PRIMARY KEY (owner),
+  FOREIGN KEY (owner)
+    REFERENCES users (username)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS schedule_slots (
+  owner TEXT NOT NULL,
+  slot_index INTEGER NOT NULL,
+  weekday TEXT NOT NULL DEFAULT '',
+  workout INTEGER NOT NULL,
+  PRIMARY KEY (owner, slot_index),
+  FOREIGN KEY (owner)
+    REFERENCES schedules (owner)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (owner, workout)
+    REFERENCES workouts (owner, workout_index)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS training_maxes;
+DROP TABLE IF EXISTS enrollments;
+DROP TABLE IF EXISTS program_prescriptions;
+DROP TABLE IF EXISTS program_phases;
+DROP TABLE IF EXISTS programs;
@@ -0,0 +1,68 @@
+CREATE TABLE IF NOT EXISTS programs (
+  owner TEXT NOT NULL,
+  program_index INTEGER NOT NULL,
+  name TEXT NOT NULL,
+  PRIMARY KEY (owner, program_index),
+  FOREIGN KEY (owner)
+    REFERENCES users (username)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS program_phases (
+  owner TEXT NOT NULL,
+  program INTEGER NOT NULL,
+  phase_index INTEGER NOT NULL,
+  name TEXT NOT NULL,
+  weeks INTEGER NOT NULL,
+  intensity REAL NOT NULL DEFAULT 100,
+  PRIMARY KEY (owner, program, phase_index),
+  FOREIGN KEY (owner, program)
+    REFERENCES programs (owner, program_index)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS program_prescriptions (
+  owner TEXT NOT NULL,
+  program INTEGER NOT NULL,
+  prescription_index INTEGER NOT NULL,
+  week INTEGER NOT NULL DEFAULT 0,
+  workout INTEGER NOT NULL,
+  exercise INTEGER NOT NULL,
+  lift TEXT NOT NULL,
+  percent REAL NOT NULL,
+  repetitions INTEGER NOT NULL,
+  PRIMARY KEY (owner, program, prescription_index),
+  FOREIGN KEY (owner, program)
+    REFERENCES programs (owner, program_index)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (owner, workout)
+    REFERENCES workouts (owner, workout_index)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS enrollments (
+  owner TEXT NOT NULL,
+  program INTEGER NOT NULL,
+  start_date TEXT NOT NULL,
+  PRIMARY KEY (owner, program),
+  FOREIGN KEY (owner, program)
+    REFERENCES programs (owner, program_index)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS training_maxes (
+  owner TEXT NOT NULL,
+  program INTEGER NOT NULL,
+  lift TEXT NOT NULL,
+  weight REAL NOT NULL,
+  PRIMARY KEY (owner, program, lift),
+  FOREIGN KEY (owner, program)
+    REFERENCES enrollments (owner, program)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+ALTER TABLE session_sets DROP COLUMN warmup;
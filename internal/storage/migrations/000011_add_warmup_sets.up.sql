@@ -0,0 +1 @@
+ALTER TABLE session_sets ADD COLUMN warmup BOOLEAN NOT NULL DEFAULT FALSE;
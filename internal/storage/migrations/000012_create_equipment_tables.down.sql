@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS equipment_machines;
+DROP TABLE IF EXISTS equipment_plates;
+DROP TABLE IF EXISTS equipment_bars;
+DROP TABLE IF EXISTS equipment_profiles;
@@ -0,0 +1,48 @@
+CREATE TABLE IF NOT EXISTS equipment_profiles (
+  owner TEXT NOT NULL,
+  dumbbell_min REAL NOT NULL DEFAULT 0,
+  dumbbell_max REAL NOT NULL DEFAULT 0,
+  dumbbell_increment REAL NOT NULL DEFAULT 0,
+  PRIMARY KEY (owner),
+  FOREIGN KEY (owner)
+    REFERENCES users (username)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS equipment_bars (
+  owner TEXT NOT NULL,
+  bar_index INTEGER NOT NULL,
+  name TEXT NOT NULL,
+  weight REAL NOT NULL,
+  PRIMARY KEY (owner, bar_index),
+  FOREIGN KEY (owner)
+    REFERENCES equipment_profiles (owner)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS equipment_plates (
+  owner TEXT NOT NULL,
+  weight REAL NOT NULL,
+  quantity INTEGER NOT NULL,
+  PRIMARY KEY (owner, weight),
+  FOREIGN KEY (owner)
+    REFERENCES equipment_profiles (owner)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS equipment_machines (
+  owner TEXT NOT NULL,
+  machine_index INTEGER NOT NULL,
+  name TEXT NOT NULL,
+  min_weight REAL NOT NULL,
+  max_weight REAL NOT NULL,
+  increment REAL NOT NULL,
+  PRIMARY KEY (owner, machine_index),
+  FOREIGN KEY (owner)
+    REFERENCES equipment_profiles (owner)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS body_measurements;
+DROP TABLE IF EXISTS body_entries;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS body_entries (
+  owner TEXT NOT NULL,
+  measured_on TEXT NOT NULL,
+  weight REAL NOT NULL DEFAULT 0,
+  body_fat REAL NOT NULL DEFAULT 0,
+  PRIMARY KEY (owner, measured_on),
+  FOREIGN KEY (owner)
+    REFERENCES users (username)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS body_measurements (
+  owner TEXT NOT NULL,
+  measured_on TEXT NOT NULL,
+  site TEXT NOT NULL,
+  circumference REAL NOT NULL,
+  PRIMARY KEY (owner, measured_on, site),
+  FOREIGN KEY (owner, measured_on)
+    REFERENCES body_entries (owner, measured_on)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
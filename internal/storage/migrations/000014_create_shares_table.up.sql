@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS workout_shares (
+  token TEXT NOT NULL,
+  owner TEXT NOT NULL,
+  workout INTEGER NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  expires_at TIMESTAMP,
+  PRIMARY KEY (token),
+  FOREIGN KEY (owner, workout)
+    REFERENCES workouts (owner, workout_index)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+ALTER TABLE workouts ADD COLUMN author TEXT NOT NULL DEFAULT '';
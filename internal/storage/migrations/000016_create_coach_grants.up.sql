@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS coach_grants (
+  athlete TEXT NOT NULL,
+  coach TEXT NOT NULL,
+  access TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  accepted_at TIMESTAMP,
+  PRIMARY KEY (athlete, coach),
+  FOREIGN KEY (athlete)
+    REFERENCES users (username)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (coach)
+    REFERENCES users (username)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
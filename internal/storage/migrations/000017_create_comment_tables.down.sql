@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS session_comments;
+DROP TABLE IF EXISTS workout_comments;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS workout_comments (
+  owner TEXT NOT NULL,
+  workout INTEGER NOT NULL,
+  comment_index INTEGER NOT NULL,
+  parent INTEGER NOT NULL DEFAULT 0,
+  author TEXT NOT NULL,
+  body TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP,
+  PRIMARY KEY (owner, workout, comment_index),
+  FOREIGN KEY (owner, workout)
+    REFERENCES workouts (owner, workout_index)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS session_comments (
+  owner TEXT NOT NULL,
+  session INTEGER NOT NULL,
+  comment_index INTEGER NOT NULL,
+  parent INTEGER NOT NULL DEFAULT 0,
+  author TEXT NOT NULL,
+  body TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP,
+  PRIMARY KEY (owner, session, comment_index),
+  FOREIGN KEY (owner, session)
+    REFERENCES sessions (owner, session_index)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
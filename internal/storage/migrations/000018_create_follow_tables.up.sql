@@ -0,0 +1,17 @@
+ALTER TABLE users ADD COLUMN privacy TEXT NOT NULL DEFAULT 'public';
+
+CREATE TABLE IF NOT EXISTS follows (
+  follower TEXT NOT NULL,
+  followee TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  accepted_at TIMESTAMP,
+  PRIMARY KEY (follower, followee),
+  FOREIGN KEY (follower)
+    REFERENCES users (username)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (followee)
+    REFERENCES users (username)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
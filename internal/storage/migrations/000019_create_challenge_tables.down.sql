@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS challenge_standings;
+DROP TABLE IF EXISTS challenge_participants;
+DROP TABLE IF EXISTS challenges;
@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS challenges (
+  challenge_id INTEGER NOT NULL,
+  owner TEXT NOT NULL,
+  name TEXT NOT NULL,
+  metric TEXT NOT NULL,
+  exercise TEXT NOT NULL DEFAULT '',
+  starts_on TEXT NOT NULL,
+  ends_on TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  computed_at TIMESTAMP,
+  PRIMARY KEY (challenge_id),
+  FOREIGN KEY (owner)
+    REFERENCES users (username)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS challenge_participants (
+  challenge INTEGER NOT NULL,
+  username TEXT NOT NULL,
+  joined_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (challenge, username),
+  FOREIGN KEY (challenge)
+    REFERENCES challenges (challenge_id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE,
+  FOREIGN KEY (username)
+    REFERENCES users (username)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS challenge_standings (
+  challenge INTEGER NOT NULL,
+  username TEXT NOT NULL,
+  standing INTEGER NOT NULL,
+  value REAL NOT NULL DEFAULT 0,
+  PRIMARY KEY (challenge, username),
+  FOREIGN KEY (challenge)
+    REFERENCES challenges (challenge_id)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
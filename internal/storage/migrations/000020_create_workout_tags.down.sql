@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS workout_tags;
+
+ALTER TABLE workouts DROP COLUMN folder;
@@ -0,0 +1,12 @@
+ALTER TABLE workouts ADD COLUMN folder TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS workout_tags (
+  owner TEXT NOT NULL,
+  workout INTEGER NOT NULL,
+  tag TEXT NOT NULL,
+  PRIMARY KEY (owner, workout, tag),
+  FOREIGN KEY (owner, workout)
+    REFERENCES workouts (owner, workout_index)
+    ON UPDATE CASCADE
+    ON DELETE CASCADE
+);
@@ -35,10 +35,10 @@ func newCockroach(dburl, path string) (*SqlDatastore, error) {
 
 	if err := m.Up(); err != nil {
 		if errors.Is(err, migrate.ErrNoChange) {
-			return &SqlDatastore{db, placeholder}, nil
+			return &SqlDatastore{db, placeholder, DialectPostgres}, nil
 		}
 		return nil, err
 	}
 
-	return &SqlDatastore{db, placeholder}, nil
+	return &SqlDatastore{db, placeholder, DialectPostgres}, nil
 }
@@ -50,10 +50,10 @@ func newLocalSqlite(dburl, path string, overwrite bool) (*SqlDatastore, error) {
 
 	if err := m.Up(); err != nil {
 		if errors.Is(err, migrate.ErrNoChange) {
-			return &SqlDatastore{db, placeholder}, nil
+			return &SqlDatastore{db, placeholder, DialectSQLite}, nil
 		}
 		return nil, err
 	}
 
-	return &SqlDatastore{db, placeholder}, nil
+	return &SqlDatastore{db, placeholder, DialectSQLite}, nil
 }
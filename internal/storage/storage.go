@@ -32,6 +32,7 @@ var DefaultSqliteConfig = DatastoreConfig{
 type SqlDatastore struct {
 	*sql.DB
 	placeholder tqla.Option
+	dialect     Dialect
 }
 
 // NewSqliteDatastore creates a new database at dbURL
@@ -64,3 +65,8 @@ func (ds *SqlDatastore) CompileStatement(stmt string, data any) (string, []any,
 
 	return tmpl.Compile(stmt, data)
 }
+
+// Dialect returns the SQL dialect of the underlying database
+func (ds *SqlDatastore) Dialect() Dialect {
+	return ds.dialect
+}
@@ -0,0 +1,80 @@
+package strength
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/scrot/musclemem-api/internal/api"
+)
+
+// DefaultTableRepetitions is the size of the rep-max table
+// returned when no size is specified
+const DefaultTableRepetitions = 12
+
+// NewOneRepMaxHandler returns the estimated one-rep max and rep-max table
+// requires weight and reps query parameters, optional formula and table
+// query parameters, where table is the number of rows of the rep-max table
+func NewOneRepMaxHandler(l *slog.Logger) http.Handler {
+	l = l.With("handler", "OneRepMaxHandler")
+
+	type Response struct {
+		Formula     Formula  `json:"formula"`
+		Weight      float64  `json:"weight"`
+		Repetitions int      `json:"repetitions"`
+		OneRepMax   float64  `json:"e1rm"`
+		Table       []RepMax `json:"table"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+
+		l := l.With("weight", params.Get("weight"), "reps", params.Get("reps"), "formula", params.Get("formula"))
+
+		weight, err := strconv.ParseFloat(params.Get("weight"), 64)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "invalid weight")
+			return
+		}
+
+		reps, err := strconv.Atoi(params.Get("reps"))
+		if err != nil {
+			api.WriteInternalError(l, w, err, "invalid reps")
+			return
+		}
+
+		formula, err := ParseFormula(params.Get("formula"))
+		if err != nil {
+			api.WriteInternalError(l, w, err, "invalid formula")
+			return
+		}
+
+		size := DefaultTableRepetitions
+		if v := params.Get("table"); v != "" {
+			if size, err = strconv.Atoi(v); err != nil {
+				api.WriteInternalError(l, w, err, "invalid table")
+				return
+			}
+		}
+
+		e1rm, err := Estimate(formula, weight, reps)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "invalid weight or reps")
+			return
+		}
+
+		table, err := Table(formula, e1rm, size)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "invalid table")
+			return
+		}
+
+		l.Debug("estimated one-rep max", "e1rm", e1rm)
+
+		resp := Response{formula, weight, reps, e1rm, table}
+		if err := api.WriteJSON(w, http.StatusOK, resp); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
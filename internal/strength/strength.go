@@ -0,0 +1,171 @@
+// Package strength estimates one-rep maxes from submaximal sets and
+// the weights that can be lifted for a number of repetitions
+package strength
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	ErrUnknownFormula = errors.New("unknown formula")
+	ErrInvalidInput   = errors.New("invalid input")
+)
+
+// MaxRepetitions is the highest number of repetitions a one-rep max is
+// estimated from, estimates become unreliable for higher repetitions
+const MaxRepetitions = 30
+
+// Formula is a published one-rep max estimation formula
+type Formula string
+
+const (
+	FormulaEpley    Formula = "epley"
+	FormulaBrzycki  Formula = "brzycki"
+	FormulaLombardi Formula = "lombardi"
+	FormulaMayhew   Formula = "mayhew"
+	FormulaOConner  Formula = "oconner"
+	FormulaWathan   Formula = "wathan"
+	FormulaLander   Formula = "lander"
+)
+
+// DefaultFormula is used when no formula is specified
+const DefaultFormula = FormulaEpley
+
+// Formulas contains all supported formulas
+var Formulas = []Formula{
+	FormulaEpley,
+	FormulaBrzycki,
+	FormulaLombardi,
+	FormulaMayhew,
+	FormulaOConner,
+	FormulaWathan,
+	FormulaLander,
+}
+
+// ParseFormula returns the formula named s, an empty s returns the DefaultFormula
+func ParseFormula(s string) (Formula, error) {
+	if s == "" {
+		return DefaultFormula, nil
+	}
+
+	for _, f := range Formulas {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrUnknownFormula, s)
+}
+
+// percentage returns the fraction of the one-rep max
+// that can be lifted for reps repetitions
+func (f Formula) percentage(reps int) float64 {
+	r := float64(reps)
+
+	switch f {
+	case FormulaEpley:
+		return 1 / (1 + r/30)
+	case FormulaBrzycki:
+		return (37 - r) / 36
+	case FormulaLombardi:
+		return 1 / math.Pow(r, 0.10)
+	case FormulaMayhew:
+		return (52.2 + 41.9*math.Exp(-0.055*r)) / 100
+	case FormulaOConner:
+		return 1 / (1 + 0.025*r)
+	case FormulaWathan:
+		return (48.8 + 53.8*math.Exp(-0.075*r)) / 100
+	case FormulaLander:
+		return (101.3 - 2.67123*r) / 100
+	default:
+		return 0
+	}
+}
+
+// Estimate returns the estimated one-rep max of lifting weight for reps
+// repetitions using formula f, rounded to two decimals. A single
+// repetition is the one-rep max itself
+func Estimate(f Formula, weight float64, reps int) (float64, error) {
+	if err := validate(f, weight, reps); err != nil {
+		return 0, fmt.Errorf("Estimate: %w", err)
+	}
+
+	if reps == 1 {
+		return weight, nil
+	}
+
+	return round(weight / f.percentage(reps)), nil
+}
+
+// OneRepMax returns the estimated one-rep max using the DefaultFormula,
+// it returns 0 when the one-rep max can't be estimated from the set
+func OneRepMax(weight float64, reps int) float64 {
+	e, err := Estimate(DefaultFormula, weight, reps)
+	if err != nil {
+		return 0
+	}
+	return e
+}
+
+// Weight is the inverse of Estimate, it returns the weight that can be
+// lifted for reps repetitions given the one-rep max, rounded to two decimals
+func Weight(f Formula, oneRepMax float64, reps int) (float64, error) {
+	if err := validate(f, oneRepMax, reps); err != nil {
+		return 0, fmt.Errorf("Weight: %w", err)
+	}
+
+	if reps == 1 {
+		return oneRepMax, nil
+	}
+
+	return round(oneRepMax * f.percentage(reps)), nil
+}
+
+// RepMax is the weight that can be lifted for Repetitions repetitions
+type RepMax struct {
+	Repetitions int     `json:"repetitions"`
+	Weight      float64 `json:"weight"`
+	Percentage  float64 `json:"percentage"`
+}
+
+// Table returns the rep-max table from 1 up to and including reps
+// repetitions for a one-rep max using formula f
+func Table(f Formula, oneRepMax float64, reps int) ([]RepMax, error) {
+	if err := validate(f, oneRepMax, reps); err != nil {
+		return nil, fmt.Errorf("Table: %w", err)
+	}
+
+	table := make([]RepMax, 0, reps)
+	for r := 1; r <= reps; r++ {
+		w, err := Weight(f, oneRepMax, r)
+		if err != nil {
+			return nil, fmt.Errorf("Table: %w", err)
+		}
+
+		table = append(table, RepMax{r, w, round(w / oneRepMax * 100)})
+	}
+
+	return table, nil
+}
+
+func validate(f Formula, weight float64, reps int) error {
+	if f.percentage(1) == 0 {
+		return fmt.Errorf("%w: %q", ErrUnknownFormula, f)
+	}
+
+	if weight <= 0 {
+		return fmt.Errorf("%w: weight %.2f, expected more than 0", ErrInvalidInput, weight)
+	}
+
+	if reps < 1 || reps > MaxRepetitions {
+		return fmt.Errorf("%w: repetitions %d, expected 1 to %d", ErrInvalidInput, reps, MaxRepetitions)
+	}
+
+	return nil
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
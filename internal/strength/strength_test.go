@@ -0,0 +1,114 @@
+package strength
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEstimate(t *testing.T) {
+	cs := []struct {
+		formula Formula
+		weight  float64
+		reps    int
+		want    float64
+	}{
+		{FormulaEpley, 100, 1, 100},
+		{FormulaEpley, 100, 5, 116.67},
+		{FormulaEpley, 100, 10, 133.33},
+		{FormulaBrzycki, 100, 5, 112.5},
+		{FormulaBrzycki, 100, 10, 133.33},
+		{FormulaLombardi, 100, 10, 125.89},
+		{FormulaMayhew, 100, 10, 130.93},
+		{FormulaOConner, 100, 10, 125},
+		{FormulaWathan, 100, 10, 134.75},
+		{FormulaLander, 100, 10, 134.07},
+		{FormulaBrzycki, 225, 1, 225},
+	}
+
+	for _, c := range cs {
+		got, err := Estimate(c.formula, c.weight, c.reps)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != c.want {
+			t.Errorf("%s %.1fx%d: want %.2f but got %.2f", c.formula, c.weight, c.reps, c.want, got)
+		}
+	}
+}
+
+func TestEstimateInvalidInput(t *testing.T) {
+	cs := []struct {
+		name    string
+		formula Formula
+		weight  float64
+		reps    int
+		wantErr error
+	}{
+		{"unknownFormula", "guess", 100, 5, ErrUnknownFormula},
+		{"zeroWeight", FormulaEpley, 0, 5, ErrInvalidInput},
+		{"zeroRepetitions", FormulaEpley, 100, 0, ErrInvalidInput},
+		{"tooManyRepetitions", FormulaBrzycki, 100, MaxRepetitions + 1, ErrInvalidInput},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := Estimate(c.formula, c.weight, c.reps); !errors.Is(err, c.wantErr) {
+				t.Errorf("want error %v but got %v", c.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTable(t *testing.T) {
+	// published percentages of the one-rep max for 1, 5 and 10 repetitions
+	cs := []struct {
+		formula Formula
+		want    map[int]float64
+	}{
+		{FormulaBrzycki, map[int]float64{1: 100, 5: 88.89, 10: 75}},
+		{FormulaEpley, map[int]float64{1: 100, 5: 85.71, 10: 75}},
+		{FormulaOConner, map[int]float64{1: 100, 5: 88.89, 10: 80}},
+	}
+
+	for _, c := range cs {
+		table, err := Table(c.formula, 100, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(table) != 10 {
+			t.Fatalf("%s: want 10 rows but got %d", c.formula, len(table))
+		}
+
+		for reps, want := range c.want {
+			if got := table[reps-1]; got.Repetitions != reps || got.Percentage != want {
+				t.Errorf("%s %d reps: want %.2f%% but got %v", c.formula, reps, want, got)
+			}
+		}
+	}
+}
+
+func TestParseFormula(t *testing.T) {
+	cs := []struct {
+		input   string
+		want    Formula
+		wantErr bool
+	}{
+		{"", DefaultFormula, false},
+		{"brzycki", FormulaBrzycki, false},
+		{"Brzycki", "", true},
+		{"guess", "", true},
+	}
+
+	for _, c := range cs {
+		got, err := ParseFormula(c.input)
+		if (err != nil) != c.wantErr {
+			t.Errorf("%q: want error %t but got %v", c.input, c.wantErr, err)
+		}
+
+		if got != c.want {
+			t.Errorf("%q: want %s but got %s", c.input, c.want, got)
+		}
+	}
+}
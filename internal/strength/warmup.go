@@ -0,0 +1,180 @@
+package strength
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Scheme determines how the warm-up sets ramp up to the working weight
+type Scheme string
+
+const (
+	// SchemeRamp warms up with increasing percentages of the working weight
+	SchemeRamp Scheme = "ramp"
+
+	// SchemeSteps warms up from the bar adding a fixed step each set
+	SchemeSteps Scheme = "steps"
+
+	// SchemeBar starts with the empty bar before ramping up
+	SchemeBar Scheme = "bar"
+)
+
+const (
+	DefaultScheme = SchemeRamp
+	DefaultBar    = 20.0
+	DefaultStep   = 20.0
+)
+
+// DefaultPlates are the plate denominations of a standard plate set
+var DefaultPlates = []float64{25, 20, 15, 10, 5, 2.5, 1.25}
+
+// ramps contains the percentage of the working weight and repetitions of
+// each warm-up set of a ramp, a percentage of 0 represents the empty bar
+var ramps = map[Scheme][]struct {
+	percent     float64
+	repetitions int
+}{
+	SchemeRamp: {{40, 5}, {60, 3}, {80, 2}},
+	SchemeBar:  {{0, 10}, {50, 5}, {70, 3}, {85, 1}},
+}
+
+// stepRepetitions are the repetitions of the warm-up sets of
+// SchemeSteps, all subsequent sets are performed for a single repetition
+var stepRepetitions = []int{10, 5, 3, 2}
+
+// WarmupConfig configures the warm-up generator, zero values are replaced
+// with the defaults. Bar is the weight of the empty bar, Plates the
+// available plate denominations and Step the increment of SchemeSteps.
+// Round replaces the rounding to the plates when set
+type WarmupConfig struct {
+	Scheme Scheme                `json:"scheme"`
+	Bar    float64               `json:"bar"`
+	Plates []float64             `json:"plates"`
+	Step   float64               `json:"step,omitempty"`
+	Round  func(float64) float64 `json:"-"`
+}
+
+// WarmupSet is a single warm-up set, Percent is the
+// percentage of the working weight
+type WarmupSet struct {
+	Weight      float64 `json:"weight"`
+	Repetitions int     `json:"repetitions"`
+	Percent     float64 `json:"percent"`
+}
+
+// ParseScheme returns the scheme with name s, an empty
+// string returns the DefaultScheme
+func ParseScheme(s string) (Scheme, error) {
+	switch Scheme(s) {
+	case "":
+		return DefaultScheme, nil
+	case SchemeRamp, SchemeSteps, SchemeBar:
+		return Scheme(s), nil
+	default:
+		return "", fmt.Errorf("%w: scheme %q", ErrInvalidInput, s)
+	}
+}
+
+// Warmup returns the warm-up sets leading up to the working weight, every
+// weight can be loaded on the bar with the available plates. Sets that
+// would not be lighter than the working weight or the previous set are
+// left out, so a working weight at or below the bar has no warm-up sets
+func Warmup(working float64, c WarmupConfig) ([]WarmupSet, error) {
+	c, err := c.withDefaults()
+	if err != nil {
+		return []WarmupSet{}, fmt.Errorf("Warmup: %w", err)
+	}
+
+	if working <= 0 {
+		return []WarmupSet{}, fmt.Errorf("Warmup: %w: working weight %.2f", ErrInvalidInput, working)
+	}
+
+	type target struct {
+		weight      float64
+		repetitions int
+	}
+
+	var targets []target
+	switch c.Scheme {
+	case SchemeSteps:
+		for i := 0; c.Bar+float64(i)*c.Step < working; i++ {
+			reps := 1
+			if i < len(stepRepetitions) {
+				reps = stepRepetitions[i]
+			}
+			targets = append(targets, target{c.Bar + float64(i)*c.Step, reps})
+		}
+	default:
+		for _, r := range ramps[c.Scheme] {
+			targets = append(targets, target{working * r.percent / 100, r.repetitions})
+		}
+	}
+
+	sets := []WarmupSet{}
+	for _, t := range targets {
+		weight := Loadable(t.weight, c.Bar, c.Plates)
+		if c.Round != nil {
+			weight = max(c.Round(t.weight), c.Bar)
+		}
+		if weight >= working || (len(sets) > 0 && weight <= sets[len(sets)-1].Weight) {
+			continue
+		}
+		sets = append(sets, WarmupSet{weight, t.repetitions, round(weight / working * 100)})
+	}
+
+	return sets, nil
+}
+
+// Loadable returns the heaviest weight at or below target that can be loaded
+// symmetrically on a bar with the plate denominations, it returns the bar
+// weight when target is lighter than the bar
+func Loadable(target float64, bar float64, plates []float64) float64 {
+	ps := append([]float64{}, plates...)
+	sort.Sort(sort.Reverse(sort.Float64Slice(ps)))
+
+	// small epsilon prevents float noise from dropping a plate
+	const eps = 1e-9
+
+	side := (target - bar) / 2
+	if side <= 0 {
+		return bar
+	}
+
+	loaded := 0.0
+	for _, p := range ps {
+		if p <= 0 {
+			continue
+		}
+		n := math.Floor((side-loaded)/p + eps)
+		loaded += n * p
+	}
+
+	return round(bar + 2*loaded)
+}
+
+func (c WarmupConfig) withDefaults() (WarmupConfig, error) {
+	scheme, err := ParseScheme(string(c.Scheme))
+	if err != nil {
+		return WarmupConfig{}, err
+	}
+	c.Scheme = scheme
+
+	if c.Bar < 0 || c.Step < 0 {
+		return WarmupConfig{}, fmt.Errorf("%w: negative bar or step", ErrInvalidInput)
+	}
+
+	if c.Bar == 0 {
+		c.Bar = DefaultBar
+	}
+
+	if c.Step == 0 {
+		c.Step = DefaultStep
+	}
+
+	if len(c.Plates) == 0 {
+		c.Plates = DefaultPlates
+	}
+
+	return c, nil
+}
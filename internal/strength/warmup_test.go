@@ -0,0 +1,53 @@
+package strength
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestWarmup(t *testing.T) {
+	cs := []struct {
+		name    string
+		working float64
+		config  WarmupConfig
+		want    []WarmupSet
+	}{
+		{"ramp", 100, WarmupConfig{}, []WarmupSet{{40, 5, 40}, {60, 3, 60}, {80, 2, 80}}},
+		{"rampRoundsDown", 102.5, WarmupConfig{}, []WarmupSet{{40, 5, 39.02}, {60, 3, 58.54}, {80, 2, 78.05}}},
+		{"rampDropsDuplicates", 30, WarmupConfig{}, []WarmupSet{{20, 5, 66.67}, {22.5, 2, 75}}},
+		{"steps", 100, WarmupConfig{Scheme: SchemeSteps}, []WarmupSet{{20, 10, 20}, {40, 5, 40}, {60, 3, 60}, {80, 2, 80}}},
+		{"stepsSingles", 140, WarmupConfig{Scheme: SchemeSteps, Step: 20}, []WarmupSet{
+			{20, 10, 14.29}, {40, 5, 28.57}, {60, 3, 42.86}, {80, 2, 57.14}, {100, 1, 71.43}, {120, 1, 85.71},
+		}},
+		{"bar", 100, WarmupConfig{Scheme: SchemeBar}, []WarmupSet{{20, 10, 20}, {50, 5, 50}, {70, 3, 70}, {85, 1, 85}}},
+		{"heavyPlatesOnly", 100, WarmupConfig{Scheme: SchemeBar, Plates: []float64{20, 10}}, []WarmupSet{{20, 10, 20}, {40, 5, 40}, {60, 3, 60}, {80, 1, 80}}},
+		{"womensBar", 60, WarmupConfig{Bar: 15}, []WarmupSet{{22.5, 5, 37.5}, {35, 3, 58.33}, {47.5, 2, 79.17}}},
+		{"atBar", 20, WarmupConfig{}, []WarmupSet{}},
+		{"customRound", 105, WarmupConfig{Round: func(w float64) float64 { return math.Round(w/10) * 10 }}, []WarmupSet{
+			{40, 5, 38.1}, {60, 3, 57.14}, {80, 2, 76.19},
+		}},
+	}
+
+	for _, c := range cs {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := Warmup(c.working, c.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("want %v but got %v", c.want, got)
+			}
+		})
+	}
+
+	if _, err := Warmup(100, WarmupConfig{Scheme: "jog"}); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("want error %v but got %v", ErrInvalidInput, err)
+	}
+
+	if _, err := Warmup(0, WarmupConfig{}); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("want error %v but got %v", ErrInvalidInput, err)
+	}
+}
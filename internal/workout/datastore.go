@@ -13,6 +13,7 @@ type WorkoutStore interface {
 	Retreiver
 	Storer
 	Updater
+	Tagger
 	Deleter
 }
 
@@ -23,8 +24,15 @@ type Retreiver interface {
 	// includeExercises also include all exercises belonging to the workout
 	ByID(owner string, workout int) (Workout, error)
 
-	// ByOwner returns all workouts belonging to an owner
-	ByOwner(owner string) ([]Workout, error)
+	// ByOwner returns the workouts belonging to an owner
+	// matching the tag and folder of the query if set
+	ByOwner(owner string, query Query) ([]Workout, error)
+
+	// Tags returns the tags an owner labeled workouts with
+	Tags(owner string) ([]Tag, error)
+
+	// Folders returns the folders an owner put workouts in
+	Folders(owner string) ([]Folder, error)
 }
 
 // Storer implementations allow for new workouts to be created
@@ -37,6 +45,26 @@ type Storer interface {
 type Updater interface {
 	// ChangeName updates the name of an existing workout
 	ChangeName(owner string, workout int, name string) (Workout, error)
+
+	// ChangeFolder moves an existing workout into a folder,
+	// an empty folder moves the workout out of any folder
+	ChangeFolder(owner string, workout int, folder string) (Workout, error)
+}
+
+// Tagger implementations allow for workouts to be labeled with tags
+type Tagger interface {
+	// Tag labels an existing workout with a tag
+	Tag(owner string, workout int, tag string) (Workout, error)
+
+	// Untag removes a tag from an existing workout
+	Untag(owner string, workout int, tag string) (Workout, error)
+
+	// RenameTag renames a tag on all workouts of owner,
+	// merging it into the tag name if that already exists
+	RenameTag(owner string, tag string, name string) (Tag, error)
+
+	// DeleteTag removes a tag from all workouts of owner
+	DeleteTag(owner string, tag string) (Tag, error)
 }
 
 // Deleter implementations allow for existing workouts to be deleted
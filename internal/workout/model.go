@@ -7,12 +7,23 @@ import (
 	"strings"
 )
 
+const (
+	MaxTagLength    = 32
+	MaxFolderLength = 64
+)
+
 // Workout is a collection of ordered exercises
-// that should be completed in a single session
+// that should be completed in a single session,
+// Author is the user that created the workout
+// it is imported from, empty if created by Owner.
+// Folder and Tags organize the workouts of Owner
 type Workout struct {
-	Owner string `json:"owner"`
-	Index int    `json:"index"`
-	Name  string `json:"name" validate:"required"`
+	Owner  string   `json:"owner"`
+	Index  int      `json:"index"`
+	Name   string   `json:"name" validate:"required"`
+	Author string   `json:"author,omitempty"`
+	Folder string   `json:"folder,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
 }
 
 func (w Workout) String() string {
@@ -47,3 +58,57 @@ func ParseRef(s string) (WorkoutRef, error) {
 
 	return WorkoutRef{Username: ss[0], WorkoutIndex: wi}, nil
 }
+
+// Query filters the workouts of an owner, Tag and
+// Folder are optional and ignored when empty
+type Query struct {
+	Tag    string
+	Folder string
+}
+
+// Tag is a label on the workouts of an owner,
+// Workouts is the number of workouts labeled
+type Tag struct {
+	Name     string `json:"name"`
+	Workouts int    `json:"workouts"`
+}
+
+// Folder groups the workouts of an owner,
+// Workouts is the number of workouts in it
+type Folder struct {
+	Name     string `json:"name"`
+	Workouts int    `json:"workouts"`
+}
+
+// NormalizeTag returns the tag trimmed and lowercased so tags are
+// matched case insensitive, tags can't contain slashes since they
+// are used in paths
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+
+	if tag == "" {
+		return "", fmt.Errorf("%w: empty tag", ErrInvalidFields)
+	}
+
+	if n := len([]rune(tag)); n > MaxTagLength {
+		return "", fmt.Errorf("%w: tag of %d characters, expected up to %d", ErrInvalidFields, n, MaxTagLength)
+	}
+
+	if strings.Contains(tag, "/") {
+		return "", fmt.Errorf("%w: tag %q contains a slash", ErrInvalidFields, tag)
+	}
+
+	return tag, nil
+}
+
+// NormalizeFolder returns the folder trimmed of spaces and surrounding
+// slashes, an empty folder moves the workout out of any folder
+func NormalizeFolder(folder string) (string, error) {
+	folder = strings.Trim(strings.TrimSpace(folder), "/")
+
+	if n := len([]rune(folder)); n > MaxFolderLength {
+		return "", fmt.Errorf("%w: folder of %d characters, expected up to %d", ErrInvalidFields, n, MaxFolderLength)
+	}
+
+	return folder, nil
+}
@@ -14,11 +14,19 @@ func NewFetchAllHandler(l *slog.Logger, workouts Retreiver) http.Handler {
 	l = l.With("handler", "FetchAllHandler")
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		username := r.PathValue("username")
+		var (
+			username = r.PathValue("username")
+			params   = r.URL.Query()
+		)
 
-		l := l.With("user", username)
+		query := Query{
+			Tag:    params.Get("tag"),
+			Folder: params.Get("folder"),
+		}
+
+		l := l.With("user", username, "tag", query.Tag, "folder", query.Folder)
 
-		ws, err := workouts.ByOwner(username)
+		ws, err := workouts.ByOwner(username, query)
 		if err != nil {
 			api.WriteInternalError(l, w, err, "")
 			return
@@ -95,6 +103,11 @@ func NewDeleteHandler(l *slog.Logger, workouts Deleter) http.Handler {
 func NewUpdateHandler(l *slog.Logger, workouts Updater) http.Handler {
 	l = l.With("handler", "UpdateHandler")
 
+	type Request struct {
+		Name   string  `json:"name"`
+		Folder *string `json:"folder"`
+	}
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var (
 			username = r.PathValue("username")
@@ -112,24 +125,210 @@ func NewUpdateHandler(l *slog.Logger, workouts Updater) http.Handler {
 		defer r.Body.Close()
 		dec := json.NewDecoder(r.Body)
 
-		var patch Workout
+		var patch Request
 		if err := dec.Decode(&patch); err != nil {
 			api.WriteInternalError(l, w, err, "")
 			return
 		}
 
+		if patch.Name == "" && patch.Folder == nil {
+			api.WriteInternalError(l, w, ErrInvalidFields, "nothing to update")
+			return
+		}
+
+		var updated Workout
+
 		if patch.Name != "" {
 			l = l.With("name", patch.Name)
-			updated, err := workouts.ChangeName(username, wid, patch.Name)
+			updated, err = workouts.ChangeName(username, wid, patch.Name)
 			if err != nil {
 				api.WriteInternalError(l, w, err, "nothing to update")
 				return
 			}
-			if err := api.WriteJSON(w, http.StatusOK, updated); err != nil {
+		}
+
+		if patch.Folder != nil {
+			l = l.With("folder", *patch.Folder)
+			updated, err = workouts.ChangeFolder(username, wid, *patch.Folder)
+			if err != nil {
 				api.WriteInternalError(l, w, err, "")
 				return
 			}
-		} else {
+		}
+
+		if err := api.WriteJSON(w, http.StatusOK, updated); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+func NewTagHandler(l *slog.Logger, workouts Tagger) http.Handler {
+	l = l.With("handler", "TagHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			workout  = r.PathValue("workout")
+			tag      = r.PathValue("tag")
+		)
+
+		l := l.With("username", username, "workout", workout, "tag", tag)
+
+		wid, err := strconv.Atoi(workout)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		tagged, err := workouts.Tag(username, wid, tag)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("workout tagged", "key", tagged.Ref())
+
+		if err := api.WriteJSON(w, http.StatusOK, tagged); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+func NewUntagHandler(l *slog.Logger, workouts Tagger) http.Handler {
+	l = l.With("handler", "UntagHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			workout  = r.PathValue("workout")
+			tag      = r.PathValue("tag")
+		)
+
+		l := l.With("username", username, "workout", workout, "tag", tag)
+
+		wid, err := strconv.Atoi(workout)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		untagged, err := workouts.Untag(username, wid, tag)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("workout untagged", "key", untagged.Ref())
+
+		if err := api.WriteJSON(w, http.StatusOK, untagged); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+func NewTagsHandler(l *slog.Logger, workouts Retreiver) http.Handler {
+	l = l.With("handler", "TagsHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		ts, err := workouts.Tags(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+		l.Debug("fetched user tags", "count", len(ts))
+
+		if err := api.WriteJSON(w, http.StatusOK, ts); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+func NewRenameTagHandler(l *slog.Logger, workouts Tagger) http.Handler {
+	l = l.With("handler", "RenameTagHandler")
+
+	type Request struct {
+		Name string `json:"name"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			tag      = r.PathValue("tag")
+		)
+
+		l := l.With("username", username, "tag", tag)
+
+		req, err := api.ReadJSON[Request](r)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		renamed, err := workouts.RenameTag(username, tag, req.Name)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("tag renamed", "name", renamed.Name)
+
+		if err := api.WriteJSON(w, http.StatusOK, renamed); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+func NewDeleteTagHandler(l *slog.Logger, workouts Tagger) http.Handler {
+	l = l.With("handler", "DeleteTagHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			username = r.PathValue("username")
+			tag      = r.PathValue("tag")
+		)
+
+		l := l.With("username", username, "tag", tag)
+
+		deleted, err := workouts.DeleteTag(username, tag)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+
+		l.Debug("tag deleted", "workouts", deleted.Workouts)
+
+		if err := api.WriteJSON(w, http.StatusOK, deleted); err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+	})
+}
+
+func NewFoldersHandler(l *slog.Logger, workouts Retreiver) http.Handler {
+	l = l.With("handler", "FoldersHandler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username := r.PathValue("username")
+
+		l := l.With("user", username)
+
+		fs, err := workouts.Folders(username)
+		if err != nil {
+			api.WriteInternalError(l, w, err, "")
+			return
+		}
+		l.Debug("fetched user folders", "count", len(fs))
+
+		if err := api.WriteJSON(w, http.StatusOK, fs); err != nil {
 			api.WriteInternalError(l, w, err, "")
 			return
 		}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/scrot/musclemem-api/internal/storage"
 )
@@ -95,7 +96,7 @@ func (ws *SQLWorkoutStore) Delete(owner string, workout int) (Workout, error) {
 		return Workout{}, fmt.Errorf("Delete: execute delete: %w", err)
 	}
 
-	wos, err := ws.ByOwner(owner)
+	wos, err := ws.ByOwner(owner, Query{})
 	if err != nil {
 		return Workout{}, fmt.Errorf("Delete: fetch workouts: %w", err)
 	}
@@ -129,7 +130,7 @@ func (ws *SQLWorkoutStore) Delete(owner string, workout int) (Workout, error) {
 
 func (ws *SQLWorkoutStore) ByID(owner string, workout int) (Workout, error) {
 	const stmt = `
-  SELECT owner, workout_index, name
+  SELECT owner, workout_index, name, author, folder
   FROM workouts
   WHERE owner = {{ .Owner }} AND workout_index = {{ .Workout }}
   `
@@ -149,28 +150,59 @@ func (ws *SQLWorkoutStore) ByID(owner string, workout int) (Workout, error) {
 	}
 
 	var w Workout
-	if err := ws.QueryRow(q, args...).Scan(&w.Owner, &w.Index, &w.Name); err != nil {
+	if err := ws.QueryRow(q, args...).Scan(&w.Owner, &w.Index, &w.Name, &w.Author, &w.Folder); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return Workout{}, fmt.Errorf("ByID: %w", ErrNotFound)
 		}
 		return Workout{}, fmt.Errorf("ByID: query: %w", err)
 	}
 
+	tags, err := ws.tags(owner)
+	if err != nil {
+		return Workout{}, fmt.Errorf("ByID: %w", err)
+	}
+	w.Tags = tags[w.Index]
+
 	return w, nil
 }
 
-func (ws *SQLWorkoutStore) ByOwner(owner string) ([]Workout, error) {
+func (ws *SQLWorkoutStore) ByOwner(owner string, query Query) ([]Workout, error) {
 	const stmt = `
-  SELECT owner, workout_index, name
-  FROM workouts
-  WHERE owner = {{ . }}
+  SELECT w.owner, w.workout_index, w.name, w.author, w.folder
+  FROM workouts w
+  WHERE w.owner = {{ .Owner }}
+    {{ if .Folder }}AND w.folder = {{ .Folder }}{{ end }}
+    {{ if .Tag }}
+    AND EXISTS (
+      SELECT 1
+      FROM workout_tags t
+      WHERE t.owner = w.owner AND t.workout = w.workout_index AND t.tag = {{ .Tag }}
+    )
+    {{ end }}
+  ORDER BY w.workout_index
   `
 
 	if owner == "" {
 		return []Workout{}, ErrInvalidFields
 	}
 
-	q, args, err := ws.CompileStatement(stmt, owner)
+	var err error
+	if query.Tag != "" {
+		if query.Tag, err = NormalizeTag(query.Tag); err != nil {
+			return []Workout{}, fmt.Errorf("ByOwner: %w", err)
+		}
+	}
+
+	if query.Folder, err = NormalizeFolder(query.Folder); err != nil {
+		return []Workout{}, fmt.Errorf("ByOwner: %w", err)
+	}
+
+	data := struct {
+		Owner string
+		Query
+	}{owner, query}
+
+	q, args, err := ws.CompileStatement(stmt, data)
 	if err != nil {
 		return []Workout{}, fmt.Errorf("ByOwner: compile: %w", err)
 	}
@@ -183,7 +215,8 @@ func (ws *SQLWorkoutStore) ByOwner(owner string) ([]Workout, error) {
 	var wos []Workout
 	for rows.Next() {
 		var w Workout
-		if err := rows.Scan(&w.Owner, &w.Index, &w.Name); err != nil {
+		if err := rows.Scan(&w.Owner, &w.Index, &w.Name, &w.Author, &w.Folder); err != nil {
+			rows.Close()
 			if errors.Is(err, sql.ErrNoRows) {
 				return []Workout{}, fmt.Errorf("ByOwner: query: %w", ErrNotFound)
 			}
@@ -191,10 +224,100 @@ func (ws *SQLWorkoutStore) ByOwner(owner string) ([]Workout, error) {
 		}
 		wos = append(wos, w)
 	}
+	rows.Close()
+
+	tags, err := ws.tags(owner)
+	if err != nil {
+		return []Workout{}, fmt.Errorf("ByOwner: %w", err)
+	}
+
+	for i, w := range wos {
+		wos[i].Tags = tags[w.Index]
+	}
 
 	return wos, nil
 }
 
+func (ws *SQLWorkoutStore) Tags(owner string) ([]Tag, error) {
+	const stmt = `
+  SELECT tag, COUNT(*)
+  FROM workout_tags
+  WHERE owner = {{ . }}
+  GROUP BY tag
+  ORDER BY tag
+  `
+
+	if owner == "" {
+		return []Tag{}, fmt.Errorf("Tags: %w", ErrInvalidFields)
+	}
+
+	q, args, err := ws.CompileStatement(stmt, owner)
+	if err != nil {
+		return []Tag{}, fmt.Errorf("Tags: compile: %w", err)
+	}
+
+	rows, err := ws.Query(q, args...)
+	if err != nil {
+		return []Tag{}, fmt.Errorf("Tags: query: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []Tag{}
+	for rows.Next() {
+		var t Tag
+		if err := rows.Scan(&t.Name, &t.Workouts); err != nil {
+			return []Tag{}, fmt.Errorf("Tags: scan: %w", err)
+		}
+		tags = append(tags, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []Tag{}, fmt.Errorf("Tags: rows: %w", err)
+	}
+
+	return tags, nil
+}
+
+func (ws *SQLWorkoutStore) Folders(owner string) ([]Folder, error) {
+	const stmt = `
+  SELECT folder, COUNT(*)
+  FROM workouts
+  WHERE owner = {{ . }} AND folder <> ''
+  GROUP BY folder
+  ORDER BY folder
+  `
+
+	if owner == "" {
+		return []Folder{}, fmt.Errorf("Folders: %w", ErrInvalidFields)
+	}
+
+	q, args, err := ws.CompileStatement(stmt, owner)
+	if err != nil {
+		return []Folder{}, fmt.Errorf("Folders: compile: %w", err)
+	}
+
+	rows, err := ws.Query(q, args...)
+	if err != nil {
+		return []Folder{}, fmt.Errorf("Folders: query: %w", err)
+	}
+	defer rows.Close()
+
+	folders := []Folder{}
+	for rows.Next() {
+		var f Folder
+		if err := rows.Scan(&f.Name, &f.Workouts); err != nil {
+			return []Folder{}, fmt.Errorf("Folders: scan: %w", err)
+		}
+		folders = append(folders, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []Folder{}, fmt.Errorf("Folders: rows: %w", err)
+	}
+
+	return folders, nil
+}
+
 func (ws *SQLWorkoutStore) ChangeName(owner string, workout int, name string) (Workout, error) {
 	const stmt = `
   UPDATE workouts
@@ -233,6 +356,301 @@ func (ws *SQLWorkoutStore) ChangeName(owner string, workout int, name string) (W
 	return w, nil
 }
 
+func (ws *SQLWorkoutStore) ChangeFolder(owner string, workout int, folder string) (Workout, error) {
+	const stmt = `
+  UPDATE workouts
+  SET folder = {{ .Folder }}
+  WHERE owner = {{ .Owner }} AND workout_index = {{ .Workout }}
+  `
+
+	if owner == "" || workout <= 0 {
+		return Workout{}, fmt.Errorf("ChangeFolder: %w", ErrInvalidFields)
+	}
+
+	folder, err := NormalizeFolder(folder)
+	if err != nil {
+		return Workout{}, fmt.Errorf("ChangeFolder: %w", err)
+	}
+
+	if _, err := ws.ByID(owner, workout); err != nil {
+		return Workout{}, fmt.Errorf("ChangeFolder: workout %s/%d: %w", owner, workout, err)
+	}
+
+	data := struct {
+		Owner   string
+		Workout int
+		Folder  string
+	}{owner, workout, folder}
+
+	q, args, err := ws.CompileStatement(stmt, data)
+	if err != nil {
+		return Workout{}, fmt.Errorf("ChangeFolder: compile: %w", err)
+	}
+
+	if _, err := ws.Exec(q, args...); err != nil {
+		return Workout{}, fmt.Errorf("ChangeFolder: execute: %w", err)
+	}
+
+	w, err := ws.ByID(owner, workout)
+	if err != nil {
+		return Workout{}, fmt.Errorf("ChangeFolder: fetch %s/%d: %w", owner, workout, err)
+	}
+
+	return w, nil
+}
+
+func (ws *SQLWorkoutStore) Tag(owner string, workout int, tag string) (Workout, error) {
+	const stmt = `
+  INSERT INTO workout_tags (owner, workout, tag)
+  VALUES ({{ .Owner }}, {{ .Workout }}, {{ .Tag }})
+  ON CONFLICT (owner, workout, tag) DO NOTHING
+  `
+
+	if owner == "" || workout <= 0 {
+		return Workout{}, fmt.Errorf("Tag: %w", ErrInvalidFields)
+	}
+
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return Workout{}, fmt.Errorf("Tag: %w", err)
+	}
+
+	if _, err := ws.ByID(owner, workout); err != nil {
+		return Workout{}, fmt.Errorf("Tag: workout %s/%d: %w", owner, workout, err)
+	}
+
+	data := struct {
+		Owner   string
+		Workout int
+		Tag     string
+	}{owner, workout, tag}
+
+	q, args, err := ws.CompileStatement(stmt, data)
+	if err != nil {
+		return Workout{}, fmt.Errorf("Tag: compile: %w", err)
+	}
+
+	if _, err := ws.Exec(q, args...); err != nil {
+		return Workout{}, fmt.Errorf("Tag: execute: %w", err)
+	}
+
+	w, err := ws.ByID(owner, workout)
+	if err != nil {
+		return Workout{}, fmt.Errorf("Tag: fetch %s/%d: %w", owner, workout, err)
+	}
+
+	return w, nil
+}
+
+func (ws *SQLWorkoutStore) Untag(owner string, workout int, tag string) (Workout, error) {
+	const stmt = `
+  DELETE FROM workout_tags
+  WHERE owner = {{ .Owner }} AND workout = {{ .Workout }} AND tag = {{ .Tag }}
+  `
+
+	if owner == "" || workout <= 0 {
+		return Workout{}, fmt.Errorf("Untag: %w", ErrInvalidFields)
+	}
+
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return Workout{}, fmt.Errorf("Untag: %w", err)
+	}
+
+	w, err := ws.ByID(owner, workout)
+	if err != nil {
+		return Workout{}, fmt.Errorf("Untag: workout %s/%d: %w", owner, workout, err)
+	}
+
+	if !slices.Contains(w.Tags, tag) {
+		return Workout{}, fmt.Errorf("Untag: tag %q of %s: %w", tag, w.Ref(), ErrNotFound)
+	}
+
+	data := struct {
+		Owner   string
+		Workout int
+		Tag     string
+	}{owner, workout, tag}
+
+	q, args, err := ws.CompileStatement(stmt, data)
+	if err != nil {
+		return Workout{}, fmt.Errorf("Untag: compile: %w", err)
+	}
+
+	if _, err := ws.Exec(q, args...); err != nil {
+		return Workout{}, fmt.Errorf("Untag: execute: %w", err)
+	}
+
+	w.Tags = slices.DeleteFunc(w.Tags, func(t string) bool { return t == tag })
+	return w, nil
+}
+
+func (ws *SQLWorkoutStore) RenameTag(owner string, tag string, name string) (Tag, error) {
+	const (
+		mergeStmt = `
+    INSERT INTO workout_tags (owner, workout, tag)
+    SELECT owner, workout, CAST({{ .Name }} AS TEXT)
+    FROM workout_tags
+    WHERE owner = {{ .Owner }} AND tag = {{ .Tag }}
+    ON CONFLICT (owner, workout, tag) DO NOTHING
+    `
+
+		deleteStmt = `
+    DELETE FROM workout_tags
+    WHERE owner = {{ .Owner }} AND tag = {{ .Tag }}
+    `
+	)
+
+	if owner == "" {
+		return Tag{}, fmt.Errorf("RenameTag: %w", ErrInvalidFields)
+	}
+
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return Tag{}, fmt.Errorf("RenameTag: %w", err)
+	}
+
+	name, err = NormalizeTag(name)
+	if err != nil {
+		return Tag{}, fmt.Errorf("RenameTag: %w", err)
+	}
+
+	if _, err := ws.tag(owner, tag); err != nil {
+		return Tag{}, fmt.Errorf("RenameTag: %w", err)
+	}
+
+	if tag != name {
+		data := struct {
+			Owner string
+			Tag   string
+			Name  string
+		}{owner, tag, name}
+
+		tx, err := ws.Begin()
+		if err != nil {
+			return Tag{}, fmt.Errorf("RenameTag: begin transaction: %w", err)
+		}
+
+		for _, stmt := range []string{mergeStmt, deleteStmt} {
+			q, args, err := ws.CompileStatement(stmt, data)
+			if err != nil {
+				tx.Rollback()
+				return Tag{}, fmt.Errorf("RenameTag: compile: %w", err)
+			}
+
+			if _, err := tx.Exec(q, args...); err != nil {
+				tx.Rollback()
+				return Tag{}, fmt.Errorf("RenameTag: execute: %w", err)
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return Tag{}, fmt.Errorf("RenameTag: commit transaction: %w", err)
+		}
+	}
+
+	renamed, err := ws.tag(owner, name)
+	if err != nil {
+		return Tag{}, fmt.Errorf("RenameTag: %w", err)
+	}
+
+	return renamed, nil
+}
+
+func (ws *SQLWorkoutStore) DeleteTag(owner string, tag string) (Tag, error) {
+	const stmt = `
+  DELETE FROM workout_tags
+  WHERE owner = {{ .Owner }} AND tag = {{ .Tag }}
+  `
+
+	if owner == "" {
+		return Tag{}, fmt.Errorf("DeleteTag: %w", ErrInvalidFields)
+	}
+
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return Tag{}, fmt.Errorf("DeleteTag: %w", err)
+	}
+
+	deleted, err := ws.tag(owner, tag)
+	if err != nil {
+		return Tag{}, fmt.Errorf("DeleteTag: %w", err)
+	}
+
+	data := struct {
+		Owner string
+		Tag   string
+	}{owner, tag}
+
+	q, args, err := ws.CompileStatement(stmt, data)
+	if err != nil {
+		return Tag{}, fmt.Errorf("DeleteTag: compile: %w", err)
+	}
+
+	if _, err := ws.Exec(q, args...); err != nil {
+		return Tag{}, fmt.Errorf("DeleteTag: execute: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// tag returns the tag of owner including the number of workouts
+// labeled, it returns an ErrNotFound error if no workout is labeled
+func (ws *SQLWorkoutStore) tag(owner string, tag string) (Tag, error) {
+	tags, err := ws.Tags(owner)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	for _, t := range tags {
+		if t.Name == tag {
+			return t, nil
+		}
+	}
+
+	return Tag{}, fmt.Errorf("tag %q of %s: %w", tag, owner, ErrNotFound)
+}
+
+// tags returns the tags of the workouts of owner by workout index
+func (ws *SQLWorkoutStore) tags(owner string) (map[int][]string, error) {
+	const stmt = `
+  SELECT workout, tag
+  FROM workout_tags
+  WHERE owner = {{ . }}
+  ORDER BY workout, tag
+  `
+
+	q, args, err := ws.CompileStatement(stmt, owner)
+	if err != nil {
+		return nil, fmt.Errorf("tags: compile: %w", err)
+	}
+
+	rows, err := ws.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("tags: query: %w", err)
+	}
+	defer rows.Close()
+
+	tags := make(map[int][]string)
+	for rows.Next() {
+		var (
+			workout int
+			tag     string
+		)
+
+		if err := rows.Scan(&workout, &tag); err != nil {
+			return nil, fmt.Errorf("tags: scan: %w", err)
+		}
+		tags[workout] = append(tags[workout], tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("tags: rows: %w", err)
+	}
+
+	return tags, nil
+}
+
 // lastIndex returns the last workout index of a user
 // if the index is 0 and no error, then there are no workouts
 func (xs *SQLWorkoutStore) lastIndex(owner string) (int, error) {
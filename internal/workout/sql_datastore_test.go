@@ -0,0 +1,114 @@
+package workout
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/scrot/musclemem-api/internal/storage"
+	"github.com/scrot/musclemem-api/internal/user"
+)
+
+func TestTags(t *testing.T) {
+	workouts, flush := mockWorkoutStore(t)
+	defer flush()
+
+	for _, name := range []string{"lower", "upper", "cardio"} {
+		if _, err := workouts.New("user", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, wt := range []struct {
+		workout int
+		tag     string
+	}{{1, "Strength"}, {2, "strength "}, {2, "gym"}, {3, "home"}} {
+		if _, err := workouts.Tag("user", wt.workout, wt.tag); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := workouts.Tag("user", 1, "a/b"); !errors.Is(err, ErrInvalidFields) {
+		t.Errorf("want error %v on tag with slash but got %v", ErrInvalidFields, err)
+	}
+
+	if _, err := workouts.ChangeFolder("user", 1, "/split/"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := workouts.ChangeFolder("user", 2, "split"); err != nil {
+		t.Fatal(err)
+	}
+
+	wos, err := workouts.ByOwner("user", Query{Tag: "STRENGTH", Folder: "split"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(wos) != 2 || wos[0].Name != "lower" || !slices.Equal(wos[1].Tags, []string{"gym", "strength"}) {
+		t.Errorf("want lower and upper tagged strength in split but got %+v", wos)
+	}
+
+	// renaming into an existing tag merges them
+	renamed, err := workouts.RenameTag("user", "gym", "strength")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if renamed.Name != "strength" || renamed.Workouts != 2 {
+		t.Errorf("want strength on 2 workouts but got %+v", renamed)
+	}
+
+	if _, err := workouts.DeleteTag("user", "gym"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("want error %v deleting renamed tag but got %v", ErrNotFound, err)
+	}
+
+	if _, err := workouts.Untag("user", 3, "home"); err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := workouts.Tags("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tags) != 1 || tags[0] != (Tag{"strength", 2}) {
+		t.Errorf("want only strength tag left but got %+v", tags)
+	}
+
+	folders, err := workouts.Folders("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(folders) != 1 || folders[0] != (Folder{"split", 2}) {
+		t.Errorf("want split folder with 2 workouts but got %+v", folders)
+	}
+}
+
+func mockWorkoutStore(t *testing.T) (*SQLWorkoutStore, func()) {
+	t.Helper()
+
+	config := storage.DatastoreConfig{
+		DatabaseURL:   "file://test.db?cache=shared&mode=memory",
+		MigrationPath: "migrations",
+		Overwrite:     false,
+	}
+
+	store, err := storage.NewSqlDatastore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := user.NewSQLUserStore(store).New("user", "test@gmail.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	flush := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal()
+		}
+	}
+
+	return NewSQLWorkoutStore(store), flush
+}